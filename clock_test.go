@@ -0,0 +1,364 @@
+package podlifecycle_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	podlifecycle "github.com/kroderdev/pod-lifecycle-go"
+)
+
+// fakeClock is a manually advanced podlifecycle.Clock. Timers and tickers
+// fire only from Advance, which runs AfterFunc callbacks before returning.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	at      time.Time
+	period  time.Duration // zero for one-shot After channels
+	ch      chan time.Time
+	fn      func() // set for AfterFunc timers
+	stopped bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	return c.add(d, 0).ch
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) podlifecycle.Ticker {
+	return fakeTicker{c: c, w: c.add(d, d)}
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) podlifecycle.Timer {
+	w := &fakeWaiter{fn: f, ch: make(chan time.Time, 1)}
+	t := fakeTimer{c: c, w: w}
+	t.Reset(d)
+	return t
+}
+
+func (c *fakeClock) add(d, period time.Duration) *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &fakeWaiter{at: c.now.Add(d), period: period, ch: make(chan time.Time, 1)}
+	if d <= 0 && period == 0 {
+		w.ch <- c.now
+		return w
+	}
+	c.waiters = append(c.waiters, w)
+	return w
+}
+
+// Advance moves the clock forward by d and fires every due timer and ticker.
+// Like time.Ticker, a ticker that falls behind drops ticks.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var due []func()
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.stopped {
+			continue
+		}
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		if w.fn != nil {
+			w.stopped = true
+			due = append(due, w.fn)
+			continue
+		}
+		select {
+		case w.ch <- c.now:
+		default:
+		}
+		if w.period > 0 {
+			for !w.at.After(c.now) {
+				w.at = w.at.Add(w.period)
+			}
+			pending = append(pending, w)
+		}
+	}
+	c.waiters = pending
+	c.mu.Unlock()
+	for _, f := range due {
+		f()
+	}
+}
+
+// fakeTimer is an AfterFunc timer on a fakeClock.
+type fakeTimer struct {
+	c *fakeClock
+	w *fakeWaiter
+}
+
+func (t fakeTimer) Stop() bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+	active := !t.w.stopped && slices.Contains(t.c.waiters, t.w)
+	t.w.stopped = true
+	return active
+}
+
+// Reset reschedules the timer d from now. Like time.AfterFunc, a zero or
+// negative d fires it right away, without waiting for Advance.
+func (t fakeTimer) Reset(d time.Duration) bool {
+	active := t.Stop()
+	if d <= 0 {
+		go t.w.fn()
+		return active
+	}
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+	t.c.waiters = slices.DeleteFunc(t.c.waiters, func(w *fakeWaiter) bool { return w == t.w })
+	t.w.at = t.c.now.Add(d)
+	t.w.stopped = false
+	t.c.waiters = append(t.c.waiters, t.w)
+	return active
+}
+
+type fakeTicker struct {
+	c *fakeClock
+	w *fakeWaiter
+}
+
+func (t fakeTicker) C() <-chan time.Time { return t.w.ch }
+
+func (t fakeTicker) Stop() {
+	t.c.mu.Lock()
+	t.w.stopped = true
+	t.c.mu.Unlock()
+}
+
+func TestWithClockDrivesMinUptime(t *testing.T) {
+	clock := newFakeClock()
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithMinUptime(time.Hour),
+		podlifecycle.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/ready", port)
+	if got := doGET(t, url); got != http.StatusServiceUnavailable {
+		t.Errorf("before min uptime: want 503, got %d", got)
+	}
+	if got := pm.Uptime(); got != 0 {
+		t.Errorf("Uptime before Advance: got %v, want 0", got)
+	}
+	clock.Advance(time.Hour)
+	if got := doGET(t, url); got != http.StatusOK {
+		t.Errorf("after min uptime: want 200, got %d", got)
+	}
+
+	cancel()
+	<-done
+	report, ok := pm.LastShutdownReport()
+	if !ok {
+		t.Fatal("no shutdown report")
+	}
+	if report.Duration != 0 {
+		t.Errorf("report duration on a frozen clock: got %v, want 0", report.Duration)
+	}
+}
+
+func TestWithClockDrivesShutdownBudget(t *testing.T) {
+	clock := newFakeClock()
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithShutdownTimeout(50*time.Millisecond),
+		// No /ready scrapes arrive, so shutdown waits out half the budget.
+		podlifecycle.WithConfirmNotReady(1),
+		podlifecycle.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	go pm.Shutdown()
+
+	select {
+	case <-pm.Done():
+		t.Fatal("shutdown finished on real time while the clock was frozen")
+	case <-time.After(200 * time.Millisecond):
+	}
+	for {
+		clock.Advance(50 * time.Millisecond)
+		select {
+		case <-pm.Done():
+		case <-time.After(20 * time.Millisecond):
+			continue
+		}
+		break
+	}
+	report, ok := pm.LastShutdownReport()
+	if !ok {
+		t.Fatal("no shutdown report")
+	}
+	if report.NotReadyConfirmed || report.Duration < 50*time.Millisecond {
+		t.Errorf("report: got %+v, want an unconfirmed shutdown lasting the 50ms budget", report)
+	}
+}
+
+func TestExtendShutdownOnClock(t *testing.T) {
+	clock := newFakeClock()
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithShutdownTimeout(10*time.Second),
+		podlifecycle.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := make(chan bool, 1), make(chan bool, 1)
+	advanced := make(chan struct{})
+	pm.RegisterConnCloser(func() {
+		first <- pm.ExtendShutdown(time.Minute)
+		<-advanced
+		second <- pm.ExtendShutdown(time.Second)
+	})
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	go pm.Shutdown()
+
+	if !<-first {
+		t.Fatal("ExtendShutdown during shutdown: got false, want true")
+	}
+	// Past the original 10s budget but well inside the extended one.
+	clock.Advance(30 * time.Second)
+	close(advanced)
+	if !<-second {
+		t.Error("ExtendShutdown 30s into a 70s budget: got false, want true")
+	}
+	<-pm.Done()
+}
+
+func TestWithClockNil(t *testing.T) {
+	if _, err := podlifecycle.NewPodManager(podlifecycle.WithClock(nil)); !errors.Is(err, podlifecycle.ErrInvalidOption) {
+		t.Errorf("nil clock: got %v, want ErrInvalidOption", err)
+	}
+}
+
+func TestReadyDurationAdvancesAndResets(t *testing.T) {
+	clock := newFakeClock()
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+
+	clock.Advance(time.Minute)
+	if got := pm.ReadyDuration(); got != 0 {
+		t.Errorf("before SetReady: got %v, want 0", got)
+	}
+	pm.SetReady()
+	clock.Advance(30 * time.Second)
+	if got := pm.ReadyDuration(); got != 30*time.Second {
+		t.Errorf("ready for 30s: got %v", got)
+	}
+	if got := pm.Uptime(); got != 90*time.Second {
+		t.Errorf("Uptime: got %v, want 1m30s", got)
+	}
+	pm.SetReady() // already ready: keeps the original timestamp
+	clock.Advance(10 * time.Second)
+	if got := pm.ReadyDuration(); got != 40*time.Second {
+		t.Errorf("after repeated SetReady: got %v, want 40s", got)
+	}
+	if st := pm.Status(); st.ReadyFor != "40s" {
+		t.Errorf("Status.ReadyFor: got %q, want 40s", st.ReadyFor)
+	}
+
+	pm.SetNotReady()
+	if got := pm.ReadyDuration(); got != 0 {
+		t.Errorf("after SetNotReady: got %v, want 0", got)
+	}
+	clock.Advance(time.Minute)
+	pm.SetReady()
+	clock.Advance(5 * time.Second)
+	if got := pm.ReadyDuration(); got != 5*time.Second {
+		t.Errorf("ready again: got %v, want 5s", got)
+	}
+}
+
+func TestLivenessDrainDelay(t *testing.T) {
+	clock := newFakeClock()
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC),
+		podlifecycle.WithGRPCPort(freePort(t)),
+		podlifecycle.WithLivenessDrainDelay(10*time.Second),
+		podlifecycle.WithShutdownTimeout(time.Minute),
+		podlifecycle.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	pm.SetReady()
+
+	go pm.Shutdown()
+	deadline := time.Now().Add(2 * time.Second)
+	for pm.GRPCStatuses()["ready"] != "NOT_SERVING" {
+		if time.Now().After(deadline) {
+			t.Fatal("readiness did not fail after shutdown began")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := pm.GRPCStatuses()["live"]; got != "SERVING" {
+		t.Errorf("live while readiness drains: got %q, want SERVING", got)
+	}
+	clock.Advance(5 * time.Second)
+	time.Sleep(50 * time.Millisecond)
+	if got := pm.GRPCStatuses()["live"]; got != "SERVING" {
+		t.Errorf("live before the delay: got %q, want SERVING", got)
+	}
+
+	for {
+		clock.Advance(5 * time.Second)
+		select {
+		case <-pm.Done():
+		case <-time.After(20 * time.Millisecond):
+			continue
+		}
+		break
+	}
+	if got := pm.GRPCStatuses()["live"]; got != "NOT_SERVING" {
+		t.Errorf("live after the delay: got %q, want NOT_SERVING", got)
+	}
+}
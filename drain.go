@@ -0,0 +1,124 @@
+package podlifecycle
+
+import (
+	"context"
+	"time"
+)
+
+// BeginDrain starts a soft shutdown: /ready (and gRPC readiness) fails from
+// now on and the hooks registered with RegisterDrainHook run, in registration
+// order, in the calling goroutine. The probe server keeps serving and /live
+// stays green until the full shutdown, which a shutdown signal or Shutdown
+// still performs. Calls while already draining have no effect; SetReady does
+// not undo a drain, CancelDrain does.
+func (pm *PodManager) BeginDrain() {
+	pm.drainMu.Lock()
+	if pm.draining.Load() || pm.drainStarting {
+		pm.drainMu.Unlock()
+		return
+	}
+	pm.drainStarting = true
+	pm.drainMu.Unlock()
+	// Pre-drain hooks may be slow (service discovery calls), so they run
+	// outside drainMu and do not hold up CancelDrain.
+	pm.runPreDrainHooks()
+	pm.drainMu.Lock()
+	pm.drainStarting = false
+	pm.transition("draining", &pm.draining, true)
+	pm.syncProbe()
+	pm.drainMu.Unlock()
+	pm.hooksMu.Lock()
+	hooks := pm.drainHooks
+	pm.hooksMu.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// CancelDrain ends a drain begun with BeginDrain, e.g. when a rollout is
+// aborted, so the pod reports ready again once WithRecoveryHoldDown has
+// elapsed. It reports whether a drain was cancelled, and has no effect once
+// shutdown has begun or while BeginDrain is still running pre-drain hooks.
+// Drain hooks are not undone; a later BeginDrain runs them again. Pre-drain
+// hooks are re-armed, so the next BeginDrain or shutdown runs them again, but
+// not undone either: re-register with service discovery once CancelDrain
+// returns true.
+func (pm *PodManager) CancelDrain() bool {
+	pm.drainMu.Lock()
+	if !pm.draining.Load() || pm.shuttingDown.Load() {
+		pm.drainMu.Unlock()
+		return false
+	}
+	pm.drainEndedAt.Store(pm.clock.Now().UnixNano())
+	pm.transition("draining", &pm.draining, false)
+	pm.syncProbe()
+	pm.preDrainMu.Lock()
+	pm.preDrainRan = false
+	pm.preDrainMu.Unlock()
+	pm.drainMu.Unlock()
+	if d := pm.recoveryHoldDown; d > 0 {
+		// Push-based probes (gRPC) need a nudge once the hold-down ends.
+		pm.goBackground(func(ctx context.Context) {
+			select {
+			case <-pm.clock.After(d):
+				pm.syncProbe()
+			case <-ctx.Done():
+			}
+		})
+	}
+	return true
+}
+
+// inRecoveryHoldDown reports whether a cancelled drain ended less than
+// WithRecoveryHoldDown ago.
+func (pm *PodManager) inRecoveryHoldDown() bool {
+	ns := pm.drainEndedAt.Load()
+	return ns != 0 && pm.clock.Now().Sub(time.Unix(0, ns)) < pm.recoveryHoldDown
+}
+
+// IsDraining reports whether a drain begun with BeginDrain is in progress.
+func (pm *PodManager) IsDraining() bool { return pm.draining.Load() }
+
+// RegisterDrainHook registers fn to run when BeginDrain is called, e.g. to
+// stop consuming from a queue. Hooks registered after the drain began do not
+// run.
+func (pm *PodManager) RegisterDrainHook(fn func()) {
+	pm.hooksMu.Lock()
+	pm.drainHooks = append(pm.drainHooks, fn)
+	pm.hooksMu.Unlock()
+}
+
+// RegisterPreDrainHook registers fn to run before the pod goes not-ready:
+// at the start of shutdown, or of BeginDrain if that comes first. Hooks run
+// once, and again only after CancelDrain re-arms them. Use it to deregister
+// from service discovery (Consul, etcd) so no new clients resolve the pod
+// while it still passes readiness.
+//
+// Shutdown phases run in this order: pre-drain hooks, the not-ready flip,
+// drain hooks (BeginDrain only), WithConfirmNotReady, connection closers,
+// and the probe server stop. Pre-drain hooks run in registration order in
+// the shutting-down goroutine, before the shutdown timeout starts, so they
+// should return promptly.
+func (pm *PodManager) RegisterPreDrainHook(fn func()) {
+	pm.hooksMu.Lock()
+	pm.preDrainHooks = append(pm.preDrainHooks, fn)
+	pm.hooksMu.Unlock()
+}
+
+// runPreDrainHooks runs the pre-drain hooks unless they already ran since
+// the last CancelDrain. A concurrent caller waits for a run in progress, so
+// shutdown never goes not-ready before the hooks have finished.
+func (pm *PodManager) runPreDrainHooks() {
+	pm.preDrainMu.Lock()
+	defer pm.preDrainMu.Unlock()
+	if pm.preDrainRan {
+		return
+	}
+	pm.preDrainRan = true
+	pm.hooksMu.Lock()
+	hooks := pm.preDrainHooks
+	pm.hooksMu.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
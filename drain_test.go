@@ -0,0 +1,219 @@
+package podlifecycle_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	podlifecycle "github.com/kroderdev/pod-lifecycle-go"
+)
+
+func TestBeginDrainFailsReadyKeepsLive(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hookCalls int
+	pm.RegisterDrainHook(func() { hookCalls++ })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+	if got := doGET(t, base+"/ready"); got != http.StatusOK {
+		t.Fatalf("/ready before drain: want 200, got %d", got)
+	}
+
+	pm.BeginDrain()
+	pm.BeginDrain()
+	if !pm.IsDraining() {
+		t.Error("IsDraining() should be true after BeginDrain")
+	}
+	if pm.IsShuttingDown() {
+		t.Error("IsShuttingDown() should stay false while draining")
+	}
+	if hookCalls != 1 {
+		t.Errorf("drain hook calls: got %d, want 1", hookCalls)
+	}
+	if got := doGET(t, base+"/ready"); got != http.StatusServiceUnavailable {
+		t.Errorf("/ready while draining: want 503, got %d", got)
+	}
+	if got := doGET(t, base+"/live"); got != http.StatusOK {
+		t.Errorf("/live while draining: want 200, got %d", got)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StartContext did not return after cancel")
+	}
+	if !pm.IsShuttingDown() {
+		t.Error("IsShuttingDown() should be true after full shutdown")
+	}
+}
+
+func TestPreDrainHookRunsBeforeNotReady(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+	var order []string
+	pm.RegisterPreDrainHook(func() {
+		order = append(order, fmt.Sprintf("pre-drain /ready %d", doGET(t, base+"/ready")))
+	})
+	pm.RegisterPreDrainHook(func() { order = append(order, "pre-drain 2") })
+	pm.RegisterConnCloser(func() {
+		order = append(order, fmt.Sprintf("closer /ready %d", doGET(t, base+"/ready")))
+	})
+
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	pm.SetReady()
+	pm.Shutdown()
+	pm.BeginDrain() // pre-drain hooks already ran
+
+	want := []string{"pre-drain /ready 200", "pre-drain 2", "closer /ready 503"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("order: got %q, want %q", order, want)
+	}
+}
+
+func TestCancelDrainRecoveryHoldDown(t *testing.T) {
+	clock := newFakeClock()
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithClock(clock),
+		podlifecycle.WithRecoveryHoldDown(10*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+	pm.SetReady()
+	url := fmt.Sprintf("http://127.0.0.1:%d/ready", port)
+
+	if pm.CancelDrain() {
+		t.Error("CancelDrain without a drain: got true, want false")
+	}
+	pm.BeginDrain()
+	if !pm.CancelDrain() {
+		t.Fatal("CancelDrain while draining: got false, want true")
+	}
+	if pm.IsDraining() {
+		t.Error("IsDraining after CancelDrain: got true")
+	}
+	if got := doGET(t, url); got != http.StatusServiceUnavailable {
+		t.Errorf("/ready during hold-down: want 503, got %d", got)
+	}
+	clock.Advance(9 * time.Second)
+	if got := doGET(t, url); got != http.StatusServiceUnavailable {
+		t.Errorf("/ready 9s into hold-down: want 503, got %d", got)
+	}
+	clock.Advance(time.Second)
+	if got := doGET(t, url); got != http.StatusOK {
+		t.Errorf("/ready after hold-down: want 200, got %d", got)
+	}
+
+	pm.Shutdown()
+	if pm.CancelDrain() {
+		t.Error("CancelDrain after shutdown: got true, want false")
+	}
+}
+
+func TestCancelDrainWithoutHoldDown(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hookCalls int
+	pm.RegisterDrainHook(func() { hookCalls++ })
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+	pm.SetReady()
+	pm.BeginDrain()
+	pm.CancelDrain()
+	if !pm.Status().Ready {
+		t.Error("ready right after CancelDrain without hold-down: got false")
+	}
+	pm.BeginDrain()
+	if hookCalls != 2 {
+		t.Errorf("drain hook calls after a second drain: got %d, want 2", hookCalls)
+	}
+}
+
+func TestSlowPreDrainHookDoesNotBlockDrainCalls(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+	entered, release := make(chan struct{}), make(chan struct{})
+	pm.RegisterPreDrainHook(func() {
+		close(entered)
+		<-release
+	})
+	drained := make(chan struct{})
+	go func() {
+		pm.BeginDrain()
+		close(drained)
+	}()
+	<-entered
+
+	calls := make(chan bool)
+	go func() {
+		pm.BeginDrain()
+		calls <- pm.CancelDrain()
+	}()
+	select {
+	case cancelled := <-calls:
+		if cancelled {
+			t.Error("CancelDrain during pre-drain hooks: got true, want false")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("BeginDrain and CancelDrain blocked on a running pre-drain hook")
+	}
+
+	close(release)
+	<-drained
+	if !pm.IsDraining() {
+		t.Error("IsDraining after the pre-drain hook returned: got false")
+	}
+}
+
+func TestCancelDrainRearmsPreDrainHooks(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls int
+	pm.RegisterPreDrainHook(func() { calls++ })
+
+	pm.BeginDrain()
+	pm.BeginDrain()
+	if calls != 1 {
+		t.Fatalf("pre-drain hook calls while draining: got %d, want 1", calls)
+	}
+	if !pm.CancelDrain() {
+		t.Fatal("CancelDrain while draining: got false, want true")
+	}
+	pm.Shutdown()
+	if calls != 2 {
+		t.Errorf("pre-drain hook calls after CancelDrain and shutdown: got %d, want 2", calls)
+	}
+}
@@ -0,0 +1,73 @@
+package podlifecycle
+
+import "time"
+
+// LifecycleEventType names a lifecycle event delivered on Events.
+type LifecycleEventType string
+
+// Lifecycle event types.
+const (
+	EventStarted          LifecycleEventType = "started"          // the probe server is serving
+	EventReady            LifecycleEventType = "ready"            // SetReady turned readiness on
+	EventNotReady         LifecycleEventType = "notReady"         // SetNotReady turned it off
+	EventDrainBegan       LifecycleEventType = "drainBegan"       // BeginDrain was called
+	EventShutdownBegan    LifecycleEventType = "shutdownBegan"    // shutdown has begun
+	EventShutdownComplete LifecycleEventType = "shutdownComplete" // shutdown has finished
+)
+
+// LifecycleEvent is a lifecycle change delivered on Events. It marshals to
+// JSON as {"type": ..., "time": ...}, ready to forward to an event bus.
+type LifecycleEvent struct {
+	Type LifecycleEventType `json:"type"`
+	Time time.Time          `json:"time"`
+}
+
+// eventBuffer is the capacity of the Events channel.
+const eventBuffer = 64
+
+// Events returns a channel of lifecycle events, closed after
+// EventShutdownComplete. The channel holds up to 64 events; when it is full,
+// further events are dropped rather than blocking the lifecycle, and counted
+// by DroppedEvents. Consumers that must not miss events should receive
+// promptly from a dedicated goroutine.
+func (pm *PodManager) Events() <-chan LifecycleEvent { return pm.events }
+
+// DroppedEvents returns how many lifecycle events were dropped because the
+// Events channel was full.
+func (pm *PodManager) DroppedEvents() uint64 { return pm.droppedEvents.Load() }
+
+// emit delivers an event without blocking, counting it as dropped when the
+// channel is full. Events after shutdown completed are discarded.
+func (pm *PodManager) emit(typ LifecycleEventType, ts time.Time) {
+	pm.eventsMu.Lock()
+	defer pm.eventsMu.Unlock()
+	if pm.eventsClosed {
+		return
+	}
+	select {
+	case pm.events <- LifecycleEvent{Type: typ, Time: ts}:
+	default:
+		pm.droppedEvents.Add(1)
+	}
+	if typ == EventShutdownComplete {
+		pm.eventsClosed = true
+		close(pm.events)
+	}
+}
+
+// eventFor maps a state flag transition to its lifecycle event, if any.
+func eventFor(field string, v bool) (LifecycleEventType, bool) {
+	switch {
+	case field == "ready" && v:
+		return EventReady, true
+	case field == "ready":
+		return EventNotReady, true
+	case field == "started" && v:
+		return EventStarted, true
+	case field == "draining" && v:
+		return EventDrainBegan, true
+	case field == "shuttingDown" && v:
+		return EventShutdownBegan, true
+	}
+	return "", false
+}
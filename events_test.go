@@ -0,0 +1,79 @@
+package podlifecycle_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	podlifecycle "github.com/kroderdev/pod-lifecycle-go"
+)
+
+func TestEventsFullLifecycle(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	pm.SetReady()
+	pm.SetNotReady()
+	pm.SetReady()
+	pm.BeginDrain()
+	pm.Shutdown()
+	pm.SetReady() // after shutdown: discarded
+
+	var got []string
+	timeout := time.After(2 * time.Second)
+	for done := false; !done; {
+		select {
+		case ev, ok := <-pm.Events():
+			if !ok {
+				done = true
+				break
+			}
+			if ev.Time.IsZero() {
+				t.Errorf("%s: zero timestamp", ev.Type)
+			}
+			got = append(got, string(ev.Type))
+		case <-timeout:
+			t.Fatalf("Events not closed after shutdown; got %v", got)
+		}
+	}
+	want := "started ready notReady ready drainBegan shutdownBegan shutdownComplete"
+	if strings.Join(got, " ") != want {
+		t.Errorf("events: got %v, want %s", got, want)
+	}
+	if n := pm.DroppedEvents(); n != 0 {
+		t.Errorf("DroppedEvents: got %d, want 0", n)
+	}
+
+	b, err := json.Marshal(podlifecycle.LifecycleEvent{Type: podlifecycle.EventReady})
+	if err != nil || !strings.Contains(string(b), `"type":"ready"`) {
+		t.Errorf("JSON: got %s (%v)", b, err)
+	}
+}
+
+func TestEventsDropWhenFull(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			pm.SetReady()
+			pm.SetNotReady()
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("lifecycle blocked on an unread Events channel")
+	}
+	if n := pm.DroppedEvents(); n != 100-64 {
+		t.Errorf("DroppedEvents: got %d, want %d", n, 100-64)
+	}
+}
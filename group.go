@@ -0,0 +1,71 @@
+package podlifecycle
+
+import (
+	"net/http"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+// Group combines several PodManagers hosted in one process, e.g. logical
+// services in a monolith. It is ready only when every member is ready, and
+// reports shutting down as soon as any member is. Group implements the same
+// state interface the probes read, so Handler serves the combined view.
+type Group struct {
+	members []*PodManager
+}
+
+// NewGroup returns a Group over members.
+func NewGroup(members ...*PodManager) *Group {
+	return &Group{members: members}
+}
+
+// Ready reports whether every member is ready, including each member's
+// min-uptime and started gates.
+func (g *Group) Ready() bool {
+	for _, pm := range g.members {
+		if !pm.probeReady() {
+			return false
+		}
+	}
+	return true
+}
+
+// ShuttingDown reports whether any member is shutting down.
+func (g *Group) ShuttingDown() bool {
+	for _, pm := range g.members {
+		if pm.shuttingDown.Load() {
+			return true
+		}
+	}
+	return false
+}
+
+// Started reports whether every member has started.
+func (g *Group) Started() bool {
+	for _, pm := range g.members {
+		if !pm.started.Load() {
+			return false
+		}
+	}
+	return true
+}
+
+// Live reports whether every member is live.
+func (g *Group) Live() bool {
+	for _, pm := range g.members {
+		if pm.workerFailed.Load() {
+			return false
+		}
+	}
+	return true
+}
+
+// Handler returns an http.Handler serving /ready, /live, and /startup for the
+// combined state. Members' dependency checkers are not run; each member's own
+// probe still runs them.
+func (g *Group) Handler() http.Handler {
+	mux := http.NewServeMux()
+	// The existing-mux probe only registers handlers; Start cannot fail.
+	_ = check.NewExistingHTTPProbe(mux, check.HTTPOptions{}).Start(g, func() {})
+	return mux
+}
@@ -0,0 +1,42 @@
+package podlifecycle_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	podlifecycle "github.com/kroderdev/pod-lifecycle-go"
+)
+
+func TestGroupReadyRequiresAllMembers(t *testing.T) {
+	var members []*podlifecycle.PodManager
+	for i := 0; i < 2; i++ {
+		pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go pm.StartContext(ctx) //nolint:errcheck
+		members = append(members, pm)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	srv := httptest.NewServer(podlifecycle.NewGroup(members...).Handler())
+	defer srv.Close()
+
+	members[0].SetReady()
+	if code := doGET(t, srv.URL+"/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("one of two ready: got %d, want 503", code)
+	}
+	members[1].SetReady()
+	if code := doGET(t, srv.URL+"/ready"); code != http.StatusOK {
+		t.Errorf("both ready: got %d, want 200", code)
+	}
+	members[0].Shutdown()
+	if code := doGET(t, srv.URL+"/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("one shutting down: got %d, want 503", code)
+	}
+}
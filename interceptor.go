@@ -8,7 +8,10 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/config"
 )
 
 const healthServicePrefix = "/grpc.health.v1.Health/"
@@ -30,25 +33,103 @@ func LoggingUnaryInterceptor(log *slog.Logger) grpc.UnaryServerInterceptor {
 
 		start := time.Now()
 		resp, err := handler(ctx, req)
-		duration := time.Since(start)
+		logGRPCRequest(log, info.FullMethod, time.Since(start), err)
+		return resp, err
+	}
+}
 
-		code := status.Code(err)
-		log.Info("grpc request",
-			"method", info.FullMethod,
+func logGRPCRequest(log *slog.Logger, method string, duration time.Duration, err error) {
+	code := status.Code(err)
+	log.Info("grpc request",
+		"method", method,
+		"code", code.String(),
+		"duration", duration,
+	)
+	if err != nil {
+		log.Warn("grpc request error",
+			"method", method,
 			"code", code.String(),
-			"duration", duration,
+			"err", err,
 		)
+	}
+}
+
+// GRPCMetricsRecorder receives per-RPC metrics from ObservabilityUnaryInterceptor.
+// Implement it on top of the metrics library of your choice; implementations
+// must be safe for concurrent use.
+type GRPCMetricsRecorder interface {
+	// IncRequest counts a completed RPC.
+	IncRequest(method, code string)
+	// IncError counts an RPC that returned a non-nil error.
+	IncError(method, code string)
+	// ObserveDuration records the RPC latency, e.g. into a histogram.
+	ObserveDuration(method string, d time.Duration)
+}
+
+// ObservabilityUnaryInterceptor returns a gRPC unary server interceptor that
+// logs each request like LoggingUnaryInterceptor and records it on metrics in
+// the same pass. Health check requests are neither logged nor recorded.
+func ObservabilityUnaryInterceptor(log *slog.Logger, metrics GRPCMetricsRecorder) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		duration := time.Since(start)
+
+		logGRPCRequest(log, info.FullMethod, duration, err)
+		code := status.Code(err).String()
+		metrics.IncRequest(info.FullMethod, code)
 		if err != nil {
-			log.Warn("grpc request error",
-				"method", info.FullMethod,
-				"code", code.String(),
-				"err", err,
-			)
+			metrics.IncError(info.FullMethod, code)
 		}
+		metrics.ObserveDuration(info.FullMethod, duration)
 		return resp, err
 	}
 }
 
+// DrainingUnaryInterceptor returns a gRPC unary server interceptor that rejects
+// new RPCs with codes.Unavailable once pm is shutting down, so clients that do
+// not watch health fail fast and retry elsewhere. RPCs already in flight are
+// unaffected, and health check requests are always let through.
+func DrainingUnaryInterceptor(pm *PodManager) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if pm.IsShuttingDown() && !strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return nil, errDraining
+		}
+		return handler(ctx, req)
+	}
+}
+
+// DrainingStreamInterceptor is the streaming counterpart of DrainingUnaryInterceptor.
+func DrainingStreamInterceptor(pm *PodManager) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if pm.IsShuttingDown() && !strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return errDraining
+		}
+		return handler(srv, ss)
+	}
+}
+
+var errDraining = status.Error(codes.Unavailable, "server draining")
+
 // ---------------------------------------------------------------------------
 // HTTP Middleware
 // ---------------------------------------------------------------------------
@@ -91,3 +172,36 @@ func LoggingHTTPMiddleware(log *slog.Logger) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// DrainMiddleware returns an http.Handler middleware that answers new requests
+// with 503, Retry-After, and Connection: close once pm is shutting down, so
+// clients fail fast and reconnect elsewhere. Requests already in flight are
+// unaffected, and the probe endpoints (/ready, /live, /startup, under the
+// WithProbePathPrefix prefix, and the ping, status, and metrics paths) are
+// always passed through. It is the HTTP counterpart of
+// DrainingUnaryInterceptor.
+func DrainMiddleware(pm *PodManager) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if pm.IsShuttingDown() && !pm.probePaths[r.URL.Path] {
+				w.Header().Set("Retry-After", "1")
+				w.Header().Set("Connection", "close")
+				http.Error(w, "server draining", http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// probePaths returns the probe endpoint paths cfg registers, keyed for
+// lookup by DrainMiddleware.
+func probePaths(cfg config.Config) map[string]bool {
+	paths := make(map[string]bool)
+	for _, p := range []string{"/ready", "/live", "/startup", cfg.PingPath, cfg.StatusPath, cfg.MetricsPath} {
+		if p != "" {
+			paths[cfg.ProbePathPrefix+p] = true
+		}
+	}
+	return paths
+}
@@ -5,10 +5,16 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestLoggingUnaryInterceptor(t *testing.T) {
@@ -65,3 +71,158 @@ func TestLoggingUnaryInterceptor(t *testing.T) {
 		}
 	})
 }
+
+type fakeRecorder struct {
+	mu        sync.Mutex
+	requests  map[string]int
+	errors    map[string]int
+	durations []time.Duration
+}
+
+func newFakeRecorder() *fakeRecorder {
+	return &fakeRecorder{requests: map[string]int{}, errors: map[string]int{}}
+}
+
+func (f *fakeRecorder) IncRequest(method, code string) {
+	f.mu.Lock()
+	f.requests[method+" "+code]++
+	f.mu.Unlock()
+}
+
+func (f *fakeRecorder) IncError(method, code string) {
+	f.mu.Lock()
+	f.errors[method+" "+code]++
+	f.mu.Unlock()
+}
+
+func (f *fakeRecorder) ObserveDuration(_ string, d time.Duration) {
+	f.mu.Lock()
+	f.durations = append(f.durations, d)
+	f.mu.Unlock()
+}
+
+func TestObservabilityUnaryInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	rec := newFakeRecorder()
+	interceptor := ObservabilityUnaryInterceptor(logger, rec)
+
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return "resp", nil }
+	fail := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, errors.New("boom") }
+
+	_, _ = interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: "/svc/Ok"}, ok)
+	_, _ = interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: "/svc/Fail"}, fail)
+	_, _ = interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, ok)
+
+	out := buf.String()
+	if !strings.Contains(out, "method=/svc/Ok") || !strings.Contains(out, "err=boom") {
+		t.Errorf("expected request and error logs, got %s", out)
+	}
+	if strings.Contains(out, "grpc.health.v1") {
+		t.Errorf("health check should not be logged, got %s", out)
+	}
+	if rec.requests["/svc/Ok OK"] != 1 || rec.requests["/svc/Fail Unknown"] != 1 {
+		t.Errorf("requests: got %v", rec.requests)
+	}
+	if len(rec.errors) != 1 || rec.errors["/svc/Fail Unknown"] != 1 {
+		t.Errorf("errors: got %v", rec.errors)
+	}
+	if len(rec.durations) != 2 {
+		t.Errorf("durations: want 2 observations (health skipped), got %d", len(rec.durations))
+	}
+}
+
+func TestDrainingInterceptors(t *testing.T) {
+	pm, err := NewPodManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unary := DrainingUnaryInterceptor(pm)
+	stream := DrainingStreamInterceptor(pm)
+	okUnary := func(ctx context.Context, req interface{}) (interface{}, error) { return "resp", nil }
+	okStream := func(srv interface{}, ss grpc.ServerStream) error { return nil }
+	app := &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}
+	health := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	appStream := &grpc.StreamServerInfo{FullMethod: "/svc/Stream"}
+
+	if _, err := unary(context.Background(), "req", app, okUnary); err != nil {
+		t.Errorf("unary before shutdown: want nil, got %v", err)
+	}
+	if err := stream(nil, nil, appStream, okStream); err != nil {
+		t.Errorf("stream before shutdown: want nil, got %v", err)
+	}
+
+	pm.Shutdown()
+
+	if _, err := unary(context.Background(), "req", app, okUnary); status.Code(err) != codes.Unavailable {
+		t.Errorf("unary after shutdown: want Unavailable, got %v", err)
+	}
+	if err := stream(nil, nil, appStream, okStream); status.Code(err) != codes.Unavailable {
+		t.Errorf("stream after shutdown: want Unavailable, got %v", err)
+	}
+	if _, err := unary(context.Background(), "req", health, okUnary); err != nil {
+		t.Errorf("health after shutdown: want nil, got %v", err)
+	}
+}
+
+func TestDrainMiddleware(t *testing.T) {
+	pm, err := NewPodManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := DrainMiddleware(pm)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	serve := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if rec := serve("/api"); rec.Code != http.StatusTeapot {
+		t.Errorf("before shutdown: got %d, want 418", rec.Code)
+	}
+
+	pm.Shutdown()
+
+	rec := serve("/api")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("after shutdown: got %d, want 503", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("after shutdown: missing Retry-After")
+	}
+	if rec := serve("/ready"); rec.Code != http.StatusTeapot {
+		t.Errorf("probe path after shutdown: got %d, want passthrough", rec.Code)
+	}
+}
+
+func TestDrainMiddlewareProbePathPrefix(t *testing.T) {
+	pm, err := NewPodManager(
+		WithExistingHTTPMux(http.NewServeMux()),
+		WithProbePathPrefix("/internal"),
+		WithPingEndpoint("/ping"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := DrainMiddleware(pm)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	pm.Shutdown()
+
+	for path, want := range map[string]int{
+		"/internal/live":    http.StatusTeapot,
+		"/internal/ready":   http.StatusTeapot,
+		"/internal/startup": http.StatusTeapot,
+		"/internal/ping":    http.StatusTeapot,
+		"/live":             http.StatusServiceUnavailable,
+		"/api":              http.StatusServiceUnavailable,
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s after shutdown: got %d, want %d", path, rec.Code, want)
+		}
+	}
+}
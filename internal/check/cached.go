@@ -0,0 +1,75 @@
+package check
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type cachedChecker struct {
+	checker Checker
+	ttl     time.Duration
+
+	mu       sync.Mutex
+	err      error
+	at       time.Time     // zero until a result is cached
+	gen      uint64        // incremented each time a result is cached
+	inflight chan struct{} // closed when the running refresh finishes
+}
+
+// Cached returns a Checker that serves c's last result for ttl and refreshes
+// it on demand afterwards, so c runs at most once per ttl no matter how often
+// /ready is scraped. Concurrent callers share one refresh. A refresh cut
+// short by its caller's context is neither cached nor shared: callers
+// waiting on it retry. Refreshes only happen when the checker is called, so
+// like any checker it does no work while /ready is short-circuited (before
+// SetReady or during shutdown). The TTL is measured on the clock set with
+// WithClock when the manager runs the checker.
+func Cached(c Checker, ttl time.Duration) Checker {
+	return &cachedChecker{checker: c, ttl: ttl}
+}
+
+func (c *cachedChecker) Check(ctx context.Context) error {
+	clock := clockFrom(ctx)
+	c.mu.Lock()
+	for {
+		if !c.at.IsZero() && clock.Now().Sub(c.at) < c.ttl {
+			err := c.err
+			c.mu.Unlock()
+			return err
+		}
+		ch := c.inflight
+		if ch == nil {
+			break
+		}
+		gen := c.gen
+		c.mu.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		c.mu.Lock()
+		if c.gen != gen {
+			err := c.err
+			c.mu.Unlock()
+			return err
+		}
+	}
+	ch := make(chan struct{})
+	c.inflight = ch
+	c.mu.Unlock()
+
+	err := c.checker.Check(ctx)
+
+	c.mu.Lock()
+	if ctx.Err() == nil {
+		c.err = err
+		c.at = clock.Now()
+		c.gen++
+	}
+	c.inflight = nil
+	c.mu.Unlock()
+	close(ch)
+	return err
+}
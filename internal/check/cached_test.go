@@ -0,0 +1,140 @@
+package check_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+type countingChecker struct{ calls atomic.Int32 }
+
+func (c *countingChecker) Check(_ context.Context) error {
+	c.calls.Add(1)
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+func TestCachedCallsAtMostOncePerTTL(t *testing.T) {
+	inner := &countingChecker{}
+	c := check.Cached(inner, 200*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Check(context.Background()); err != nil {
+				t.Errorf("Check: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := inner.calls.Load(); got != 1 {
+		t.Errorf("within ttl: got %d calls, want 1", got)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	_ = c.Check(context.Background())
+	if got := inner.calls.Load(); got != 2 {
+		t.Errorf("after ttl: got %d calls, want 2", got)
+	}
+}
+
+func TestCachedServesCachedError(t *testing.T) {
+	c := check.Cached(errChecker{"down"}, time.Minute)
+	for i := 0; i < 2; i++ {
+		if err := c.Check(context.Background()); err == nil || err.Error() != "down" {
+			t.Errorf("call %d: got %v, want down", i, err)
+		}
+	}
+}
+
+// manualClock is a Clock whose Now only moves when advanced.
+type manualClock struct {
+	check.RealClock
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestCachedTTLUsesContextClock(t *testing.T) {
+	clock := &manualClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ctx := check.ContextWithClock(context.Background(), clock)
+	inner := &countingChecker{}
+	c := check.Cached(inner, time.Minute)
+
+	for range 3 {
+		if err := c.Check(ctx); err != nil {
+			t.Fatalf("Check: %v", err)
+		}
+	}
+	if got := inner.calls.Load(); got != 1 {
+		t.Errorf("within ttl: got %d calls, want 1", got)
+	}
+	clock.advance(59 * time.Second)
+	_ = c.Check(ctx)
+	if got := inner.calls.Load(); got != 1 {
+		t.Errorf("just before ttl: got %d calls, want 1", got)
+	}
+	clock.advance(time.Second)
+	_ = c.Check(ctx)
+	if got := inner.calls.Load(); got != 2 {
+		t.Errorf("after ttl on the clock: got %d calls, want 2", got)
+	}
+}
+
+// cancelOnceChecker blocks its first run until ctx is done and passes later
+// runs.
+type cancelOnceChecker struct {
+	calls   atomic.Int32
+	entered chan struct{}
+}
+
+func (c *cancelOnceChecker) Check(ctx context.Context) error {
+	if c.calls.Add(1) == 1 {
+		close(c.entered)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestCachedWaiterIgnoresCancelledLeader(t *testing.T) {
+	inner := &cancelOnceChecker{entered: make(chan struct{})}
+	c := check.Cached(inner, time.Minute)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	go func() { _ = c.Check(leaderCtx) }()
+	<-inner.entered
+	waiter := make(chan error, 1)
+	go func() { waiter <- c.Check(context.Background()) }()
+	time.Sleep(50 * time.Millisecond) // let the waiter join the leader's refresh
+	cancel()
+
+	select {
+	case err := <-waiter:
+		if err != nil {
+			t.Errorf("waiter: got %v, want its own passing result", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("waiter did not return after the leader was cancelled")
+	}
+	if got := inner.calls.Load(); got != 2 {
+		t.Errorf("calls: got %d, want the cancelled run and the waiter's retry", got)
+	}
+}
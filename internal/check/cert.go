@@ -0,0 +1,64 @@
+package check
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+)
+
+type certExpiryChecker struct {
+	path         string
+	minRemaining time.Duration
+}
+
+// NewCertExpiryChecker returns a Checker that fails when the first PEM
+// certificate in certPath expires in less than minRemaining, so a pod
+// serving a stale certificate goes not-ready and gets attention before
+// clients start rejecting it. The file is re-read on every check, so a
+// rotated certificate is picked up. A missing or unparsable file fails.
+// The remaining time is measured on the manager's clock (see WithClock).
+func NewCertExpiryChecker(certPath string, minRemaining time.Duration) Checker {
+	return &certExpiryChecker{path: certPath, minRemaining: minRemaining}
+}
+
+func (c *certExpiryChecker) Check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", c.path, err)
+	}
+	cert, err := readCert(c.path)
+	if err != nil {
+		return err
+	}
+	if remaining := cert.NotAfter.Sub(clockFrom(ctx).Now()); remaining < c.minRemaining {
+		return fmt.Errorf("%s: certificate %q expires at %s, in %s, less than %s",
+			c.path, cert.Subject.CommonName, cert.NotAfter.UTC().Format(time.RFC3339),
+			remaining.Round(time.Second), c.minRemaining)
+	}
+	return nil
+}
+
+// readCert parses the first CERTIFICATE block of the PEM file at path.
+func readCert(path string) (*x509.Certificate, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			return nil, fmt.Errorf("%s: no PEM certificate found", path)
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return cert, nil
+	}
+}
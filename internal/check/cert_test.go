@@ -0,0 +1,85 @@
+package check_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+// testdata/cert-valid.pem is a self-signed certificate valid until 2125.
+
+func TestCertExpiryCheckerValid(t *testing.T) {
+	c := check.NewCertExpiryChecker("testdata/cert-valid.pem", 30*24*time.Hour)
+	if err := c.Check(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCertExpiryCheckerNearExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tls.crt")
+	writeFile(t, path, selfSignedPEM(t, time.Now().Add(time.Hour)))
+	err := check.NewCertExpiryChecker(path, 24*time.Hour).Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "near.example") {
+		t.Errorf("got %v, want an expiry error naming the certificate", err)
+	}
+	if err := check.NewCertExpiryChecker(path, time.Minute).Check(context.Background()); err != nil {
+		t.Errorf("within minRemaining: unexpected error: %v", err)
+	}
+}
+
+func TestCertExpiryCheckerUsesContextClock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tls.crt")
+	notAfter := time.Now().Add(48 * time.Hour)
+	writeFile(t, path, selfSignedPEM(t, notAfter))
+	c := check.NewCertExpiryChecker(path, 24*time.Hour)
+	if err := c.Check(context.Background()); err != nil {
+		t.Fatalf("real clock: unexpected error: %v", err)
+	}
+	clock := &manualClock{now: notAfter.Add(-time.Hour)}
+	if err := c.Check(check.ContextWithClock(context.Background(), clock)); err == nil {
+		t.Error("an hour before expiry on the context clock: expected error, got nil")
+	}
+}
+
+func TestCertExpiryCheckerBadFile(t *testing.T) {
+	dir := t.TempDir()
+	garbage := filepath.Join(dir, "garbage.pem")
+	writeFile(t, garbage, "not a certificate\n")
+	corrupt := filepath.Join(dir, "corrupt.pem")
+	writeFile(t, corrupt, "-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n")
+	for _, path := range []string{filepath.Join(dir, "missing.pem"), garbage, corrupt} {
+		if err := check.NewCertExpiryChecker(path, time.Hour).Check(context.Background()); err == nil {
+			t.Errorf("%s: expected error, got nil", filepath.Base(path))
+		}
+	}
+}
+
+func selfSignedPEM(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "near.example"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
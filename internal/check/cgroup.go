@@ -0,0 +1,125 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// defaultCgroupRoot is where the container's cgroup hierarchy is mounted.
+const defaultCgroupRoot = "/sys/fs/cgroup"
+
+// cgroupUnlimited is the smallest cgroup v1 limit treated as "no limit"; v1
+// reports an unset limit as a page-aligned value near math.MaxInt64.
+const cgroupUnlimited = 1 << 62
+
+type cgroupMemoryChecker struct {
+	root        string
+	maxFraction float64
+}
+
+// NewCgroupMemoryChecker returns a Checker that fails when the container's
+// memory working set exceeds maxFraction (e.g. 0.9) of its cgroup memory
+// limit, so the pod goes not-ready before the OOM killer strikes.
+//
+// Both cgroup v2 (memory.current, memory.max) and v1 (memory/
+// memory.usage_in_bytes, memory/memory.limit_in_bytes) are supported. The
+// working set is usage minus inactive file cache, as the kubelet computes it.
+// The check passes when no cgroup memory files are found (e.g. outside a
+// container) or no limit is set.
+func NewCgroupMemoryChecker(maxFraction float64) Checker {
+	return newCgroupMemoryChecker(defaultCgroupRoot, maxFraction)
+}
+
+func newCgroupMemoryChecker(root string, maxFraction float64) *cgroupMemoryChecker {
+	return &cgroupMemoryChecker{root: root, maxFraction: maxFraction}
+}
+
+func (c *cgroupMemoryChecker) Check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	usage, limit, err := c.read()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil // not in a memory cgroup
+	}
+	if err != nil {
+		return err
+	}
+	if limit == 0 || limit >= cgroupUnlimited {
+		return nil
+	}
+	if frac := float64(usage) / float64(limit); frac > c.maxFraction {
+		return fmt.Errorf("cgroup memory usage %d bytes is %.1f%% of limit %d bytes, above %.1f%%",
+			usage, frac*100, limit, c.maxFraction*100)
+	}
+	return nil
+}
+
+// read returns the working set and limit in bytes from the v2 files, falling
+// back to v1. A limit of 0 means unlimited.
+func (c *cgroupMemoryChecker) read() (usage, limit uint64, err error) {
+	usage, limit, err = readCgroupMemory(c.root, "memory.current", "memory.max", "inactive_file")
+	if errors.Is(err, fs.ErrNotExist) {
+		dir := filepath.Join(c.root, "memory")
+		usage, limit, err = readCgroupMemory(dir, "memory.usage_in_bytes", "memory.limit_in_bytes", "total_inactive_file")
+	}
+	return usage, limit, err
+}
+
+// readCgroupMemory reads usage and limit files from dir and subtracts the
+// inactiveKey entry of memory.stat, when present, from usage.
+func readCgroupMemory(dir, usageFile, limitFile, inactiveKey string) (usage, limit uint64, err error) {
+	if usage, err = readCgroupValue(filepath.Join(dir, usageFile)); err != nil {
+		return 0, 0, err
+	}
+	if limit, err = readCgroupValue(filepath.Join(dir, limitFile)); err != nil {
+		return 0, 0, err
+	}
+	if inactive, ok := readCgroupStat(filepath.Join(dir, "memory.stat"), inactiveKey); ok && inactive < usage {
+		usage -= inactive
+	}
+	return usage, limit, nil
+}
+
+// readCgroupValue parses a single-value cgroup file; "max" reads as 0.
+func readCgroupValue(path string) (uint64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(string(b))
+	if s == "max" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+	return v, nil
+}
+
+// readCgroupStat returns the value of key in a memory.stat file.
+func readCgroupStat(path, key string) (uint64, bool) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		name, value, ok := strings.Cut(sc.Text(), " ")
+		if !ok || name != key {
+			continue
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		return v, err == nil
+	}
+	return 0, false
+}
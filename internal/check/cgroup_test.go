@@ -0,0 +1,74 @@
+package check_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+// The fixtures report 600 MiB usage with 100 MiB inactive file cache against
+// a 1 GiB limit: a working set of about 48.8%.
+
+func TestCgroupMemoryCheckerV2(t *testing.T) {
+	if err := check.NewCgroupMemoryCheckerAt("testdata/cgroup-v2", 0.5).Check(context.Background()); err != nil {
+		t.Errorf("under threshold: unexpected error: %v", err)
+	}
+	err := check.NewCgroupMemoryCheckerAt("testdata/cgroup-v2", 0.45).Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "48.8%") {
+		t.Errorf("over threshold: got %v, want error reporting 48.8%%", err)
+	}
+}
+
+func TestCgroupMemoryCheckerV1(t *testing.T) {
+	if err := check.NewCgroupMemoryCheckerAt("testdata/cgroup-v1", 0.5).Check(context.Background()); err != nil {
+		t.Errorf("under threshold: unexpected error: %v", err)
+	}
+	if err := check.NewCgroupMemoryCheckerAt("testdata/cgroup-v1", 0.45).Check(context.Background()); err == nil {
+		t.Error("over threshold: expected error, got nil")
+	}
+}
+
+func TestCgroupMemoryCheckerUnlimited(t *testing.T) {
+	v2 := t.TempDir()
+	writeFile(t, filepath.Join(v2, "memory.current"), "629145600\n")
+	writeFile(t, filepath.Join(v2, "memory.max"), "max\n")
+	if err := check.NewCgroupMemoryCheckerAt(v2, 0.1).Check(context.Background()); err != nil {
+		t.Errorf("v2 without limit: unexpected error: %v", err)
+	}
+
+	v1 := t.TempDir()
+	writeFile(t, filepath.Join(v1, "memory", "memory.usage_in_bytes"), "629145600\n")
+	writeFile(t, filepath.Join(v1, "memory", "memory.limit_in_bytes"), "9223372036854771712\n")
+	if err := check.NewCgroupMemoryCheckerAt(v1, 0.1).Check(context.Background()); err != nil {
+		t.Errorf("v1 without limit: unexpected error: %v", err)
+	}
+}
+
+func TestCgroupMemoryCheckerNoCgroup(t *testing.T) {
+	if err := check.NewCgroupMemoryCheckerAt(t.TempDir(), 0.1).Check(context.Background()); err != nil {
+		t.Errorf("no cgroup files: unexpected error: %v", err)
+	}
+}
+
+func TestCgroupMemoryCheckerMalformed(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "memory.current"), "lots\n")
+	writeFile(t, filepath.Join(dir, "memory.max"), "1073741824\n")
+	if err := check.NewCgroupMemoryCheckerAt(dir, 0.9).Check(context.Background()); err == nil {
+		t.Error("malformed usage: expected error, got nil")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -7,3 +7,38 @@ import "context"
 type Checker interface {
 	Check(ctx context.Context) error
 }
+
+// Func adapts an ordinary function to the Checker interface, like
+// http.HandlerFunc.
+type Func func(ctx context.Context) error
+
+// Check calls f(ctx).
+func (f Func) Check(ctx context.Context) error { return f(ctx) }
+
+// CheckStatus is the outcome reported by a DetailedChecker.
+type CheckStatus int
+
+const (
+	// StatusOK reports a healthy dependency.
+	StatusOK CheckStatus = iota
+	// StatusWarn reports a dependency that works but looks concerning. It
+	// shows in the /ready body without failing readiness.
+	StatusWarn
+	// StatusError reports a failed dependency, like a non-nil Check error.
+	StatusError
+)
+
+// CheckResult is the outcome of a DetailedChecker run.
+type CheckResult struct {
+	Status  CheckStatus
+	Message string
+}
+
+// DetailedChecker is a Checker that can also report a warning. /ready calls
+// CheckDetailed in place of Check when a registered checker implements it;
+// startup and liveness checks keep calling Check. Wrappers such as Cached
+// hide the richer method, so they report plain pass or fail.
+type DetailedChecker interface {
+	Checker
+	CheckDetailed(ctx context.Context) CheckResult
+}
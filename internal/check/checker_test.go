@@ -0,0 +1,17 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func TestFuncAdapter(t *testing.T) {
+	errDown := errors.New("down")
+	var c check.Checker = check.Func(func(context.Context) error { return errDown })
+	if err := c.Check(context.Background()); !errors.Is(err, errDown) {
+		t.Errorf("Check: got %v, want %v", err, errDown)
+	}
+}
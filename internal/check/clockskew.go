@@ -0,0 +1,34 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type clockSkewChecker struct {
+	reference func(ctx context.Context) (time.Time, error)
+	maxSkew   time.Duration
+}
+
+// NewClockSkewChecker returns a Checker that fails when the local clock is
+// more than maxSkew ahead of or behind the time returned by reference, e.g.
+// an NTP query or a peer's clock. The local time is taken halfway through the
+// reference call to offset its latency. A reference error fails the check.
+func NewClockSkewChecker(reference func(ctx context.Context) (time.Time, error), maxSkew time.Duration) Checker {
+	return &clockSkewChecker{reference: reference, maxSkew: maxSkew}
+}
+
+func (c *clockSkewChecker) Check(ctx context.Context) error {
+	before := time.Now()
+	ref, err := c.reference(ctx)
+	if err != nil {
+		return fmt.Errorf("clock reference: %w", err)
+	}
+	local := before.Add(time.Since(before) / 2)
+	skew := local.Sub(ref)
+	if skew.Abs() > c.maxSkew {
+		return fmt.Errorf("clock skew %v exceeds %v", skew.Round(time.Millisecond), c.maxSkew)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func TestClockSkewChecker(t *testing.T) {
+	reference := func(offset time.Duration) func(context.Context) (time.Time, error) {
+		return func(context.Context) (time.Time, error) { return time.Now().Add(offset), nil }
+	}
+	ctx := context.Background()
+
+	if err := check.NewClockSkewChecker(reference(100*time.Millisecond), time.Second).Check(ctx); err != nil {
+		t.Errorf("within tolerance: unexpected error: %v", err)
+	}
+	for _, offset := range []time.Duration{5 * time.Second, -5 * time.Second} {
+		err := check.NewClockSkewChecker(reference(offset), time.Second).Check(ctx)
+		if err == nil || !strings.Contains(err.Error(), "clock skew") {
+			t.Errorf("reference off by %v: got %v, want clock skew error", offset, err)
+		}
+	}
+
+	failing := func(context.Context) (time.Time, error) { return time.Time{}, errors.New("ntp timeout") }
+	if err := check.NewClockSkewChecker(failing, time.Second).Check(ctx); err == nil || !strings.Contains(err.Error(), "ntp timeout") {
+		t.Errorf("reference error: got %v, want it wrapped", err)
+	}
+}
@@ -0,0 +1,47 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// maxStderrLen bounds how much captured stderr a CommandChecker error carries.
+const maxStderrLen = 256
+
+type commandChecker struct {
+	name string
+	args []string
+}
+
+// NewCommandChecker returns a Checker that runs name with args and treats
+// exit status 0 as healthy. A non-zero exit returns an error carrying the
+// (truncated) stderr. The process is killed when the check context expires.
+//
+// The command runs with the probe process's privileges and environment on
+// every /ready request. Use a fixed binary path and arguments; never build
+// them from request or other untrusted input.
+func NewCommandChecker(name string, args ...string) Checker {
+	return &commandChecker{name: name, args: args}
+}
+
+func (c *commandChecker) Check(ctx context.Context) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.name, c.args...)
+	cmd.Stderr = &stderr
+	// Don't wait on pipes held open by grandchildren once the process is killed.
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("%s: %w", c.name, ctxErr)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", c.name, err, truncate(msg, maxStderrLen))
+		}
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package check_test
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func requireCommand(t *testing.T, name string) {
+	t.Helper()
+	if _, err := exec.LookPath(name); err != nil {
+		t.Skipf("%s not available: %v", name, err)
+	}
+}
+
+func TestCommandCheckerExitStatus(t *testing.T) {
+	requireCommand(t, "true")
+	requireCommand(t, "false")
+	if err := check.NewCommandChecker("true").Check(context.Background()); err != nil {
+		t.Errorf("true: unexpected error: %v", err)
+	}
+	if err := check.NewCommandChecker("false").Check(context.Background()); err == nil {
+		t.Error("false: expected error, got nil")
+	}
+}
+
+func TestCommandCheckerStderrAndTimeout(t *testing.T) {
+	requireCommand(t, "sh")
+	err := check.NewCommandChecker("sh", "-c", "echo unreachable >&2; exit 3").Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Errorf("stderr: got %v, want error containing stderr", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := check.NewCommandChecker("sh", "-c", "sleep 10").Check(ctx); err == nil {
+		t.Error("timeout: expected error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("timeout: process not killed promptly (%v)", elapsed)
+	}
+}
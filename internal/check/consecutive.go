@@ -0,0 +1,38 @@
+package check
+
+import (
+	"context"
+	"sync"
+)
+
+type consecutiveChecker struct {
+	checker   Checker
+	threshold int
+
+	mu       sync.Mutex
+	failures int
+}
+
+// AfterConsecutiveFailures returns a Checker that passes until c has failed
+// threshold times in a row, then returns c's error until c passes again,
+// which resets the count. This adds hysteresis to a flapping dependency.
+// Calls from concurrent /ready requests each count. A threshold below 2
+// reports every failure.
+func AfterConsecutiveFailures(c Checker, threshold int) Checker {
+	return &consecutiveChecker{checker: c, threshold: threshold}
+}
+
+func (c *consecutiveChecker) Check(ctx context.Context) error {
+	err := c.checker.Check(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.failures = 0
+		return nil
+	}
+	c.failures++
+	if c.failures < c.threshold {
+		return nil
+	}
+	return err
+}
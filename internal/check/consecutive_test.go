@@ -0,0 +1,68 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func TestAfterConsecutiveFailures(t *testing.T) {
+	errDown := errors.New("down")
+	var fail bool
+	c := check.AfterConsecutiveFailures(check.Func(func(context.Context) error {
+		if fail {
+			return errDown
+		}
+		return nil
+	}), 3)
+	ctx := context.Background()
+
+	fail = true
+	for i := 1; i < 3; i++ {
+		if err := c.Check(ctx); err != nil {
+			t.Fatalf("failure %d: got %v, want nil below the threshold", i, err)
+		}
+	}
+	if err := c.Check(ctx); !errors.Is(err, errDown) {
+		t.Fatalf("failure 3: got %v, want %v", err, errDown)
+	}
+	if err := c.Check(ctx); !errors.Is(err, errDown) {
+		t.Fatalf("failure 4: got %v, want %v", err, errDown)
+	}
+
+	fail = false
+	if err := c.Check(ctx); err != nil {
+		t.Fatalf("success: got %v, want nil", err)
+	}
+	fail = true
+	if err := c.Check(ctx); err != nil {
+		t.Errorf("first failure after a success: got %v, want nil (count reset)", err)
+	}
+}
+
+func TestAfterConsecutiveFailuresConcurrent(t *testing.T) {
+	c := check.AfterConsecutiveFailures(errChecker{"down"}, 50)
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- c.Check(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	failed := 0
+	for err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed != 51 {
+		t.Errorf("failures reported: got %d, want 51 (calls 50 through 100)", failed)
+	}
+}
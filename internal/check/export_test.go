@@ -0,0 +1,30 @@
+package check
+
+import (
+	"net"
+	"net/http"
+)
+
+// NewCgroupMemoryCheckerAt reads the cgroup hierarchy under root instead of
+// /sys/fs/cgroup, for tests against fixture files.
+func NewCgroupMemoryCheckerAt(root string, maxFraction float64) Checker {
+	return newCgroupMemoryChecker(root, maxFraction)
+}
+
+// NewFDUsageCheckerAt counts descriptors in dir against limit instead of
+// /proc/self/fd and RLIMIT_NOFILE, for tests.
+func NewFDUsageCheckerAt(dir string, limit uint64, maxFraction float64) Checker {
+	return newFDUsageChecker(dir, func() (uint64, error) { return limit, nil }, maxFraction)
+}
+
+// Listen opens a standalone probe listener on addr, for tests that inspect
+// its socket options.
+func Listen(addr string, lo ListenOptions) (net.Listener, error) {
+	return listen(addr, lo)
+}
+
+// NewProbeServer returns the server the HTTP probe built from opts would run
+// on port, for tests that inspect its timeouts.
+func NewProbeServer(opts HTTPOptions, port int) *http.Server {
+	return (&httpProbe{opts: opts}).newServer(port, http.NotFoundHandler())
+}
@@ -0,0 +1,48 @@
+package check
+
+import (
+	"log/slog"
+	"sort"
+	"sync/atomic"
+)
+
+// failureSampler logs one in every n failing probe responses.
+type failureSampler struct {
+	log   *slog.Logger
+	every uint64
+	count atomic.Uint64
+}
+
+func newFailureSampler(log *slog.Logger, every int) *failureSampler {
+	if log == nil {
+		return nil
+	}
+	if every < 1 {
+		every = 1
+	}
+	return &failureSampler{log: log, every: uint64(every)}
+}
+
+// record counts a failing response to endpoint and logs it when it falls on
+// the sample. The first failure is always logged.
+func (s *failureSampler) record(endpoint, reason string, results map[string]string) {
+	if s == nil {
+		return
+	}
+	if (s.count.Add(1)-1)%s.every != 0 {
+		return
+	}
+	var failing []string
+	for name, v := range results {
+		if resultFailed(v) {
+			failing = append(failing, name)
+		}
+	}
+	sort.Strings(failing)
+	s.log.Warn("probe failed",
+		"endpoint", endpoint,
+		"reason", reason,
+		"failing_checkers", failing,
+		"sample_every", s.every,
+	)
+}
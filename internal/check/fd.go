@@ -0,0 +1,60 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// defaultFDDir lists the process's open file descriptors on Linux.
+const defaultFDDir = "/proc/self/fd"
+
+type fdUsageChecker struct {
+	dir         string
+	limit       func() (uint64, error)
+	maxFraction float64
+}
+
+// NewFDUsageChecker returns a Checker that fails when the process has more
+// than maxFraction (e.g. 0.9) of its soft RLIMIT_NOFILE open as file
+// descriptors, so the pod goes not-ready before accept and open start failing
+// with "too many open files".
+//
+// Descriptors are counted from /proc/self/fd. The check passes where that
+// directory does not exist (outside Linux) or no limit is set.
+func NewFDUsageChecker(maxFraction float64) Checker {
+	return newFDUsageChecker(defaultFDDir, openFilesLimit, maxFraction)
+}
+
+func newFDUsageChecker(dir string, limit func() (uint64, error), maxFraction float64) *fdUsageChecker {
+	return &fdUsageChecker{dir: dir, limit: limit, maxFraction: maxFraction}
+}
+
+func (c *fdUsageChecker) Check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	limit, err := c.limit()
+	if err != nil {
+		return fmt.Errorf("read open files limit: %w", err)
+	}
+	if limit == 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil // no per-process fd listing on this platform
+	}
+	if err != nil {
+		return err
+	}
+	// Reading the directory holds one descriptor of its own.
+	open := uint64(max(len(entries)-1, 0))
+	if frac := float64(open) / float64(limit); frac > c.maxFraction {
+		return fmt.Errorf("%d open file descriptors is %.1f%% of limit %d, above %.1f%%",
+			open, frac*100, limit, c.maxFraction*100)
+	}
+	return nil
+}
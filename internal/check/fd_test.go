@@ -0,0 +1,49 @@
+package check_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+// fdDir returns a directory standing in for /proc/self/fd with n entries,
+// one of which is the descriptor used to read it.
+func fdDir(t *testing.T, n int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i := range n {
+		writeFile(t, filepath.Join(dir, fmt.Sprint(i)), "")
+	}
+	return dir
+}
+
+func TestFDUsageChecker(t *testing.T) {
+	dir := fdDir(t, 81) // 80 open descriptors
+	if err := check.NewFDUsageCheckerAt(dir, 100, 0.9).Check(context.Background()); err != nil {
+		t.Errorf("under threshold: unexpected error: %v", err)
+	}
+	err := check.NewFDUsageCheckerAt(dir, 100, 0.75).Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "80 open file descriptors") {
+		t.Errorf("over threshold: got %v, want error reporting 80 descriptors", err)
+	}
+}
+
+func TestFDUsageCheckerNoLimitOrListing(t *testing.T) {
+	if err := check.NewFDUsageCheckerAt(fdDir(t, 10), 0, 0.1).Check(context.Background()); err != nil {
+		t.Errorf("no limit: unexpected error: %v", err)
+	}
+	missing := filepath.Join(t.TempDir(), "fd")
+	if err := check.NewFDUsageCheckerAt(missing, 100, 0.1).Check(context.Background()); err != nil {
+		t.Errorf("no fd listing: unexpected error: %v", err)
+	}
+}
+
+func TestFDUsageCheckerProcess(t *testing.T) {
+	if err := check.NewFDUsageChecker(0.99).Check(context.Background()); err != nil {
+		t.Errorf("this process: unexpected error: %v", err)
+	}
+}
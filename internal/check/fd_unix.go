@@ -0,0 +1,18 @@
+//go:build unix
+
+package check
+
+import "syscall"
+
+// openFilesLimit returns the soft RLIMIT_NOFILE, or 0 when it is unlimited.
+func openFilesLimit() (uint64, error) {
+	var rl syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rl); err != nil {
+		return 0, err
+	}
+	// RLIM_INFINITY is all ones on Linux and the largest int64 on the BSDs.
+	if limit := uint64(rl.Cur); limit < 1<<62 {
+		return limit, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,38 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxFileContentLen bounds how much of a mismatched file a FileContentChecker
+// error quotes.
+const maxFileContentLen = 64
+
+type fileContentChecker struct {
+	path string
+	want string
+}
+
+// NewFileContentChecker returns a Checker that reads path and passes when its
+// whitespace-trimmed content equals want, e.g. a readiness flag mounted from
+// the downward API or a ConfigMap. A missing file fails the check.
+func NewFileContentChecker(path, want string) Checker {
+	return &fileContentChecker{path: path, want: strings.TrimSpace(want)}
+}
+
+func (c *fileContentChecker) Check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", c.path, err)
+	}
+	b, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+	if got := strings.TrimSpace(string(b)); got != c.want {
+		return fmt.Errorf("%s: got %q, want %q", c.path, truncate(got, maxFileContentLen), c.want)
+	}
+	return nil
+}
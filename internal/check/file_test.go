@@ -0,0 +1,43 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func TestFileContentChecker(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ready")
+	c := check.NewFileContentChecker(path, "true")
+
+	if err := c.Check(context.Background()); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("absent: got %v, want fs.ErrNotExist", err)
+	}
+
+	if err := os.WriteFile(path, []byte("false\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Check(context.Background()); err == nil || !strings.Contains(err.Error(), `"false"`) {
+		t.Errorf("mismatched: got %v, want error quoting the content", err)
+	}
+
+	if err := os.WriteFile(path, []byte("  true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Check(context.Background()); err != nil {
+		t.Errorf("matching: unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Check(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: got %v, want context.Canceled", err)
+	}
+}
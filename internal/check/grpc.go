@@ -2,14 +2,16 @@ package check
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/health"
+	channelzsvc "google.golang.org/grpc/channelz/service"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 const (
@@ -18,45 +20,149 @@ const (
 	serviceStartup = "startup"
 )
 
+// ErrReservedService is returned when setting the status of one of the
+// built-in "ready", "live", or "startup" services directly.
+var ErrReservedService = errors.New("reserved health service")
+
+// ServiceStatusSetter is implemented by the gRPC probes, whose health server
+// can report application-defined services next to ready, live, and startup.
+type ServiceStatusSetter interface {
+	SetServiceStatus(service string, serving bool) error
+}
+
+// checkServiceName rejects the built-in service names.
+func checkServiceName(service string) error {
+	switch service {
+	case serviceReady, serviceLive, serviceStartup:
+		return fmt.Errorf("%w: %q is managed by the probe", ErrReservedService, service)
+	}
+	return nil
+}
+
+// servingStatus maps serving to a health status.
+func servingStatus(serving bool) healthpb.HealthCheckResponse_ServingStatus {
+	if serving {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+// GRPCOptions configures the standalone gRPC probe.
+type GRPCOptions struct {
+	Port int
+	// Listener, when set, is served instead of binding Port. The probe takes
+	// ownership and closes it on Shutdown.
+	Listener        net.Listener
+	ShutdownTimeout time.Duration
+	// DrainProgressInterval and OnDrainProgress, when both set, report the
+	// elapsed time periodically while Shutdown waits on GracefulStop.
+	DrainProgressInterval time.Duration
+	OnDrainProgress       func(elapsed time.Duration)
+	Listen                ListenOptions
+	// MaxConcurrentStreams and MaxRecvMsgSize, when non-zero, cap concurrent
+	// streams per connection (e.g. Watch fan-out) and inbound message size.
+	MaxConcurrentStreams uint32
+	MaxRecvMsgSize       int
+	// Reflection registers the gRPC server reflection service so tools such
+	// as grpcurl can discover the health service.
+	Reflection bool
+	// Channelz registers the channelz service, which exposes the server's
+	// connections and streams for debugging.
+	Channelz bool
+	// StartupShutdownGrace, when positive, keeps the "startup" service
+	// SERVING for this long after Shutdown begins, while "ready" and "live"
+	// flip to NOT_SERVING immediately. The hold counts against the shutdown
+	// context.
+	StartupShutdownGrace time.Duration
+	// LiveIgnoresShutdown keeps the "live" service SERVING while shutting
+	// down, so only "ready" signals the drain.
+	LiveIgnoresShutdown bool
+	// OnForcedStop, when set, is called when Shutdown's context expires and
+	// the server is stopped without waiting for in-flight RPCs.
+	OnForcedStop func()
+	// Clock drives the startup hold and drain progress. Nil means RealClock.
+	Clock Clock
+}
+
 type grpcProbe struct {
-	port            int
-	shutdownTimeout time.Duration
-	server          *grpc.Server
-	health          *health.Server
-	mu              sync.Mutex
+	opts   GRPCOptions
+	server *grpc.Server
+	health *trackedHealth
+	state  StateReader
+	mu     sync.Mutex
+	// custom holds application service statuses set before Start, applied
+	// once the health server exists.
+	custom map[string]bool
+	// stopped is set once Shutdown begins; later state updates are dropped.
+	stopped bool
 }
 
 // NewGRPCProbe returns a Server that implements the gRPC health protocol for services "ready", "live", "startup".
-func NewGRPCProbe(port int, shutdownTimeout time.Duration) Server {
-	return &grpcProbe{port: port, shutdownTimeout: shutdownTimeout}
+// Only those, the server-wide "" entry, and services set with SetServiceStatus
+// are known: Check on any other name fails with codes.NotFound, and Watch
+// reports SERVICE_UNKNOWN, so clients can tell a misconfigured name from a
+// service that is not serving.
+func NewGRPCProbe(opts GRPCOptions) Server {
+	return &grpcProbe{opts: opts}
+}
+
+// NewGRPCProbeWithListener returns a gRPC probe that serves on ln instead of
+// binding a port, for socket activation or caller-managed listeners.
+func NewGRPCProbeWithListener(ln net.Listener, shutdownTimeout time.Duration) Server {
+	return NewGRPCProbe(GRPCOptions{Listener: ln, ShutdownTimeout: shutdownTimeout})
 }
 
 func (g *grpcProbe) Start(state StateReader, onStarted func()) error {
 	g.mu.Lock()
-	g.health = health.NewServer()
-	g.server = grpc.NewServer()
+	g.state = state
+	g.health = newTrackedHealth()
+	g.server = grpc.NewServer(g.serverOptions()...)
 	healthpb.RegisterHealthServer(g.server, g.health)
+	if g.opts.Reflection {
+		reflection.Register(g.server)
+	}
+	if g.opts.Channelz {
+		channelzsvc.RegisterChannelzServiceToServer(g.server)
+	}
+	for service, serving := range g.custom {
+		g.health.SetServingStatus(service, servingStatus(serving))
+	}
 	g.mu.Unlock()
 
-	addr := net.JoinHostPort("", fmt.Sprintf("%d", g.port))
-	ln, err := net.Listen("tcp", addr)
-	if err != nil {
-		return err
+	ln := g.opts.Listener
+	if ln == nil {
+		var err error
+		ln, err = listen(g.opts.Listen.addr(g.opts.Port), g.opts.Listen)
+		if err != nil {
+			return err
+		}
 	}
 	onStarted()
-	g.health.SetServingStatus(serviceStartup, healthpb.HealthCheckResponse_SERVING)
-	g.applyState(g.health, state.Ready(), state.ShuttingDown())
+	ready, shuttingDown := state.Ready(), state.ShuttingDown()
+	g.mu.Lock()
+	g.applyState(g.health, ready, shuttingDown, state)
+	g.mu.Unlock()
 	go func() { _ = g.server.Serve(ln) }()
 	return nil
 }
 
-// applyState sets gRPC health statuses without acquiring the lock.
-// Must be called with g.mu held OR before Start returns (single-goroutine context).
-func applyState(hs *health.Server, ready, shuttingDown bool) {
+// applyState sets gRPC health statuses without acquiring a lock. Callers
+// hold the probe's mutex so that each logical state change is applied as a
+// unit relative to other SetState calls, and a watcher never ends up with,
+// say, ready SERVING after shutdown began. Started and liveness are read from
+// state, which is nil before Start.
+// With a StartupShutdownGrace, shutting down leaves "startup" as is; Shutdown
+// clears it after the grace period. With LiveIgnoresShutdown, "live" keeps
+// following liveness alone.
+func applyState(hs *trackedHealth, ready, shuttingDown bool, state StateReader, opts *GRPCOptions) {
 	if shuttingDown {
 		hs.SetServingStatus(serviceReady, healthpb.HealthCheckResponse_NOT_SERVING)
-		hs.SetServingStatus(serviceLive, healthpb.HealthCheckResponse_NOT_SERVING)
-		hs.SetServingStatus(serviceStartup, healthpb.HealthCheckResponse_NOT_SERVING)
+		if !opts.LiveIgnoresShutdown || !isLive(state) {
+			hs.SetServingStatus(serviceLive, healthpb.HealthCheckResponse_NOT_SERVING)
+		}
+		if opts.StartupShutdownGrace <= 0 {
+			hs.SetServingStatus(serviceStartup, healthpb.HealthCheckResponse_NOT_SERVING)
+		}
 		return
 	}
 	if ready {
@@ -64,39 +170,123 @@ func applyState(hs *health.Server, ready, shuttingDown bool) {
 	} else {
 		hs.SetServingStatus(serviceReady, healthpb.HealthCheckResponse_NOT_SERVING)
 	}
-	hs.SetServingStatus(serviceLive, healthpb.HealthCheckResponse_SERVING)
+	if isLive(state) {
+		hs.SetServingStatus(serviceLive, healthpb.HealthCheckResponse_SERVING)
+	} else {
+		hs.SetServingStatus(serviceLive, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	if state != nil && state.Started() {
+		hs.SetServingStatus(serviceStartup, healthpb.HealthCheckResponse_SERVING)
+	} else {
+		hs.SetServingStatus(serviceStartup, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
 }
 
-func (g *grpcProbe) applyState(hs *health.Server, ready, shuttingDown bool) {
-	applyState(hs, ready, shuttingDown)
+func (g *grpcProbe) applyState(hs *trackedHealth, ready, shuttingDown bool, state StateReader) {
+	applyState(hs, ready, shuttingDown, state, &g.opts)
 }
 
+// SetState is a no-op before Start; Start applies the current StateReader
+// values, so state set before the server is up is not lost. It is also a
+// no-op once Shutdown has begun, so a late update cannot report SERVING.
 func (g *grpcProbe) SetState(ready, shuttingDown bool) {
 	g.mu.Lock()
-	hs := g.health
-	g.mu.Unlock()
-	if hs == nil {
+	defer g.mu.Unlock()
+	if g.health == nil || g.stopped {
 		return
 	}
-	applyState(hs, ready, shuttingDown)
+	g.applyState(g.health, ready, shuttingDown, g.state)
+}
+
+// SetServiceStatus sets the health status of an application service. Before
+// Start it is remembered and applied when the health server is created; after
+// Shutdown has begun it is ignored.
+func (g *grpcProbe) SetServiceStatus(service string, serving bool) error {
+	if err := checkServiceName(service); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopped {
+		return nil
+	}
+	if g.custom == nil {
+		g.custom = make(map[string]bool)
+	}
+	g.custom[service] = serving
+	if g.health != nil {
+		g.health.SetServingStatus(service, servingStatus(serving))
+	}
+	return nil
+}
+
+// serverOptions returns the grpc.ServerOptions for the standalone server.
+func (g *grpcProbe) serverOptions() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if g.opts.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(g.opts.MaxConcurrentStreams))
+	}
+	if g.opts.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(g.opts.MaxRecvMsgSize))
+	}
+	return opts
 }
 
 func (g *grpcProbe) Shutdown(ctx context.Context) {
 	g.mu.Lock()
-	srv := g.server
+	srv, hs := g.server, g.health
+	g.stopped = true
 	g.mu.Unlock()
 	if srv == nil {
 		return
 	}
+	holdStartup(ctx, hs, g.opts.StartupShutdownGrace, clockOr(g.opts.Clock))
+	stopServer(ctx, srv, g.opts)
+}
+
+// holdStartup waits out grace, or until ctx expires, then marks the startup
+// service NOT_SERVING. It returns immediately when grace is not positive.
+func holdStartup(ctx context.Context, hs *trackedHealth, grace time.Duration, clock Clock) {
+	if grace <= 0 {
+		return
+	}
+	select {
+	case <-clock.After(grace):
+	case <-ctx.Done():
+	}
+	hs.SetServingStatus(serviceStartup, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// stopServer calls GracefulStop on srv, falling back to Stop when ctx expires,
+// and reports drain progress as configured in opts.
+func stopServer(ctx context.Context, srv *grpc.Server, opts GRPCOptions) {
 	done := make(chan struct{})
 	go func() {
 		srv.GracefulStop()
 		close(done)
 	}()
-	select {
-	case <-done:
-	case <-ctx.Done():
-		srv.Stop()
+
+	clock := clockOr(opts.Clock)
+	var tick <-chan time.Time
+	if opts.DrainProgressInterval > 0 && opts.OnDrainProgress != nil {
+		ticker := clock.NewTicker(opts.DrainProgressInterval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+	start := clock.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			srv.Stop()
+			if opts.OnForcedStop != nil {
+				opts.OnForcedStop()
+			}
+			return
+		case <-tick:
+			opts.OnDrainProgress(clock.Now().Sub(start))
+		}
 	}
 }
 
@@ -109,45 +299,98 @@ func (g *grpcProbe) Shutdown(ctx context.Context) {
 // happens at construction time so callers can safely call s.Serve afterwards
 // without a registration race.
 //
-// On Shutdown the health statuses are set to NOT_SERVING, but the underlying
-// server is NOT stopped — the caller owns the server and is responsible for
-// calling GracefulStop.
+// On Shutdown the health statuses are set to NOT_SERVING ("live" excepted
+// with LiveIgnoresShutdown), but the underlying server is NOT stopped — the
+// caller owns the server and is responsible for calling GracefulStop. A
+// managed probe (NewManagedGRPCProbe) also stops the server, like the
+// standalone probe does.
 type existingGRPCProbe struct {
-	health *health.Server
+	health *trackedHealth
+	state  StateReader
 	mu     sync.Mutex
+	server *grpc.Server // nil unless managed
+	opts   GRPCOptions
+	// stopped is set once Shutdown begins; later state updates are dropped.
+	stopped bool
 }
 
 // NewExistingGRPCProbe creates a Server that registers gRPC health on s.
 // s must not yet be serving when NewExistingGRPCProbe is called.
 func NewExistingGRPCProbe(s *grpc.Server) Server {
-	hs := health.NewServer()
+	return NewExistingGRPCProbeWithOptions(s, GRPCOptions{})
+}
+
+// NewExistingGRPCProbeWithOptions is like NewExistingGRPCProbe but honors the
+// StartupShutdownGrace, LiveIgnoresShutdown, and Clock fields of opts.
+func NewExistingGRPCProbeWithOptions(s *grpc.Server, opts GRPCOptions) Server {
+	hs := newTrackedHealth()
 	healthpb.RegisterHealthServer(s, hs)
-	return &existingGRPCProbe{health: hs}
+	return &existingGRPCProbe{health: hs, opts: opts}
+}
+
+// NewManagedGRPCProbe is like NewExistingGRPCProbe, but Shutdown also stops s:
+// GracefulStop, then Stop once the shutdown context expires. Only the drain
+// progress, StartupShutdownGrace, LiveIgnoresShutdown, OnForcedStop, and Clock
+// fields of opts are used.
+func NewManagedGRPCProbe(s *grpc.Server, opts GRPCOptions) Server {
+	hs := newTrackedHealth()
+	healthpb.RegisterHealthServer(s, hs)
+	return &existingGRPCProbe{health: hs, server: s, opts: opts}
 }
 
 func (e *existingGRPCProbe) Start(state StateReader, onStarted func()) error {
 	// No new server to start — health is pre-registered on the caller's server.
 	onStarted()
+	ready, shuttingDown := state.Ready(), state.ShuttingDown()
 	e.mu.Lock()
-	hs := e.health
-	e.mu.Unlock()
-	hs.SetServingStatus(serviceStartup, healthpb.HealthCheckResponse_SERVING)
-	applyState(hs, state.Ready(), state.ShuttingDown())
+	defer e.mu.Unlock()
+	e.state = state
+	applyState(e.health, ready, shuttingDown, state, &e.opts)
 	return nil
 }
 
+// SetState is a no-op once Shutdown has begun. The health server latches
+// NOT_SERVING on Shutdown anyway, but during a StartupShutdownGrace it has
+// not been shut down yet and a late update could otherwise flip it back.
 func (e *existingGRPCProbe) SetState(ready, shuttingDown bool) {
 	e.mu.Lock()
-	hs := e.health
-	e.mu.Unlock()
-	applyState(hs, ready, shuttingDown)
+	defer e.mu.Unlock()
+	if e.stopped {
+		return
+	}
+	applyState(e.health, ready, shuttingDown, e.state, &e.opts)
 }
 
-func (e *existingGRPCProbe) Shutdown(_ context.Context) {
+// SetServiceStatus sets the health status of an application service. It is
+// ignored once Shutdown has begun.
+func (e *existingGRPCProbe) SetServiceStatus(service string, serving bool) error {
+	if err := checkServiceName(service); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return nil
+	}
+	e.health.SetServingStatus(service, servingStatus(serving))
+	return nil
+}
+
+func (e *existingGRPCProbe) Shutdown(ctx context.Context) {
 	e.mu.Lock()
 	hs := e.health
+	e.stopped = true
 	e.mu.Unlock()
-	// Mark all health services NOT_SERVING so load-balancers stop routing.
-	// The caller is responsible for stopping the gRPC server itself.
-	hs.Shutdown()
+	// Mark the health services NOT_SERVING so load-balancers stop routing.
+	// With LiveIgnoresShutdown, "live" keeps the status applyState gave it.
+	// Unless managed, the caller is responsible for stopping the gRPC server.
+	holdStartup(ctx, hs, e.opts.StartupShutdownGrace, clockOr(e.opts.Clock))
+	if e.opts.LiveIgnoresShutdown {
+		hs.shutdownExcept(serviceLive)
+	} else {
+		hs.Shutdown()
+	}
+	if e.server != nil {
+		stopServer(ctx, e.server, e.opts)
+	}
 }
@@ -2,15 +2,23 @@ package check_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"google.golang.org/grpc"
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
 
 	"github.com/kroderdev/pod-lifecycle-go/internal/check"
 )
@@ -18,7 +26,7 @@ import (
 // startGRPCProbe starts a gRPC probe on port and returns the address and a cleanup func.
 func startGRPCProbe(t *testing.T, port int, state check.StateReader) (addr string, cleanup func()) {
 	t.Helper()
-	probe := check.NewGRPCProbe(port, 5*time.Second)
+	probe := check.NewGRPCProbe(check.GRPCOptions{Port: port, ShutdownTimeout: 5 * time.Second})
 	started := make(chan struct{})
 	errCh := make(chan error, 1)
 	go func() {
@@ -76,9 +84,68 @@ func TestGRPCReadyBeforeSetState(t *testing.T) {
 	}
 }
 
+func TestGRPCUnknownServiceNotFound(t *testing.T) {
+	port := freePort(t)
+	addr, cleanup := startGRPCProbe(t, port, fakeState{ready: true, started: true})
+	defer cleanup()
+
+	client, conn := grpcHealthClient(t, addr)
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "readyz"})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("Check(unknown): got code %v (%v), want NotFound", code, err)
+	}
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "readyz"})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Watch Recv: %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Errorf("Watch(unknown): got %v, want SERVICE_UNKNOWN", resp.Status)
+	}
+}
+
+func TestGRPCListReturnsAllServices(t *testing.T) {
+	port := freePort(t)
+	addr, cleanup := startGRPCProbe(t, port, fakeState{ready: false, started: true})
+	defer cleanup()
+
+	client, conn := grpcHealthClient(t, addr)
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	resp, err := client.List(ctx, &healthpb.HealthListRequest{})
+	if err != nil {
+		t.Fatalf("health.List: %v", err)
+	}
+	want := map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"ready":   healthpb.HealthCheckResponse_NOT_SERVING,
+		"live":    healthpb.HealthCheckResponse_SERVING,
+		"startup": healthpb.HealthCheckResponse_SERVING,
+	}
+	for service, status := range want {
+		got, ok := resp.Statuses[service]
+		if !ok {
+			t.Errorf("List: service %q missing from %v", service, resp.Statuses)
+			continue
+		}
+		if got.Status != status {
+			t.Errorf("List[%q]: want %v, got %v", service, status, got.Status)
+		}
+	}
+}
+
 func TestGRPCReadyAfterSetStateTrue(t *testing.T) {
 	port := freePort(t)
-	probe := check.NewGRPCProbe(port, 5*time.Second)
+	probe := check.NewGRPCProbe(check.GRPCOptions{Port: port, ShutdownTimeout: 5 * time.Second})
 	started := make(chan struct{})
 	go func() { probe.Start(fakeState{ready: false}, func() { close(started) }) }() //nolint:errcheck
 	<-started
@@ -101,7 +168,7 @@ func TestGRPCReadyAfterSetStateTrue(t *testing.T) {
 
 func TestGRPCReadyAfterSetStateFalse(t *testing.T) {
 	port := freePort(t)
-	probe := check.NewGRPCProbe(port, 5*time.Second)
+	probe := check.NewGRPCProbe(check.GRPCOptions{Port: port, ShutdownTimeout: 5 * time.Second})
 	started := make(chan struct{})
 	go func() { probe.Start(fakeState{ready: true}, func() { close(started) }) }() //nolint:errcheck
 	<-started
@@ -137,7 +204,7 @@ func TestGRPCLiveNormally(t *testing.T) {
 
 func TestGRPCLiveShuttingDown(t *testing.T) {
 	port := freePort(t)
-	probe := check.NewGRPCProbe(port, 5*time.Second)
+	probe := check.NewGRPCProbe(check.GRPCOptions{Port: port, ShutdownTimeout: 5 * time.Second})
 	started := make(chan struct{})
 	go func() { probe.Start(fakeState{}, func() { close(started) }) }() //nolint:errcheck
 	<-started
@@ -158,9 +225,35 @@ func TestGRPCLiveShuttingDown(t *testing.T) {
 	}
 }
 
+func TestGRPCLiveIgnoresShutdown(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	probe := check.NewGRPCProbe(check.GRPCOptions{Listener: lis, LiveIgnoresShutdown: true})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		probe.Shutdown(ctx)
+	}()
+	probe.SetState(false, true)
+
+	client, conn := grpcHealthClient(t, lis.Addr().String())
+	defer func() { _ = conn.Close() }()
+	if got := checkStatus(t, client, "live"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("live (shuttingDown, LiveIgnoresShutdown): want SERVING, got %v", got)
+	}
+	if got := checkStatus(t, client, "ready"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("ready (shuttingDown): want NOT_SERVING, got %v", got)
+	}
+}
+
 func TestGRPCStartupServing(t *testing.T) {
 	port := freePort(t)
-	addr, cleanup := startGRPCProbe(t, port, fakeState{})
+	addr, cleanup := startGRPCProbe(t, port, fakeState{started: true})
 	defer cleanup()
 
 	client, conn := grpcHealthClient(t, addr)
@@ -173,7 +266,7 @@ func TestGRPCStartupServing(t *testing.T) {
 
 func TestGRPCStartupNotServingAfterShutdownState(t *testing.T) {
 	port := freePort(t)
-	probe := check.NewGRPCProbe(port, 5*time.Second)
+	probe := check.NewGRPCProbe(check.GRPCOptions{Port: port, ShutdownTimeout: 5 * time.Second})
 	started := make(chan struct{})
 	go func() { probe.Start(fakeState{}, func() { close(started) }) }() //nolint:errcheck
 	<-started
@@ -195,15 +288,66 @@ func TestGRPCStartupNotServingAfterShutdownState(t *testing.T) {
 }
 
 func TestGRPCSetStateBeforeStartNoPanic(t *testing.T) {
-	probe := check.NewGRPCProbe(freePort(t), 5*time.Second)
+	probe := check.NewGRPCProbe(check.GRPCOptions{Port: freePort(t), ShutdownTimeout: 5 * time.Second})
 	// Should not panic when called before Start.
 	probe.SetState(true, false)
 	probe.SetState(false, true)
 }
 
+func TestGRPCSetStateAfterShutdownNoPanic(t *testing.T) {
+	probe := check.NewGRPCProbe(check.GRPCOptions{Port: freePort(t), ShutdownTimeout: 5 * time.Second})
+	if err := probe.Start(fakeState{ready: true}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	probe.Shutdown(ctx)
+	probe.SetState(true, false)
+	if err := probe.(check.ServiceStatusSetter).SetServiceStatus("app", true); err != nil {
+		t.Errorf("SetServiceStatus after Shutdown: %v", err)
+	}
+}
+
+func TestExistingGRPCProbeSetStateAfterShutdownStaysNotServing(t *testing.T) {
+	port := freePort(t)
+	srv := grpc.NewServer()
+	probe := check.NewExistingGRPCProbe(srv)
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	if err := probe.Start(fakeState{ready: true, started: true}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	probe.Shutdown(ctx)
+
+	probe.SetState(true, false)
+	if err := probe.(check.ServiceStatusSetter).SetServiceStatus("app", true); err != nil {
+		t.Errorf("SetServiceStatus after Shutdown: %v", err)
+	}
+
+	client, conn := grpcHealthClient(t, fmt.Sprintf("127.0.0.1:%d", port))
+	defer func() { _ = conn.Close() }()
+	for _, svc := range []string{"ready", "live", "startup"} {
+		if got := checkStatus(t, client, svc); got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("%s after Shutdown and SetState(true, false): want NOT_SERVING, got %v", svc, got)
+		}
+	}
+	rpcCtx, rpcCancel := context.WithTimeout(context.Background(), time.Second)
+	defer rpcCancel()
+	if resp, err := client.Check(rpcCtx, &healthpb.HealthCheckRequest{Service: "app"}); err == nil {
+		t.Errorf("app after Shutdown: want unknown service, got %v", resp.Status)
+	}
+}
+
 func TestGRPCShutdownClosesListener(t *testing.T) {
 	port := freePort(t)
-	probe := check.NewGRPCProbe(port, 5*time.Second)
+	probe := check.NewGRPCProbe(check.GRPCOptions{Port: port, ShutdownTimeout: 5 * time.Second})
 	started := make(chan struct{})
 	go func() { probe.Start(fakeState{}, func() { close(started) }) }() //nolint:errcheck
 	<-started
@@ -230,7 +374,7 @@ func TestGRPCShutdownClosesListener(t *testing.T) {
 
 func TestGRPCShutdownWithExpiredContextForcesStop(t *testing.T) {
 	port := freePort(t)
-	probe := check.NewGRPCProbe(port, 5*time.Second)
+	probe := check.NewGRPCProbe(check.GRPCOptions{Port: port, ShutdownTimeout: 5 * time.Second})
 	started := make(chan struct{})
 	go func() { probe.Start(fakeState{}, func() { close(started) }) }() //nolint:errcheck
 	<-started
@@ -258,9 +402,55 @@ func TestGRPCShutdownWithExpiredContextForcesStop(t *testing.T) {
 	}
 }
 
+func TestGRPCShutdownReportsDrainProgress(t *testing.T) {
+	port := freePort(t)
+	var mu sync.Mutex
+	var reports []time.Duration
+	probe := check.NewGRPCProbe(check.GRPCOptions{
+		Port:                  port,
+		ShutdownTimeout:       5 * time.Second,
+		DrainProgressInterval: 10 * time.Millisecond,
+		OnDrainProgress: func(elapsed time.Duration) {
+			mu.Lock()
+			reports = append(reports, elapsed)
+			mu.Unlock()
+		},
+	})
+	started := make(chan struct{})
+	go func() { probe.Start(fakeState{}, func() { close(started) }) }() //nolint:errcheck
+	<-started
+
+	// Hold a Watch stream open so GracefulStop blocks until the deadline.
+	client, conn := grpcHealthClient(t, fmt.Sprintf("127.0.0.1:%d", port))
+	defer func() { _ = conn.Close() }()
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "live"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	probe.Shutdown(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) < 2 {
+		t.Fatalf("want at least 2 progress reports during a 100ms drain, got %d", len(reports))
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i] <= reports[i-1] {
+			t.Errorf("elapsed not increasing: %v", reports)
+			break
+		}
+	}
+}
+
 func TestGRPCConcurrentSetState(t *testing.T) {
 	port := freePort(t)
-	probe := check.NewGRPCProbe(port, 5*time.Second)
+	probe := check.NewGRPCProbe(check.GRPCOptions{Port: port, ShutdownTimeout: 5 * time.Second})
 	started := make(chan struct{})
 	go func() { probe.Start(fakeState{}, func() { close(started) }) }() //nolint:errcheck
 	<-started
@@ -282,6 +472,39 @@ func TestGRPCConcurrentSetState(t *testing.T) {
 	wg.Wait()
 }
 
+// TestGRPCSetStateAtomic races a ready update against a shutdown update and
+// checks that the statuses always reflect one of them in full, never a mix
+// such as ready SERVING with live NOT_SERVING.
+func TestGRPCSetStateAtomic(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	probe := check.NewGRPCProbe(check.GRPCOptions{Listener: lis})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		probe.Shutdown(ctx)
+	}()
+	client, conn := grpcHealthClient(t, lis.Addr().String())
+	defer func() { _ = conn.Close() }()
+
+	for round := 0; round < 50; round++ {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); probe.SetState(true, false) }()
+		go func() { defer wg.Done(); probe.SetState(false, true) }()
+		wg.Wait()
+		ready, live := checkStatus(t, client, "ready"), checkStatus(t, client, "live")
+		if ready != live {
+			t.Fatalf("round %d: ready=%v live=%v, want both from the same SetState", round, ready, live)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // existingGRPCProbe tests
 // ---------------------------------------------------------------------------
@@ -317,6 +540,38 @@ func TestExistingGRPCProbeServingAfterStart(t *testing.T) {
 	}
 }
 
+// TestExistingGRPCProbeCustomService verifies that application services can
+// be reported on the shared health server and built-in names are refused.
+func TestExistingGRPCProbeCustomService(t *testing.T) {
+	port := freePort(t)
+	srv := grpc.NewServer()
+	probe := check.NewExistingGRPCProbe(srv)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	setter, ok := probe.(check.ServiceStatusSetter)
+	if !ok {
+		t.Fatal("existing gRPC probe does not implement ServiceStatusSetter")
+	}
+	if err := setter.SetServiceStatus("live", false); !errors.Is(err, check.ErrReservedService) {
+		t.Errorf("built-in service: got %v, want ErrReservedService", err)
+	}
+	if err := setter.SetServiceStatus("myapp.v1.Orders", false); err != nil {
+		t.Fatal(err)
+	}
+
+	client, conn := grpcHealthClient(t, fmt.Sprintf("127.0.0.1:%d", port))
+	defer func() { _ = conn.Close() }()
+	if got := checkStatus(t, client, "myapp.v1.Orders"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("custom service: want NOT_SERVING, got %v", got)
+	}
+}
+
 // TestExistingGRPCProbeSetStateTransitions verifies that SetState correctly
 // changes health statuses on the shared server.
 func TestExistingGRPCProbeSetStateTransitions(t *testing.T) {
@@ -361,6 +616,38 @@ func TestExistingGRPCProbeSetStateTransitions(t *testing.T) {
 	}
 }
 
+func TestExistingGRPCProbeServiceStatuses(t *testing.T) {
+	probe := check.NewExistingGRPCProbe(grpc.NewServer())
+	reader, ok := probe.(check.ServiceStatusReader)
+	if !ok {
+		t.Fatal("existing gRPC probe does not implement ServiceStatusReader")
+	}
+	if err := probe.Start(fakeState{started: true}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if got := reader.ServiceStatuses()["ready"]; got != "NOT_SERVING" {
+		t.Errorf("before SetState: ready = %q, want NOT_SERVING", got)
+	}
+
+	probe.SetState(true, false)
+	if err := probe.(check.ServiceStatusSetter).SetServiceStatus("myapp.v1.Orders", true); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"ready": "SERVING", "live": "SERVING", "startup": "SERVING", "myapp.v1.Orders": "SERVING"}
+	if got := reader.ServiceStatuses(); !maps.Equal(got, want) {
+		t.Errorf("after SetState(true,false): got %v, want %v", got, want)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	probe.Shutdown(ctx)
+	for service, status := range reader.ServiceStatuses() {
+		if status != "NOT_SERVING" {
+			t.Errorf("after Shutdown: %s = %q, want NOT_SERVING", service, status)
+		}
+	}
+}
+
 // TestExistingGRPCProbeShutdownMarksNotServingButKeepsServerRunning verifies
 // that Shutdown marks health NOT_SERVING but does NOT stop the underlying server.
 func TestExistingGRPCProbeShutdownMarksNotServingButKeepsServerRunning(t *testing.T) {
@@ -428,3 +715,323 @@ func TestGRPCUsesConfiguredPort(t *testing.T) {
 	}
 	_ = addr
 }
+
+func TestGRPCProbeServesOnProvidedListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	probe := check.NewGRPCProbeWithListener(ln, time.Second)
+	if err := probe.Start(fakeState{ready: true}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer probe.Shutdown(context.Background())
+
+	client, conn := grpcHealthClient(t, ln.Addr().String())
+	defer func() { _ = conn.Close() }()
+	if got := checkStatus(t, client, "ready"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("ready: want SERVING, got %v", got)
+	}
+}
+
+// TestManagedGRPCProbeStopsServer verifies that Shutdown stops the managed
+// server, so its Serve call returns.
+func TestManagedGRPCProbeStopsServer(t *testing.T) {
+	srv := grpc.NewServer()
+	probe := check.NewManagedGRPCProbe(srv, check.GRPCOptions{})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(lis) }()
+
+	if err := probe.Start(fakeState{ready: true}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	probe.Shutdown(ctx)
+
+	select {
+	case <-served:
+	case <-time.After(2 * time.Second):
+		srv.Stop()
+		t.Fatal("managed server still serving after Shutdown")
+	}
+}
+
+func TestExistingGRPCProbeLiveIgnoresShutdown(t *testing.T) {
+	srv := grpc.NewServer()
+	probe := check.NewExistingGRPCProbeWithOptions(srv, check.GRPCOptions{LiveIgnoresShutdown: true})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	if err := probe.Start(fakeState{ready: true, started: true}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	probe.SetState(false, true)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	probe.Shutdown(ctx)
+
+	client, conn := grpcHealthClient(t, lis.Addr().String())
+	defer func() { _ = conn.Close() }()
+	if got := checkStatus(t, client, "live"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("live after Shutdown: want SERVING, got %v", got)
+	}
+	for _, service := range []string{"ready", "startup"} {
+		if got := checkStatus(t, client, service); got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("%s after Shutdown: want NOT_SERVING, got %v", service, got)
+		}
+	}
+}
+
+func TestManagedGRPCProbeLiveIgnoresShutdown(t *testing.T) {
+	srv := grpc.NewServer()
+	probe := check.NewManagedGRPCProbe(srv, check.GRPCOptions{LiveIgnoresShutdown: true})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+
+	if err := probe.Start(fakeState{ready: true, started: true}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	probe.SetState(false, true)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	probe.Shutdown(ctx)
+
+	want := map[string]string{"ready": "NOT_SERVING", "live": "SERVING", "startup": "NOT_SERVING"}
+	if got := probe.(check.ServiceStatusReader).ServiceStatuses(); !maps.Equal(got, want) {
+		t.Errorf("after Shutdown: got %v, want %v", got, want)
+	}
+}
+
+func TestGRPCProbeReflectionListsHealth(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	probe := check.NewGRPCProbe(check.GRPCOptions{Listener: lis, Reflection: true})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		probe.Shutdown(ctx)
+	}()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("ServerReflectionInfo: %v", err)
+	}
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		if svc.GetName() == healthpb.Health_ServiceDesc.ServiceName {
+			return
+		}
+	}
+	t.Errorf("health service not listed: %v", resp.GetListServicesResponse().GetService())
+}
+
+func TestGRPCProbeChannelzListsServer(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	probe := check.NewGRPCProbe(check.GRPCOptions{Listener: lis, Channelz: true})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		probe.Shutdown(ctx)
+	}()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	resp, err := channelzpb.NewChannelzClient(conn).GetServers(ctx, &channelzpb.GetServersRequest{})
+	if err != nil {
+		t.Fatalf("GetServers: %v", err)
+	}
+	if len(resp.GetServer()) == 0 {
+		t.Error("channelz reported no servers")
+	}
+}
+
+func TestGRPCProbeMaxRecvMsgSize(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	probe := check.NewGRPCProbe(check.GRPCOptions{Listener: lis, MaxRecvMsgSize: 64})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		probe.Shutdown(ctx)
+	}()
+
+	client, conn := grpcHealthClient(t, lis.Addr().String())
+	defer func() { _ = conn.Close() }()
+
+	if got := checkStatus(t, client, "live"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("small request: want SERVING, got %v", got)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: strings.Repeat("x", 1024)})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("large request: got %v, want ResourceExhausted", err)
+	}
+}
+
+// mutableState is a StateReader whose started flag can change after Start.
+type mutableState struct {
+	fakeState
+	started atomic.Bool
+}
+
+func (m *mutableState) Started() bool { return m.started.Load() }
+
+func TestGRPCStartupFollowsStarted(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	probe := check.NewGRPCProbe(check.GRPCOptions{Listener: lis})
+	state := &mutableState{}
+	if err := probe.Start(state, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		probe.Shutdown(ctx)
+	}()
+	client, conn := grpcHealthClient(t, lis.Addr().String())
+	defer func() { _ = conn.Close() }()
+
+	if got := checkStatus(t, client, "startup"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("before started: want NOT_SERVING, got %v", got)
+	}
+	state.started.Store(true)
+	probe.SetState(false, false)
+	if got := checkStatus(t, client, "startup"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("after started: want SERVING, got %v", got)
+	}
+}
+
+func TestGRPCStartupShutdownGrace(t *testing.T) {
+	const grace = 300 * time.Millisecond
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	probe := check.NewGRPCProbe(check.GRPCOptions{Listener: lis, StartupShutdownGrace: grace})
+	if err := probe.Start(fakeState{started: true}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	client, conn := grpcHealthClient(t, lis.Addr().String())
+	defer func() { _ = conn.Close() }()
+
+	probe.SetState(false, true)
+	for _, svc := range []string{"ready", "live"} {
+		if got := checkStatus(t, client, svc); got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("%s while shutting down: want NOT_SERVING, got %v", svc, got)
+		}
+	}
+	if got := checkStatus(t, client, "startup"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("startup before grace: want SERVING, got %v", got)
+	}
+
+	watchCtx, cancelWatch := context.WithCancel(context.Background())
+	defer cancelWatch()
+	stream, err := client.Watch(watchCtx, &healthpb.HealthCheckRequest{Service: "startup"})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if resp, err := stream.Recv(); err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("initial watch: got (%v, %v), want SERVING", resp, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	begin := time.Now()
+	go probe.Shutdown(ctx)
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("startup after grace: want NOT_SERVING, got %v", resp.Status)
+	}
+	if elapsed := time.Since(begin); elapsed < grace {
+		t.Errorf("startup flipped after %v, want at least %v", elapsed, grace)
+	}
+}
+
+func TestGRPCOnForcedStop(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	var forced atomic.Int32
+	probe := check.NewGRPCProbe(check.GRPCOptions{Listener: lis, OnForcedStop: func() { forced.Add(1) }})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	client, conn := grpcHealthClient(t, lis.Addr().String())
+	defer func() { _ = conn.Close() }()
+
+	// An open Watch stream keeps GracefulStop waiting past the deadline.
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "live"})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	probe.Shutdown(ctx)
+	if got := forced.Load(); got != 1 {
+		t.Errorf("OnForcedStop calls: got %d, want 1", got)
+	}
+}
@@ -0,0 +1,58 @@
+package check
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type grpcHealthChecker struct {
+	client  healthpb.HealthClient
+	service string
+}
+
+// NewGRPCHealthChecker returns a Checker that calls the standard gRPC health
+// Check RPC for service on conn (an empty service asks about the server as a
+// whole). Any status other than SERVING, or an RPC error, fails the check.
+// The call uses the checker context's deadline.
+func NewGRPCHealthChecker(conn grpc.ClientConnInterface, service string) Checker {
+	return &grpcHealthChecker{client: healthpb.NewHealthClient(conn), service: service}
+}
+
+func (g *grpcHealthChecker) Check(ctx context.Context) error {
+	resp, err := g.client.Check(ctx, &healthpb.HealthCheckRequest{Service: g.service})
+	if err != nil {
+		return fmt.Errorf("grpc health %q: %w", g.service, err)
+	}
+	if s := resp.GetStatus(); s != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("grpc health %q: %s", g.service, s)
+	}
+	return nil
+}
+
+type healthServerChecker struct {
+	hs      *health.Server
+	service string
+}
+
+// NewHealthServerChecker returns a Checker that passes only while service is
+// SERVING on hs, e.g. a subsystem's health server embedded in the same
+// process. It calls hs.Check directly, without an RPC; an unknown service
+// fails the check.
+func NewHealthServerChecker(hs *health.Server, service string) Checker {
+	return &healthServerChecker{hs: hs, service: service}
+}
+
+func (h *healthServerChecker) Check(ctx context.Context) error {
+	resp, err := h.hs.Check(ctx, &healthpb.HealthCheckRequest{Service: h.service})
+	if err != nil {
+		return fmt.Errorf("health server %q: %w", h.service, err)
+	}
+	if s := resp.GetStatus(); s != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health server %q: %s", h.service, s)
+	}
+	return nil
+}
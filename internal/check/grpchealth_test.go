@@ -0,0 +1,75 @@
+package check_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func TestGRPCHealthChecker(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	c := check.NewGRPCHealthChecker(conn, "orders")
+	run := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		return c.Check(ctx)
+	}
+
+	if err := run(); err == nil {
+		t.Error("unknown service: expected error, got nil")
+	}
+	hs.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	if err := run(); err != nil {
+		t.Errorf("SERVING: unexpected error: %v", err)
+	}
+	hs.SetServingStatus("orders", healthpb.HealthCheckResponse_NOT_SERVING)
+	if err := run(); err == nil {
+		t.Error("NOT_SERVING: expected error, got nil")
+	}
+}
+
+func TestHealthServerChecker(t *testing.T) {
+	hs := health.NewServer()
+	c := check.NewHealthServerChecker(hs, "billing")
+	ctx := context.Background()
+
+	if err := c.Check(ctx); err == nil {
+		t.Error("unknown service: expected error, got nil")
+	}
+	hs.SetServingStatus("billing", healthpb.HealthCheckResponse_SERVING)
+	if err := c.Check(ctx); err != nil {
+		t.Errorf("SERVING: unexpected error: %v", err)
+	}
+	hs.SetServingStatus("billing", healthpb.HealthCheckResponse_NOT_SERVING)
+	if err := c.Check(ctx); err == nil {
+		t.Error("NOT_SERVING: expected error, got nil")
+	}
+	hs.SetServingStatus("billing", healthpb.HealthCheckResponse_SERVING)
+	hs.Shutdown()
+	if err := c.Check(ctx); err == nil {
+		t.Error("after Shutdown: expected error, got nil")
+	}
+}
@@ -0,0 +1,89 @@
+package check
+
+import (
+	"maps"
+	"sync"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ServiceStatusReader is implemented by the gRPC probes, which remember the
+// last status applied to each health service.
+type ServiceStatusReader interface {
+	ServiceStatuses() map[string]string
+}
+
+// trackedHealth is a health.Server that records the statuses it is given,
+// since health.Server has no getter that avoids an RPC.
+type trackedHealth struct {
+	*health.Server
+	mu       sync.Mutex
+	statuses map[string]string
+	shutdown bool
+}
+
+func newTrackedHealth() *trackedHealth {
+	return &trackedHealth{Server: health.NewServer(), statuses: make(map[string]string)}
+}
+
+func (h *trackedHealth) SetServingStatus(service string, status healthpb.HealthCheckResponse_ServingStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Server.SetServingStatus(service, status)
+	if !h.shutdown {
+		h.statuses[service] = status.String()
+	}
+}
+
+// Shutdown sets every service NOT_SERVING and ignores later updates, like
+// health.Server.Shutdown.
+func (h *trackedHealth) Shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Server.Shutdown()
+	h.shutdown = true
+	for service := range h.statuses {
+		h.statuses[service] = healthpb.HealthCheckResponse_NOT_SERVING.String()
+	}
+}
+
+// shutdownExcept sets the overall "" service and every recorded service but
+// keep NOT_SERVING. Unlike Shutdown, later updates still apply.
+func (h *trackedHealth) shutdownExcept(keep string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	for service := range h.statuses {
+		if service != keep {
+			h.Server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+			h.statuses[service] = healthpb.HealthCheckResponse_NOT_SERVING.String()
+		}
+	}
+}
+
+// snapshot returns a copy of the recorded statuses.
+func (h *trackedHealth) snapshot() map[string]string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return maps.Clone(h.statuses)
+}
+
+// ServiceStatuses returns the last status applied to each health service
+// ("ready", "live", "startup", and application services), as "SERVING" or
+// "NOT_SERVING". It is nil before Start.
+func (g *grpcProbe) ServiceStatuses() map[string]string {
+	g.mu.Lock()
+	hs := g.health
+	g.mu.Unlock()
+	if hs == nil {
+		return nil
+	}
+	return hs.snapshot()
+}
+
+// ServiceStatuses returns the last status applied to each health service;
+// see grpcProbe.ServiceStatuses.
+func (e *existingGRPCProbe) ServiceStatuses() map[string]string {
+	return e.health.snapshot()
+}
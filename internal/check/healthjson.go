@@ -0,0 +1,118 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// healthJSONContentType is the media type of the IETF "Health Check Response
+// Format for HTTP APIs" draft.
+const healthJSONContentType = "application/health+json"
+
+// healthJSONFields are the keys of the application/health+json body that
+// HTTPOptions.HealthJSONFieldNames may rename.
+var healthJSONFields = []string{"status", "checks", "componentType", "time", "output"}
+
+// IsHealthJSONField reports whether name is a key of the
+// application/health+json body that HTTPOptions.HealthJSONFieldNames may
+// rename.
+func IsHealthJSONField(name string) bool {
+	return slices.Contains(healthJSONFields, name)
+}
+
+type healthResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string][]healthComponent `json:"checks,omitempty"`
+}
+
+type healthComponent struct {
+	ComponentType string `json:"componentType"`
+	Status        string `json:"status"`
+	Time          string `json:"time"`
+	Output        string `json:"output,omitempty"`
+}
+
+// writeHealthJSON writes a /ready response in application/health+json form.
+// The top-level status is "pass", "fail", or "warn" when checkers failed
+// within the configured FailureTolerance or a DetailedChecker warned.
+func writeHealthJSON(w http.ResponseWriter, allOK bool, results map[string]string, opts *HTTPOptions) {
+	resp := healthResponse{Status: "pass"}
+	failed, warned := false, false
+	if len(results) > 0 {
+		now := clockOr(opts.Clock).Now().UTC().Format(time.RFC3339)
+		resp.Checks = make(map[string][]healthComponent, len(results))
+		for name, v := range results {
+			c := healthComponent{ComponentType: "component", Status: "pass", Time: now}
+			switch {
+			case v == resultDisabled:
+				c.Output = resultDisabled
+			case resultWarned(v):
+				c.Status = "warn"
+				c.Output = strings.TrimPrefix(v, resultWarnPrefix)
+				warned = true
+			case resultFailed(v):
+				c.Status = "fail"
+				c.Output = strings.TrimPrefix(v, "error: ")
+				failed = true
+			}
+			resp.Checks[name] = []healthComponent{c}
+		}
+	}
+	code := http.StatusOK
+	switch {
+	case !allOK:
+		resp.Status = "fail"
+		code = opts.unhealthyCode()
+	case failed || warned:
+		resp.Status = "warn"
+	}
+	var body any = resp
+	if len(opts.HealthJSONFieldNames) > 0 {
+		body = resp.renamed(opts.HealthJSONFieldNames)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		reportError(opts, fmt.Errorf("encode /ready body: %w", err))
+		w.WriteHeader(code)
+		return
+	}
+	w.Header().Set("Content-Type", healthJSONContentType)
+	w.WriteHeader(code)
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		reportError(opts, fmt.Errorf("write /ready body: %w", err))
+	}
+}
+
+// renamed returns r as maps keyed by the names in names, falling back to the
+// built-in names, with the same omission rules as the struct tags.
+func (r healthResponse) renamed(names map[string]string) map[string]any {
+	key := func(name string) string {
+		if n, ok := names[name]; ok {
+			return n
+		}
+		return name
+	}
+	out := map[string]any{key("status"): r.Status}
+	if r.Checks != nil {
+		checks := make(map[string][]map[string]string, len(r.Checks))
+		for name, components := range r.Checks {
+			for _, c := range components {
+				m := map[string]string{
+					key("componentType"): c.ComponentType,
+					key("status"):        c.Status,
+					key("time"):          c.Time,
+				}
+				if c.Output != "" {
+					m[key("output")] = c.Output
+				}
+				checks[name] = append(checks[name], m)
+			}
+		}
+		out[key("checks")] = checks
+	}
+	return out
+}
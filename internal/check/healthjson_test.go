@@ -0,0 +1,119 @@
+package check_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+type healthBody struct {
+	Status string `json:"status"`
+	Checks map[string][]struct {
+		ComponentType string `json:"componentType"`
+		Status        string `json:"status"`
+		Time          string `json:"time"`
+		Output        string `json:"output"`
+	} `json:"checks"`
+}
+
+func TestHealthJSONFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      fakeState
+		tolerance  float64
+		wantCode   int
+		wantStatus string
+	}{
+		{"failing", fakeState{ready: true}, 0, http.StatusServiceUnavailable, "fail"},
+		{"tolerated", fakeState{ready: true}, 0.5, http.StatusOK, "warn"},
+		{"not ready", fakeState{}, 0, http.StatusServiceUnavailable, "fail"},
+	}
+	for _, tc := range tests {
+		mux := http.NewServeMux()
+		opts := check.HTTPOptions{
+			Checkers: check.NewRegistry(map[string]check.Checker{
+				"db":    okChecker{},
+				"cache": errChecker{msg: "connection refused"},
+			}),
+			CheckerTimeout:   time.Second,
+			HealthJSON:       true,
+			FailureTolerance: tc.tolerance,
+		}
+		check.NewExistingHTTPProbe(mux, opts).Start(tc.state, func() {}) //nolint:errcheck
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+		if rec.Code != tc.wantCode {
+			t.Errorf("%s: code %d, want %d", tc.name, rec.Code, tc.wantCode)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/health+json" {
+			t.Errorf("%s: Content-Type %q", tc.name, ct)
+		}
+		var body healthBody
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: decode: %v", tc.name, err)
+		}
+		if body.Status != tc.wantStatus {
+			t.Errorf("%s: status %q, want %q", tc.name, body.Status, tc.wantStatus)
+		}
+		if !tc.state.ready {
+			if body.Checks != nil {
+				t.Errorf("%s: checks present although checkers did not run: %v", tc.name, body.Checks)
+			}
+			continue
+		}
+		db, cache := body.Checks["db"], body.Checks["cache"]
+		if len(db) != 1 || db[0].Status != "pass" || db[0].ComponentType != "component" || db[0].Output != "" {
+			t.Errorf("%s: db entry %+v", tc.name, db)
+		}
+		if len(cache) != 1 || cache[0].Status != "fail" || cache[0].Output != "connection refused" {
+			t.Errorf("%s: cache entry %+v", tc.name, cache)
+		}
+		if _, err := time.Parse(time.RFC3339, cache[0].Time); err != nil {
+			t.Errorf("%s: time %q: %v", tc.name, cache[0].Time, err)
+		}
+	}
+}
+
+func TestHealthJSONFieldNames(t *testing.T) {
+	mux := http.NewServeMux()
+	check.NewExistingHTTPProbe(mux, check.HTTPOptions{
+		Checkers: check.NewRegistry(map[string]check.Checker{
+			"db":    okChecker{},
+			"cache": errChecker{msg: "connection refused"},
+		}),
+		CheckerTimeout:       time.Second,
+		HealthJSON:           true,
+		HealthJSONFieldNames: map[string]string{"status": "state", "output": "err", "checks": "results"},
+	}).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+	var body struct {
+		State   string                         `json:"state"`
+		Results map[string][]map[string]string `json:"results"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.State != "fail" {
+		t.Errorf("state: got %q, want fail in %s", body.State, rec.Body)
+	}
+	cache := body.Results["cache"]
+	if len(cache) != 1 || cache[0]["state"] != "fail" || cache[0]["err"] != "connection refused" || cache[0]["componentType"] != "component" {
+		t.Errorf("cache: got %v, want renamed state and err keys", cache)
+	}
+	if db := body.Results["db"]; len(db) != 1 || db[0]["state"] != "pass" || len(db[0]) != 3 {
+		t.Errorf("db: got %v, want state, componentType, and time only", db)
+	}
+	for _, old := range []string{`"status"`, `"output"`, `"checks"`} {
+		if strings.Contains(rec.Body.String(), old) {
+			t.Errorf("body still has the built-in key %s: %s", old, rec.Body)
+		}
+	}
+}
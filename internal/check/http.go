@@ -1,134 +1,1035 @@
 package check
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"net"
 	"net/http"
+	"net/http/pprof"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
+// HTTPOptions configures the HTTP probe handlers and, for the standalone
+// probe, its server.
+type HTTPOptions struct {
+	Port int
+	// Listener, when set, is served instead of binding Port. The probe takes
+	// ownership and closes it on Shutdown.
+	Listener net.Listener
+	// ReadyPort and LivePort, when non-zero, move /ready and /live to their
+	// own listeners on the standalone probe. /startup always stays on Port.
+	ReadyPort int
+	LivePort  int
+	// PingPath, when set, registers a handler that returns 200 while the
+	// probe serves and 503 once shutdown begins, without running checkers.
+	PingPath string
+	// StatusPath, when set together with Status, registers a GET handler that
+	// writes Status() as JSON, for debugging.
+	StatusPath string
+	Status     func() any
+	// MetricsPath, when set together with Metrics, registers a GET handler
+	// that writes Metrics() as Prometheus text exposition.
+	MetricsPath     string
+	Metrics         func() []byte
+	ShutdownTimeout time.Duration
+	// ReadHeaderTimeout bounds how long the standalone server waits for
+	// request headers. Zero uses the 2s ReadTimeout; a longer value raises
+	// ReadTimeout to match.
+	ReadHeaderTimeout time.Duration
+	CheckerTimeout    time.Duration
+	// MaxCheckerErrorLen truncates checker error messages in /ready bodies to
+	// this many characters, plus an ellipsis. Zero means no limit.
+	MaxCheckerErrorLen int
+	Checkers           *Registry
+	ErrorHandler       func(error)
+	// UniformJSONBodies makes status-only responses carry a small JSON body
+	// ({"status":"ok"} or {"status":"unavailable"}) instead of an empty one.
+	UniformJSONBodies bool
+	// Pprof registers the net/http/pprof handlers under /debug/pprof/.
+	Pprof  bool
+	Listen ListenOptions
+	// ReadyResponseWriter, when set, writes every /ready response in place
+	// of the built-in status/JSON logic. results is nil when checkers did not
+	// run (not ready, shutting down, or none registered).
+	ReadyResponseWriter func(w http.ResponseWriter, ok bool, results map[string]string)
+	// VersionHeaderName and VersionHeaderValue, when the name is set, add a
+	// constant header to every /ready, /live, and /startup response.
+	VersionHeaderName  string
+	VersionHeaderValue string
+	// OnReadyServed, when set, is called after each /ready response with the
+	// verdict that was served.
+	OnReadyServed func(ok bool)
+	// UnhealthyStatusCode replaces 503 in failing probe responses. Zero
+	// means 503.
+	UnhealthyStatusCode int
+	// LoadGate, when set, reports the current load and its limit; /ready
+	// fails with reason "overloaded" while load >= limit.
+	LoadGate func() (load, limit int)
+	// OnCheckerFailure and OnCheckerRecovery, when set, are called when a
+	// checker goes from passing (or never run) to failing, and from failing
+	// back to passing. Repeated failures are reported once.
+	OnCheckerFailure  func(name string, err error)
+	OnCheckerRecovery func(name string)
+	// OnReadinessFailure, when set, is called once per /ready evaluation in
+	// which any checker failed, with all failures joined into one error.
+	OnReadinessFailure func(error)
+	// LiveIgnoresShutdown keeps /live at 200 while shutting down, so only
+	// /ready signals the drain.
+	LiveIgnoresShutdown bool
+	// HealthJSON makes /ready answer in the application/health+json format
+	// (status pass/warn/fail plus per-checker entries) instead of the flat
+	// name→result map.
+	HealthJSON bool
+	// HealthJSONFieldNames renames keys of the HealthJSON body, e.g.
+	// {"status": "state", "output": "err"}. "status" renames both the
+	// top-level and the per-checker key. Unset keys keep their names.
+	HealthJSONFieldNames map[string]string
+	// PathPrefix, when set, is prepended to every probe path registered on an
+	// existing mux, e.g. "/internal" serves "/internal/ready". pprof keeps
+	// its /debug/pprof/ paths.
+	PathPrefix string
+	// OnForcedStop, when set, is called once per Shutdown in which the
+	// context expired with requests still in flight.
+	OnForcedStop func()
+	// FailureTolerance is the fraction of enabled checkers (0 to 1) that may
+	// fail while /ready still reports 200. Zero fails on any checker failure.
+	FailureTolerance float64
+	// Clock timestamps checker results. Nil means RealClock.
+	Clock Clock
+	// FailureBody, when set, returns the body of failing /live and /startup
+	// responses for the given endpoint path. JSON bodies are sent as
+	// application/json, anything else as text/plain.
+	FailureBody func(endpoint string) string
+	// AccessLog, when set, receives one debug-level line per probe request
+	// with method, path, status, duration, and remote address.
+	AccessLog *slog.Logger
+	// RootHandler makes the standalone probe answer / with a short plain-text
+	// list of its endpoints and /favicon.ico with 204, instead of 404.
+	RootHandler bool
+	// InternalPort, when non-zero, serves every endpoint on a second listener
+	// for in-cluster use. The other listeners then answer /ready, /live,
+	// /startup, and ping with status codes only, and the status, metrics, and
+	// pprof endpoints move to InternalPort. InternalVerbose keeps bodies and
+	// detail headers on the internal listener; otherwise it is terse too.
+	InternalPort    int
+	InternalVerbose bool
+	// ReadyBodyOnFailureOnly omits the checker results from passing /ready
+	// responses, which are then an empty 200.
+	ReadyBodyOnFailureOnly bool
+	// Middleware wraps the handler of every standalone probe listener, the
+	// first entry outermost.
+	Middleware []func(http.Handler) http.Handler
+	// ResultMarshaler, when set, encodes /ready checker results in place of
+	// the JSON map and returns the body with its Content-Type.
+	ResultMarshaler func(results map[string]string) ([]byte, string, error)
+	// RunCheckersDuringShutdown keeps running checkers on /ready while
+	// shutting down; the verdict still fails but the body carries results.
+	RunCheckersDuringShutdown bool
+	// ForceCloseOnTimeout closes the servers when Shutdown's context
+	// expires, cutting off requests still in flight.
+	ForceCloseOnTimeout bool
+	// FailureLog, when set, receives a warning for one in every
+	// FailureLogEvery failing /ready, /live, and /startup responses.
+	FailureLog      *slog.Logger
+	FailureLogEvery int
+	// DetachedCheckerContext runs /ready checkers under a context that a
+	// client disconnect does not cancel; CheckerTimeout still bounds them.
+	DetachedCheckerContext bool
+	// FailOnWarn counts DetailedChecker warnings as failures, subject to
+	// FailureTolerance, instead of only reporting them in the body.
+	FailOnWarn bool
+	// ReadinessDecider, when set, decides the /ready verdict from the
+	// checker results in place of FailureTolerance and FailOnWarn.
+	ReadinessDecider func(results map[string]string) bool
+	// HTTP2Cleartext lets the standalone listeners accept HTTP/2 without TLS
+	// (h2c, prior knowledge) next to HTTP/1.1.
+	HTTP2Cleartext bool
+	// Compress gzip-encodes response bodies of at least 512 bytes when the
+	// client sends Accept-Encoding: gzip.
+	Compress bool
+	// ReadyRateLimit caps /ready checker runs at this many per second;
+	// requests over the rate are answered from the last results. Zero means
+	// no limit.
+	ReadyRateLimit int
+	// CheckerDependencies maps a checker name to its prerequisites. A
+	// checker waits for its prerequisites and is skipped, with result
+	// "skipped: <names> failed", when any of them fails. The graph must be
+	// acyclic.
+	CheckerDependencies map[string][]string
+
+	failures     *failureSampler
+	readyLimiter *readyLimiter
+}
+
+// unhealthyCode returns the status code for failing probe responses.
+func (o *HTTPOptions) unhealthyCode() int {
+	if o.UnhealthyStatusCode == 0 {
+		return http.StatusServiceUnavailable
+	}
+	return o.UnhealthyStatusCode
+}
+
+const readTimeout = 2 * time.Second
+
 type httpProbe struct {
-	port            int
-	shutdownTimeout time.Duration
-	checkerTimeout  time.Duration
-	checkers        map[string]Checker
-	errHandler      func(error)
-	server          *http.Server
-	mu              sync.Mutex
+	opts    HTTPOptions
+	servers []*http.Server
+	mu      sync.Mutex
 }
 
 // NewHTTPProbe returns a Server that serves /ready, /live, /startup over HTTP.
-func NewHTTPProbe(port int, shutdownTimeout, checkerTimeout time.Duration, checkers map[string]Checker, errHandler func(error)) Server {
-	return &httpProbe{
-		port:            port,
-		shutdownTimeout: shutdownTimeout,
-		checkerTimeout:  checkerTimeout,
-		checkers:        checkers,
-		errHandler:      errHandler,
+func NewHTTPProbe(opts HTTPOptions) Server {
+	if opts.Checkers == nil {
+		opts.Checkers = NewRegistry(nil)
 	}
+	opts.failures = newFailureSampler(opts.FailureLog, opts.FailureLogEvery)
+	opts.readyLimiter = newReadyLimiter(opts.ReadyRateLimit, opts.Clock)
+	return &httpProbe{opts: opts}
 }
 
 func (h *httpProbe) Start(state StateReader, onStarted func()) error {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/ready", onlyGET(h.readyHandler(state)))
-	mux.HandleFunc("/live", onlyGET(func(w http.ResponseWriter, _ *http.Request) {
-		if state.ShuttingDown() {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			return
+	// Group endpoints by port; the main port also carries pprof.
+	// With an internal listener, the others are terse and detail endpoints
+	// are served only internally.
+	handlers := probeHandlers(state, &h.opts)
+	internal := h.opts.InternalPort != 0
+	muxes := map[int]*http.ServeMux{h.opts.Port: http.NewServeMux()}
+	ports := []int{h.opts.Port}
+	for _, ep := range []struct {
+		pattern string
+		port    int
+		detail  bool
+	}{
+		{"/ready", h.opts.ReadyPort, false},
+		{"/live", h.opts.LivePort, false},
+		{"/startup", 0, false},
+		{h.opts.PingPath, 0, false},
+		{h.opts.StatusPath, 0, true},
+		{h.opts.MetricsPath, 0, true},
+	} {
+		handler, ok := handlers[ep.pattern]
+		if !ok || (internal && ep.detail) {
+			continue
 		}
-		w.WriteHeader(http.StatusOK)
-	}))
-	mux.HandleFunc("/startup", onlyGET(func(w http.ResponseWriter, _ *http.Request) {
-		if state.Started() {
-			w.WriteHeader(http.StatusOK)
-			return
+		if internal {
+			handler = terse(handler)
 		}
-		w.WriteHeader(http.StatusServiceUnavailable)
-	}))
+		port := ep.port
+		if port == 0 {
+			port = h.opts.Port
+		}
+		mux, ok := muxes[port]
+		if !ok {
+			mux = http.NewServeMux()
+			muxes[port] = mux
+			ports = append(ports, port)
+		}
+		mux.HandleFunc(ep.pattern, handler)
+	}
+	if h.opts.Pprof && !internal {
+		registerPprof(muxes[h.opts.Port])
+	}
+	if h.opts.RootHandler {
+		registerRoot(muxes[h.opts.Port], &h.opts)
+	}
+	if internal {
+		muxes[h.opts.InternalPort] = internalMux(state, &h.opts)
+		ports = append(ports, h.opts.InternalPort)
+	}
 
-	srv := &http.Server{
-		Addr:         net.JoinHostPort("", fmt.Sprintf("%d", h.port)),
-		Handler:      mux,
-		ReadTimeout:  2 * time.Second,
-		WriteTimeout: 2 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	servers := make([]*http.Server, 0, len(ports))
+	listeners := make([]net.Listener, 0, len(ports))
+	for i, port := range ports {
+		srv := h.newServer(port, muxes[port])
+		if i == 0 && h.opts.Listener != nil {
+			servers = append(servers, srv)
+			listeners = append(listeners, h.opts.Listener)
+			continue
+		}
+		ln, err := listen(srv.Addr, h.opts.Listen)
+		if err != nil {
+			// Roll back a partial start: nothing is serving yet, so closing
+			// the listeners bound so far frees their ports.
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			return err
+		}
+		servers = append(servers, srv)
+		listeners = append(listeners, ln)
 	}
 	h.mu.Lock()
-	h.server = srv
+	h.servers = servers
 	h.mu.Unlock()
 
-	ln, err := net.Listen("tcp", srv.Addr)
-	if err != nil {
+	onStarted()
+	for i, srv := range servers {
+		srv, ln := srv, listeners[i]
+		go func() {
+			if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+				reportError(&h.opts, err)
+			}
+		}()
+	}
+	return nil
+}
+
+func (h *httpProbe) newServer(port int, handler http.Handler) *http.Server {
+	for i := len(h.opts.Middleware) - 1; i >= 0; i-- {
+		handler = h.opts.Middleware[i](handler)
+	}
+	srv := &http.Server{
+		Addr:              h.opts.Listen.addr(port),
+		Handler:           handler,
+		ReadTimeout:       readTimeout,
+		ReadHeaderTimeout: readTimeout,
+		WriteTimeout:      2 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+	if h.opts.ReadHeaderTimeout > 0 {
+		// ReadTimeout covers the headers too, so it must not cut them off.
+		srv.ReadHeaderTimeout = h.opts.ReadHeaderTimeout
+		srv.ReadTimeout = max(readTimeout, h.opts.ReadHeaderTimeout)
+	}
+	if h.opts.HTTP2Cleartext {
+		var protocols http.Protocols
+		protocols.SetHTTP1(true)
+		protocols.SetUnencryptedHTTP2(true)
+		srv.Protocols = &protocols
+	}
+	return srv
+}
+
+// internalMux serves every probe endpoint for the internal listener, terse
+// unless opts.InternalVerbose is set. The status and metrics endpoints keep
+// their bodies either way.
+func internalMux(state StateReader, opts *HTTPOptions) *http.ServeMux {
+	mux := http.NewServeMux()
+	for pattern, h := range probeHandlers(state, opts) {
+		if !opts.InternalVerbose && pattern != opts.StatusPath && pattern != opts.MetricsPath {
+			h = terse(h)
+		}
+		mux.HandleFunc(pattern, h)
+	}
+	if opts.Pprof {
+		registerPprof(mux)
+	}
+	return mux
+}
+
+// terseWriter drops the body and detail headers of a probe response, so only
+// the status code reaches the client.
+type terseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (t *terseWriter) WriteHeader(code int) {
+	if t.wroteHeader {
+		return
+	}
+	t.wroteHeader = true
+	for _, k := range []string{"Content-Type", NotReadyReasonHeader, LoadHeader, StartupProgressHeader} {
+		t.Header().Del(k)
+	}
+	t.ResponseWriter.WriteHeader(code)
+}
+
+func (t *terseWriter) Write(p []byte) (int, error) {
+	t.WriteHeader(http.StatusOK)
+	return len(p), nil
+}
+
+// terse wraps next so that it answers with status codes only.
+func terse(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(&terseWriter{ResponseWriter: w}, r)
+	}
+}
+
+// ErrPatternConflict is returned when a probe path cannot be registered on
+// an existing mux because a pattern already there takes it or conflicts
+// with it.
+var ErrPatternConflict = errors.New("probe path conflicts with a pattern on the mux")
+
+// registerHandlers registers the probe handlers on mux. Every path is
+// checked against the patterns already on mux before anything is
+// registered, so a conflict leaves mux untouched.
+func registerHandlers(mux *http.ServeMux, state StateReader, opts *HTTPOptions) error {
+	handlers := make(map[string]http.HandlerFunc)
+	for pattern, h := range probeHandlers(state, opts) {
+		handlers[opts.PathPrefix+pattern] = h
+	}
+	if opts.Pprof {
+		for _, p := range pprofHandlers {
+			handlers[p.path] = p.handler
+		}
+	}
+	patterns := sortedNames(handlers)
+	if err := checkPatterns(mux, patterns); err != nil {
 		return err
 	}
-	onStarted()
-	go func() {
-		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
-			if h.errHandler != nil {
-				h.errHandler(err)
+	for _, pattern := range patterns {
+		if err := handleFunc(mux, pattern, handlers[pattern]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupMethods are the methods checkPatterns looks each probe path up
+// under, so method-specific patterns on the mux are found too.
+var lookupMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+}
+
+// checkPatterns reports whether registering patterns on mux would fail,
+// without modifying it. ServeMux cannot list its patterns, so the ones mux
+// routes the probe paths to are copied onto a scratch mux and the probe
+// patterns registered there first.
+func checkPatterns(mux *http.ServeMux, patterns []string) error {
+	scratch := http.NewServeMux()
+	copied := make(map[string]bool)
+	for _, pattern := range patterns {
+		for _, method := range lookupMethods {
+			r := &http.Request{Method: method, URL: &url.URL{Path: pattern}}
+			_, existing := mux.Handler(r)
+			if existing == pattern {
+				return fmt.Errorf("%w: %q is already registered", ErrPatternConflict, pattern)
 			}
+			if existing != "" && !copied[existing] {
+				copied[existing] = true
+				scratch.HandleFunc(existing, http.NotFound)
+			}
+		}
+	}
+	for _, pattern := range patterns {
+		if err := handleFunc(scratch, pattern, http.NotFound); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleFunc registers h on mux, turning the ServeMux panic on a conflicting
+// pattern into an ErrPatternConflict error.
+func handleFunc(mux *http.ServeMux, pattern string, h http.HandlerFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %q: %v", ErrPatternConflict, pattern, r)
 		}
 	}()
+	mux.HandleFunc(pattern, h)
 	return nil
 }
 
-func (h *httpProbe) readyHandler(state StateReader) http.HandlerFunc {
+// probeHandlers returns the /ready, /live, /startup, and optional ping,
+// status, and metrics handlers keyed by path.
+func probeHandlers(state StateReader, opts *HTTPOptions) map[string]http.HandlerFunc {
+	wrap := func(h http.HandlerFunc) http.HandlerFunc {
+		return probeMiddleware(onlyGET(h), opts)
+	}
+	handlers := map[string]http.HandlerFunc{
+		"/ready": wrap(readyHandler(state, opts)),
+		"/live": wrap(func(w http.ResponseWriter, _ *http.Request) {
+			if state.ShuttingDown() && !opts.LiveIgnoresShutdown {
+				opts.failures.record("/live", "shutting-down", nil)
+				writeFailure(w, "/live", opts)
+				return
+			}
+			if !isLive(state) {
+				opts.failures.record("/live", "not-live", nil)
+				writeFailure(w, "/live", opts)
+				return
+			}
+			writeStatus(w, http.StatusOK, opts)
+		}),
+		"/startup": wrap(func(w http.ResponseWriter, _ *http.Request) {
+			if pr, ok := state.(StartupProgressReader); ok {
+				if done, total := pr.StartupProgress(); total > 0 {
+					w.Header().Set(StartupProgressHeader, fmt.Sprintf("%d/%d", done, total))
+				}
+			}
+			if state.Started() {
+				writeStatus(w, http.StatusOK, opts)
+				return
+			}
+			opts.failures.record("/startup", "not-started", nil)
+			writeFailure(w, "/startup", opts)
+		}),
+	}
+	if opts.PingPath != "" {
+		handlers[opts.PingPath] = wrap(func(w http.ResponseWriter, _ *http.Request) {
+			if state.ShuttingDown() {
+				writeStatus(w, opts.unhealthyCode(), opts)
+				return
+			}
+			writeStatus(w, http.StatusOK, opts)
+		})
+	}
+	if opts.StatusPath != "" && opts.Status != nil {
+		handlers[opts.StatusPath] = wrap(func(w http.ResponseWriter, _ *http.Request) {
+			b, err := json.Marshal(opts.Status())
+			if err != nil {
+				reportError(opts, fmt.Errorf("encode status: %w", err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(append(b, '\n'))
+		})
+	}
+	if opts.MetricsPath != "" && opts.Metrics != nil {
+		handlers[opts.MetricsPath] = wrap(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(opts.Metrics())
+		})
+	}
+	return handlers
+}
+
+// registerRoot answers browser visits to the probe port: / describes the
+// probe endpoints and /favicon.ico returns 204.
+func registerRoot(mux *http.ServeMux, opts *HTTPOptions) {
+	var b strings.Builder
+	b.WriteString("pod-lifecycle probe server\n\n")
+	for _, ep := range []struct{ path, desc string }{
+		{"/ready", "readiness (runs checkers)"},
+		{"/live", "liveness"},
+		{"/startup", "startup"},
+		{opts.PingPath, "ping (no checkers)"},
+		{opts.StatusPath, "status (JSON)"},
+		{opts.MetricsPath, "metrics (Prometheus text)"},
+	} {
+		if opts.InternalPort != 0 && ep.path != "" && (ep.path == opts.StatusPath || ep.path == opts.MetricsPath) {
+			continue // served on the internal listener only
+		}
+		if ep.path != "" {
+			fmt.Fprintf(&b, "%s\t%s\n", ep.path, ep.desc)
+		}
+	}
+	body := b.String()
+	mux.HandleFunc("/{$}", onlyGET(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(body))
+	}))
+	mux.HandleFunc("/favicon.ico", onlyGET(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+var pprofHandlers = []struct {
+	path    string
+	handler http.HandlerFunc
+}{
+	{"/debug/pprof/", pprof.Index},
+	{"/debug/pprof/cmdline", pprof.Cmdline},
+	{"/debug/pprof/profile", pprof.Profile},
+	{"/debug/pprof/symbol", pprof.Symbol},
+	{"/debug/pprof/trace", pprof.Trace},
+}
+
+// PprofPaths returns the patterns registered when pprof is enabled.
+func PprofPaths() []string {
+	paths := make([]string, len(pprofHandlers))
+	for i, p := range pprofHandlers {
+		paths[i] = p.path
+	}
+	return paths
+}
+
+func registerPprof(mux *http.ServeMux) {
+	for _, p := range pprofHandlers {
+		mux.HandleFunc(p.path, p.handler)
+	}
+}
+
+// probeMiddleware applies cross-cutting behavior shared by all probe endpoints.
+func probeMiddleware(next http.HandlerFunc, opts *HTTPOptions) http.HandlerFunc {
+	if opts.VersionHeaderName != "" {
+		inner := next
+		next = func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(opts.VersionHeaderName, opts.VersionHeaderValue)
+			inner(w, r)
+		}
+	}
+	if opts.Compress {
+		next = gzipResponses(next)
+	}
+	if opts.AccessLog != nil {
+		next = accessLog(next, opts.AccessLog)
+	}
+	return next
+}
+
+// statusWriter records the status code written through it.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusWriter) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// accessLog logs each request to next at debug level.
+func accessLog(next http.HandlerFunc, log *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !state.Ready() || state.ShuttingDown() {
-			w.WriteHeader(http.StatusServiceUnavailable)
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next(sw, r)
+		log.Debug("probe request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", sw.status,
+			"duration", time.Since(start),
+			"remote", r.RemoteAddr,
+		)
+	}
+}
+
+// writeFailure writes a failing /live or /startup response, with the
+// opts.FailureBody body when set.
+func writeFailure(w http.ResponseWriter, endpoint string, opts *HTTPOptions) {
+	if opts.FailureBody == nil {
+		writeStatus(w, opts.unhealthyCode(), opts)
+		return
+	}
+	body := opts.FailureBody(endpoint)
+	if json.Valid([]byte(body)) {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.WriteHeader(opts.unhealthyCode())
+	_, _ = w.Write([]byte(body))
+}
+
+// writeStatus writes a status-only response, with a small JSON body when
+// opts.UniformJSONBodies is set.
+func writeStatus(w http.ResponseWriter, code int, opts *HTTPOptions) {
+	if !opts.UniformJSONBodies {
+		w.WriteHeader(code)
+		return
+	}
+	status := "ok"
+	if code != http.StatusOK {
+		status = "unavailable"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": status})
+}
+
+// ReservedResultKey reports whether name is a key that probe bodies use
+// next to or instead of checker names: "status" in uniform JSON bodies and
+// the health+json top level, and "checks" in health+json. A checker with
+// such a name makes /ready bodies ambiguous to clients that parse them
+// loosely.
+func ReservedResultKey(name string) bool {
+	return name == "status" || name == "checks"
+}
+
+// NotReadyReasonHeader carries a stable token on failing /ready responses:
+// "shutting-down", "checker-failed", "overloaded", or "not-ready".
+const NotReadyReasonHeader = "X-Not-Ready-Reason"
+
+// LoadHeader carries "load/limit" on /ready responses refused by the load
+// gate.
+const LoadHeader = "X-Load"
+
+// StartupProgressHeader carries "done/total" warmup progress on /startup
+// responses when the state reports it.
+const StartupProgressHeader = "X-Startup-Progress"
+
+func readyHandler(state StateReader, opts *HTTPOptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if load, limit, over := overloaded(state, opts); over {
+			w.Header().Set(NotReadyReasonHeader, "overloaded")
+			w.Header().Set(LoadHeader, fmt.Sprintf("%d/%d", load, limit))
+			opts.failures.record("/ready", "overloaded", nil)
+			writeReady(w, r, false, nil, opts)
+			if opts.OnReadyServed != nil {
+				opts.OnReadyServed(false)
+			}
 			return
 		}
-		if len(h.checkers) == 0 {
-			w.WriteHeader(http.StatusOK)
-			return
+		ctx := r.Context()
+		if opts.DetachedCheckerContext {
+			ctx = context.WithoutCancel(ctx)
 		}
-		results := h.runCheckers(r.Context())
-		allOK := true
-		for _, v := range results {
-			if v != "ok" {
-				allOK = false
-				break
-			}
+		ok, results := evaluateReady(ctx, state, opts)
+		if !ok {
+			reason := notReadyReason(state, results)
+			w.Header().Set(NotReadyReasonHeader, reason)
+			opts.failures.record("/ready", reason, results)
 		}
-		w.Header().Set("Content-Type", "application/json")
+		writeReady(w, r, ok, results, opts)
+		if opts.OnReadyServed != nil {
+			opts.OnReadyServed(ok)
+		}
+	}
+}
+
+// overloaded consults opts.LoadGate for a pod that is otherwise ready. It is
+// checked before the checkers, so an overloaded pod sheds the checker load
+// too.
+func overloaded(state StateReader, opts *HTTPOptions) (load, limit int, over bool) {
+	if opts.LoadGate == nil || !state.Ready() || state.ShuttingDown() {
+		return 0, 0, false
+	}
+	load, limit = opts.LoadGate()
+	return load, limit, load >= limit
+}
+
+// evaluateReady returns the /ready verdict and, when checkers ran, their
+// results. opts.ReadinessDecider decides from the results when set;
+// otherwise checkers fail the verdict only when the fraction failing exceeds
+// opts.FailureTolerance. The fraction is of the checkers that are not
+// disabled, so muting one does not loosen the tolerance. While shutting down
+// the verdict always fails, and checkers only run with
+// opts.RunCheckersDuringShutdown.
+func evaluateReady(ctx context.Context, state StateReader, opts *HTTPOptions) (bool, map[string]string) {
+	if state.ShuttingDown() && opts.RunCheckersDuringShutdown {
+		if checkers := opts.Checkers.Snapshot(); len(checkers) > 0 {
+			return false, readyResults(ctx, checkers, opts)
+		}
+		return false, nil
+	}
+	if !state.Ready() || state.ShuttingDown() {
+		return false, nil
+	}
+	checkers := opts.Checkers.Snapshot()
+	if len(checkers) == 0 {
+		return true, nil
+	}
+	results := readyResults(ctx, checkers, opts)
+	if opts.ReadinessDecider != nil {
+		return opts.ReadinessDecider(maps.Clone(results)), results
+	}
+	failed, counted := 0, 0
+	for _, v := range results {
+		if v == resultDisabled {
+			continue
+		}
+		counted++
+		if resultFailed(v) || (opts.FailOnWarn && resultWarned(v)) {
+			failed++
+		}
+	}
+	return failed == 0 || float64(failed)/float64(counted) <= opts.FailureTolerance, results
+}
+
+// notReadyReason classifies a failing /ready verdict. Checker results are
+// only present when the checkers ran, so they imply a checker failed.
+func notReadyReason(state StateReader, results map[string]string) string {
+	switch {
+	case state.ShuttingDown():
+		return "shutting-down"
+	case results != nil:
+		return "checker-failed"
+	default:
+		return "not-ready"
+	}
+}
+
+// writeReady writes a /ready response, delegating to opts.ReadyResponseWriter
+// when one is configured. Checker results are JSON unless the client prefers
+// text/plain, which gets sorted name=value lines.
+func writeReady(w http.ResponseWriter, r *http.Request, allOK bool, results map[string]string, opts *HTTPOptions) {
+	if opts.ReadyResponseWriter != nil {
+		opts.ReadyResponseWriter(w, allOK, results)
+		return
+	}
+	if opts.HealthJSON {
+		writeHealthJSON(w, allOK, results, opts)
+		return
+	}
+	if results == nil {
 		if allOK {
-			w.WriteHeader(http.StatusOK)
+			writeStatus(w, http.StatusOK, opts)
 		} else {
-			w.WriteHeader(http.StatusServiceUnavailable)
+			writeStatus(w, opts.unhealthyCode(), opts)
+		}
+		return
+	}
+	if allOK && opts.ReadyBodyOnFailureOnly {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	code := http.StatusOK
+	if !allOK {
+		code = opts.unhealthyCode()
+	}
+	var body []byte
+	if prefersPlainText(r.Header.Get("Accept")) {
+		var buf bytes.Buffer
+		for _, name := range sortedNames(results) {
+			fmt.Fprintf(&buf, "%s=%s\n", name, results[name])
+		}
+		body = buf.Bytes()
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else if opts.ResultMarshaler != nil {
+		b, contentType, err := opts.ResultMarshaler(results)
+		if err != nil {
+			reportError(opts, fmt.Errorf("marshal /ready body: %w", err))
+			writeStatus(w, opts.unhealthyCode(), opts)
+			return
+		}
+		body = b
+		w.Header().Set("Content-Type", contentType)
+	} else {
+		// Encode before writing the status so a failure cannot leave a
+		// truncated body behind a committed status code.
+		b, err := json.Marshal(results)
+		if err != nil {
+			reportError(opts, fmt.Errorf("encode /ready body: %w", err))
+			w.WriteHeader(code)
+			return
 		}
-		_ = json.NewEncoder(w).Encode(results)
+		body = append(b, '\n')
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(code)
+	if _, err := w.Write(body); err != nil {
+		reportError(opts, fmt.Errorf("write /ready body: %w", err))
 	}
 }
 
-func (h *httpProbe) runCheckers(reqCtx context.Context) map[string]string {
-	type result struct {
-		name string
-		val  string
+// reportError passes err to opts.ErrorHandler, if set.
+func reportError(opts *HTTPOptions, err error) {
+	if opts.ErrorHandler != nil {
+		opts.ErrorHandler(err)
 	}
-	ch := make(chan result, len(h.checkers))
-	for name, c := range h.checkers {
-		name, c := name, c
+}
+
+// prefersPlainText reports whether an Accept header ranks text/plain above
+// application/json. Wildcards and missing headers favor JSON.
+func prefersPlainText(accept string) bool {
+	var plainQ, jsonQ float64
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(k, "q") {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		switch mediaType {
+		case "text/plain":
+			plainQ = max(plainQ, q)
+		case "application/json", "application/*", "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return plainQ > jsonQ
+}
+
+// notifyTransition calls the checker failure and recovery hooks on edges
+// only: ok→fail (or a first result that fails) and fail→ok. Consecutive
+// results with the same outcome are not reported.
+func notifyTransition(name string, err error, prev Result, hadPrev bool, opts *HTTPOptions) {
+	wasOK := !hadPrev || prev.Err == nil
+	switch {
+	case err != nil && wasOK:
+		if opts.OnCheckerFailure != nil {
+			opts.OnCheckerFailure(name, err)
+		}
+	case err == nil && !wasOK:
+		if opts.OnCheckerRecovery != nil {
+			opts.OnCheckerRecovery(name)
+		}
+	}
+}
+
+// runCheckers runs checkers concurrently. Each result is stored at the
+// checker's position in name order, so the output is built deterministically
+// regardless of completion order.
+func runCheckers(reqCtx context.Context, checkers map[string]Checker, opts *HTTPOptions) map[string]string {
+	reqCtx = ContextWithClock(reqCtx, clockOr(opts.Clock))
+	names := sortedNames(checkers)
+	vals := make([]string, len(names))
+	errs := make([]error, len(names))
+	// finished[i] is closed once vals[i] is set, for dependents to wait on.
+	finished := make([]chan struct{}, len(names))
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		finished[i] = make(chan struct{})
+		index[name] = i
+	}
+	var wg sync.WaitGroup
+	for i, name := range names {
+		i, name, c := i, name, checkers[name]
+		if opts.Checkers.Disabled(name) {
+			vals[i] = resultDisabled
+			close(finished[i])
+			continue
+		}
+		wg.Add(1)
 		go func() {
-			ctx, cancel := context.WithTimeout(reqCtx, h.checkerTimeout)
+			defer wg.Done()
+			defer close(finished[i])
+			if failed := failedPrerequisites(name, opts.CheckerDependencies, index, finished, vals); len(failed) > 0 {
+				vals[i] = resultSkipped(failed)
+				// A skipped checker counts as failing: record it so status
+				// reads and the failure hooks do not keep its last result.
+				err := errors.New(vals[i])
+				prev, hadPrev, ok := opts.Checkers.Record(name, err, clockOr(opts.Clock).Now())
+				if ok {
+					notifyTransition(name, err, prev, hadPrev, opts)
+				}
+				errs[i] = fmt.Errorf("%s: %w", name, err)
+				return
+			}
+			ctx, cancel := context.WithTimeout(reqCtx, opts.CheckerTimeout)
 			defer cancel()
-			if err := c.Check(ctx); err != nil {
-				ch <- result{name, "error: " + err.Error()}
+			warning, warned, err := runChecker(ctx, c)
+			if warned && opts.FailOnWarn {
+				err = errors.New(warning)
+			}
+			var prev Result
+			var hadPrev, ok bool
+			if warned && err == nil {
+				prev, hadPrev, ok = opts.Checkers.RecordWarning(name, warning, clockOr(opts.Clock).Now())
 			} else {
-				ch <- result{name, "ok"}
+				prev, hadPrev, ok = opts.Checkers.Record(name, err, clockOr(opts.Clock).Now())
+			}
+			if ok {
+				notifyTransition(name, err, prev, hadPrev, opts)
+			}
+			switch {
+			case warned:
+				vals[i] = resultWarn(truncate(warning, opts.MaxCheckerErrorLen))
+				if err != nil {
+					errs[i] = fmt.Errorf("%s: %w", name, err)
+				}
+			case err != nil:
+				vals[i] = "error: " + truncate(err.Error(), opts.MaxCheckerErrorLen)
+				errs[i] = fmt.Errorf("%s: %w", name, err)
+			default:
+				vals[i] = "ok"
 			}
 		}()
 	}
-	out := make(map[string]string, len(h.checkers))
-	for range h.checkers {
-		r := <-ch
-		out[r.name] = r.val
+	wg.Wait()
+	if opts.OnReadinessFailure != nil {
+		if err := errors.Join(errs...); err != nil {
+			opts.OnReadinessFailure(fmt.Errorf("ready checks failed: %w", err))
+		}
+	}
+	out := make(map[string]string, len(names))
+	for i, name := range names {
+		out[name] = vals[i]
 	}
 	return out
 }
 
+// failedPrerequisites waits for the prerequisites of name that are being
+// run and returns those that failed, in order. Prerequisites that are not
+// registered do not block.
+func failedPrerequisites(name string, deps map[string][]string, index map[string]int, finished []chan struct{}, vals []string) []string {
+	var failed []string
+	for _, pre := range deps[name] {
+		j, ok := index[pre]
+		if !ok {
+			continue
+		}
+		<-finished[j]
+		if resultFailed(vals[j]) {
+			failed = append(failed, pre)
+		}
+	}
+	return failed
+}
+
+// resultSkipped is the /ready result of a checker skipped because its
+// prerequisites failed. It counts as a failure.
+func resultSkipped(failed []string) string {
+	return "skipped: " + strings.Join(failed, ", ") + " failed"
+}
+
+// resultDisabled is the /ready result of a checker muted with
+// Registry.SetDisabled. It does not count as a failure.
+const resultDisabled = "disabled"
+
+// runChecker runs c, through CheckDetailed when c implements
+// DetailedChecker. warned reports a StatusWarn result with its message.
+func runChecker(ctx context.Context, c Checker) (warning string, warned bool, err error) {
+	dc, ok := c.(DetailedChecker)
+	if !ok {
+		return "", false, c.Check(ctx)
+	}
+	res := dc.CheckDetailed(ctx)
+	switch res.Status {
+	case StatusOK:
+		return "", false, nil
+	case StatusWarn:
+		if res.Message == "" {
+			res.Message = "warning"
+		}
+		return res.Message, true, nil
+	default:
+		if res.Message == "" {
+			res.Message = "check failed"
+		}
+		return "", false, errors.New(res.Message)
+	}
+}
+
+// resultWarnPrefix marks the /ready result of a DetailedChecker warning.
+const resultWarnPrefix = "warn: "
+
+func resultWarn(msg string) string { return resultWarnPrefix + msg }
+
+// resultWarned reports whether a /ready checker result is a warning.
+func resultWarned(v string) bool { return strings.HasPrefix(v, resultWarnPrefix) }
+
+// resultFailed reports whether a /ready checker result is a failure.
+// Warnings are not failures.
+func resultFailed(v string) bool {
+	return v != "ok" && v != resultDisabled && !resultWarned(v)
+}
+
+// truncate shortens s to max runes followed by an ellipsis. max <= 0 disables
+// truncation.
+func truncate(s string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max]) + "…"
+}
+
+// sortedNames returns the keys of m in ascending order.
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // onlyGET wraps a handler to return 405 for non-GET methods.
 func onlyGET(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -140,12 +1041,32 @@ func onlyGET(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// Shutdown drains every listener in parallel under ctx, so one busy server
+// cannot use up the deadline before the others start draining.
 func (h *httpProbe) Shutdown(ctx context.Context) {
 	h.mu.Lock()
-	srv := h.server
+	servers := h.servers
 	h.mu.Unlock()
-	if srv != nil {
-		_ = srv.Shutdown(ctx)
+	var (
+		wg     sync.WaitGroup
+		forced atomic.Bool
+	)
+	for _, srv := range servers {
+		wg.Add(1)
+		go func(srv *http.Server) {
+			defer wg.Done()
+			if err := srv.Shutdown(ctx); err != nil {
+				// The deadline passed with requests still in flight.
+				if h.opts.ForceCloseOnTimeout {
+					_ = srv.Close()
+				}
+				forced.Store(true)
+			}
+		}(srv)
+	}
+	wg.Wait()
+	if forced.Load() && h.opts.OnForcedStop != nil {
+		h.opts.OnForcedStop()
 	}
 }
 
@@ -158,47 +1079,30 @@ func (h *httpProbe) SetState(_, _ bool) {
 // ---------------------------------------------------------------------------
 
 type existingHTTPProbe struct {
-	mux            *http.ServeMux
-	checkerTimeout time.Duration
-	checkers       map[string]Checker
+	mux  *http.ServeMux
+	opts HTTPOptions
 }
 
 // NewExistingHTTPProbe returns a Server that registers /ready, /live, /startup
-// on an existing ServeMux without starting a new HTTP server.
-func NewExistingHTTPProbe(mux *http.ServeMux, checkerTimeout time.Duration, checkers map[string]Checker) Server {
-	return &existingHTTPProbe{
-		mux:            mux,
-		checkerTimeout: checkerTimeout,
-		checkers:       checkers,
+// on an existing ServeMux without starting a new HTTP server. The port and
+// ShutdownTimeout fields of opts are unused.
+func NewExistingHTTPProbe(mux *http.ServeMux, opts HTTPOptions) Server {
+	if opts.Checkers == nil {
+		opts.Checkers = NewRegistry(nil)
 	}
+	opts.failures = newFailureSampler(opts.FailureLog, opts.FailureLogEvery)
+	opts.readyLimiter = newReadyLimiter(opts.ReadyRateLimit, opts.Clock)
+	return &existingHTTPProbe{mux: mux, opts: opts}
 }
 
 func (e *existingHTTPProbe) Start(state StateReader, onStarted func()) error {
-	e.mux.HandleFunc("/ready", onlyGET(e.readyHandler(state)))
-	e.mux.HandleFunc("/live", onlyGET(func(w http.ResponseWriter, _ *http.Request) {
-		if state.ShuttingDown() {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-	}))
-	e.mux.HandleFunc("/startup", onlyGET(func(w http.ResponseWriter, _ *http.Request) {
-		if state.Started() {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-		w.WriteHeader(http.StatusServiceUnavailable)
-	}))
+	if err := registerHandlers(e.mux, state, &e.opts); err != nil {
+		return err
+	}
 	onStarted()
 	return nil
 }
 
-func (e *existingHTTPProbe) readyHandler(state StateReader) http.HandlerFunc {
-	// Reuse the logic from httpProbe since it doesn't access unexported fields of h.
-	h := &httpProbe{checkers: e.checkers, checkerTimeout: e.checkerTimeout}
-	return h.readyHandler(state)
-}
-
 func (e *existingHTTPProbe) Shutdown(_ context.Context) {}
 
 func (e *existingHTTPProbe) SetState(_, _ bool) {}
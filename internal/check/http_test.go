@@ -1,14 +1,20 @@
 package check_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -64,13 +70,34 @@ func (c *ctxCapture) Check(ctx context.Context) error {
 // ---- probe builder helper ----
 
 func newProbe(port int, checkers map[string]check.Checker) check.Server {
-	return check.NewHTTPProbe(port, 5*time.Second, 2*time.Second, checkers, nil)
+	return check.NewHTTPProbe(check.HTTPOptions{
+		Port:            port,
+		ShutdownTimeout: 5 * time.Second,
+		CheckerTimeout:  2 * time.Second,
+		Checkers:        check.NewRegistry(checkers),
+	})
 }
 
 // startProbeOnPort starts the probe on a specific port and waits for it.
 func startProbeOnPort(t *testing.T, port int, state check.StateReader, checkers map[string]check.Checker) (baseURL string, cleanup func()) {
 	t.Helper()
-	probe := newProbe(port, checkers)
+	return startProbe(t, newProbe(port, checkers), port, state)
+}
+
+// startHTTPProbe builds a probe from opts, starts it on opts.Port, and waits for it.
+func startHTTPProbe(t *testing.T, opts check.HTTPOptions, state check.StateReader) (baseURL string, cleanup func()) {
+	t.Helper()
+	if opts.ShutdownTimeout == 0 {
+		opts.ShutdownTimeout = 5 * time.Second
+	}
+	if opts.CheckerTimeout == 0 {
+		opts.CheckerTimeout = 2 * time.Second
+	}
+	return startProbe(t, check.NewHTTPProbe(opts), opts.Port, state)
+}
+
+func startProbe(t *testing.T, probe check.Server, port int, state check.StateReader) (baseURL string, cleanup func()) {
+	t.Helper()
 	started := make(chan struct{})
 	errCh := make(chan error, 1)
 	go func() {
@@ -166,6 +193,19 @@ func TestLiveEndpointShuttingDown(t *testing.T) {
 	}
 }
 
+func TestLiveEndpointIgnoresShutdown(t *testing.T) {
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{LiveIgnoresShutdown: true}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{shuttingDown: true}, func() {}) //nolint:errcheck
+	for path, want := range map[string]int{"/live": http.StatusOK, "/ready": http.StatusServiceUnavailable} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s (shuttingDown, LiveIgnoresShutdown): got %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
 func TestStartupEndpointStarted(t *testing.T) {
 	port := freePort(t)
 	url, cleanup := startProbeOnPort(t, port, fakeState{started: true}, nil)
@@ -286,10 +326,73 @@ func TestMixedCheckers503(t *testing.T) {
 	}
 }
 
+// manyCheckers returns n checkers named c0000…, failing every third one.
+func manyCheckers(n int) map[string]check.Checker {
+	checkers := make(map[string]check.Checker, n)
+	for i := 0; i < n; i++ {
+		var c check.Checker = okChecker{}
+		if i%3 == 0 {
+			c = errChecker{"down"}
+		}
+		checkers[fmt.Sprintf("c%04d", i)] = c
+	}
+	return checkers
+}
+
+func TestManyCheckersAggregate(t *testing.T) {
+	const n = 2000
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{
+		Checkers:       check.NewRegistry(manyCheckers(n)),
+		CheckerTimeout: time.Second,
+	}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("want 503, got %d", rec.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if len(body) != n {
+		t.Fatalf("results: got %d, want %d", len(body), n)
+	}
+	for i := 0; i < n; i++ {
+		name, want := fmt.Sprintf("c%04d", i), "ok"
+		if i%3 == 0 {
+			want = "error: down"
+		}
+		if body[name] != want {
+			t.Errorf("%s: got %q, want %q", name, body[name], want)
+		}
+	}
+}
+
+func BenchmarkReadyManyCheckers(b *testing.B) {
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{
+		Checkers:       check.NewRegistry(manyCheckers(1000)),
+		CheckerTimeout: time.Second,
+	}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
 func TestSlowCheckerTimeout(t *testing.T) {
 	port := freePort(t)
 	checkers := map[string]check.Checker{"slow": slowChecker{10 * time.Second}}
-	probe := check.NewHTTPProbe(port, 5*time.Second, 10*time.Millisecond, checkers, nil)
+	probe := check.NewHTTPProbe(check.HTTPOptions{
+		Port:            port,
+		ShutdownTimeout: 5 * time.Second,
+		CheckerTimeout:  10 * time.Millisecond,
+		Checkers:        check.NewRegistry(checkers),
+	})
 
 	started := make(chan struct{})
 	go func() { probe.Start(fakeState{ready: true}, func() { close(started) }) }() //nolint:errcheck
@@ -327,6 +430,518 @@ func TestCheckerReceivesContextCancellation(t *testing.T) {
 	}
 }
 
+// ---- uniform JSON bodies ----
+
+func TestUniformJSONBodies(t *testing.T) {
+	tests := []struct {
+		path    string
+		state   fakeState
+		wantSts int
+		want    string
+	}{
+		{"/live", fakeState{}, http.StatusOK, "ok"},
+		{"/live", fakeState{shuttingDown: true}, http.StatusServiceUnavailable, "unavailable"},
+		{"/startup", fakeState{started: true}, http.StatusOK, "ok"},
+		{"/startup", fakeState{}, http.StatusServiceUnavailable, "unavailable"},
+		{"/ready", fakeState{ready: true}, http.StatusOK, "ok"},
+	}
+	for _, tc := range tests {
+		port := freePort(t)
+		url, cleanup := startHTTPProbe(t, check.HTTPOptions{Port: port, UniformJSONBodies: true}, tc.state)
+		resp, err := http.Get(url + tc.path) //nolint:noctx
+		if err != nil {
+			cleanup()
+			t.Fatal(err)
+		}
+		var body map[string]string
+		decErr := json.NewDecoder(resp.Body).Decode(&body)
+		_ = resp.Body.Close()
+		cleanup()
+		if decErr != nil {
+			t.Fatalf("%s %+v: decode body: %v", tc.path, tc.state, decErr)
+		}
+		if resp.StatusCode != tc.wantSts {
+			t.Errorf("%s %+v: want %d, got %d", tc.path, tc.state, tc.wantSts, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s: Content-Type %q, want application/json", tc.path, ct)
+		}
+		if body["status"] != tc.want {
+			t.Errorf("%s %+v: status %q, want %q", tc.path, tc.state, body["status"], tc.want)
+		}
+	}
+}
+
+func TestDefaultBodiesEmpty(t *testing.T) {
+	port := freePort(t)
+	url, cleanup := startProbeOnPort(t, port, fakeState{}, nil)
+	defer cleanup()
+	resp, err := http.Get(url + "/live") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.ContentLength != 0 {
+		t.Errorf("/live: want empty body by default, got length %d", resp.ContentLength)
+	}
+}
+
+// ---- server hardening ----
+
+func TestReadHeaderTimeoutCutsOffSlowClient(t *testing.T) {
+	port := freePort(t)
+	_, cleanup := startHTTPProbe(t, check.HTTPOptions{Port: port, ReadHeaderTimeout: 100 * time.Millisecond}, fakeState{})
+	defer cleanup()
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	// Dribble a partial request line and never finish the headers.
+	if _, err := conn.Write([]byte("GET /live HTTP/1.1\r\nHost: x\r\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	start := time.Now()
+	buf := make([]byte, 512)
+	for {
+		if _, err = conn.Read(buf); err != nil {
+			break
+		}
+	}
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		t.Fatal("server did not close the slow connection")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("connection closed after %v, want ~100ms", elapsed)
+	}
+}
+
+func TestReadHeaderTimeoutAboveReadTimeout(t *testing.T) {
+	srv := check.NewProbeServer(check.HTTPOptions{ReadHeaderTimeout: 5 * time.Second}, 8080)
+	if srv.ReadHeaderTimeout != 5*time.Second || srv.ReadTimeout < srv.ReadHeaderTimeout {
+		t.Errorf("got ReadHeaderTimeout %v, ReadTimeout %v; want 5s and a ReadTimeout at least as long",
+			srv.ReadHeaderTimeout, srv.ReadTimeout)
+	}
+	srv = check.NewProbeServer(check.HTTPOptions{ReadHeaderTimeout: 100 * time.Millisecond}, 8080)
+	if srv.ReadTimeout != 2*time.Second {
+		t.Errorf("short header timeout: got ReadTimeout %v, want the 2s default", srv.ReadTimeout)
+	}
+}
+
+// ---- custom ready response ----
+
+func TestReadyResponseWriterOverridesBody(t *testing.T) {
+	port := freePort(t)
+	writer := func(w http.ResponseWriter, ok bool, results map[string]string) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Header().Set("X-Health", "custom")
+		if ok {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = fmt.Fprintf(w, "<health ok=%q db=%q/>", fmt.Sprint(ok), results["db"])
+	}
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:                port,
+		Checkers:            check.NewRegistry(map[string]check.Checker{"db": errChecker{"down"}}),
+		ReadyResponseWriter: writer,
+	}, fakeState{ready: true})
+	defer cleanup()
+
+	resp, err := http.Get(url + "/ready") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("want 503, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("X-Health"); got != "custom" {
+		t.Errorf("X-Health: got %q, want custom", got)
+	}
+	if want := `<health ok="false" db="error: down"/>`; string(body) != want {
+		t.Errorf("body: got %q, want %q", body, want)
+	}
+}
+
+// ---- version header ----
+
+func TestVersionHeaderOnAllResponses(t *testing.T) {
+	port := freePort(t)
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:               port,
+		VersionHeaderName:  "X-App-Version",
+		VersionHeaderValue: "1.2.3",
+	}, fakeState{ready: false, started: true})
+	defer cleanup()
+
+	for _, tc := range []struct {
+		path    string
+		wantSts int
+	}{
+		{"/ready", http.StatusServiceUnavailable},
+		{"/live", http.StatusOK},
+		{"/startup", http.StatusOK},
+	} {
+		resp, err := http.Get(url + tc.path) //nolint:noctx
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != tc.wantSts {
+			t.Errorf("%s: want %d, got %d", tc.path, tc.wantSts, resp.StatusCode)
+		}
+		if got := resp.Header.Get("X-App-Version"); got != "1.2.3" {
+			t.Errorf("%s: X-App-Version %q, want 1.2.3", tc.path, got)
+		}
+	}
+}
+
+// ---- failure body ----
+
+func TestFailureBodyOnLiveAndStartup(t *testing.T) {
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{
+		FailureBody: func(endpoint string) string {
+			if endpoint == "/live" {
+				return `{"status":"down","endpoint":"live"}`
+			}
+			return "still starting"
+		},
+	}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{shuttingDown: true}, func() {}) //nolint:errcheck
+
+	for _, tc := range []struct{ path, body, contentType string }{
+		{"/live", `{"status":"down","endpoint":"live"}`, "application/json"},
+		{"/startup", "still starting", "text/plain; charset=utf-8"},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: want 503, got %d", tc.path, rec.Code)
+		}
+		if got := rec.Body.String(); got != tc.body {
+			t.Errorf("%s body: got %q, want %q", tc.path, got, tc.body)
+		}
+		if got := rec.Header().Get("Content-Type"); got != tc.contentType {
+			t.Errorf("%s Content-Type: got %q, want %q", tc.path, got, tc.contentType)
+		}
+	}
+}
+
+func TestFailureBodyNotUsedOnSuccess(t *testing.T) {
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{FailureBody: func(string) string { return "down" }}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{started: true}, func() {}) //nolint:errcheck
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/live", nil))
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("/live: got %d %q, want 200 with empty body", rec.Code, rec.Body.String())
+	}
+}
+
+// ---- access log ----
+
+func TestAccessLogRecordsReady(t *testing.T) {
+	var buf bytes.Buffer
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{
+		AccessLog: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{}, func() {}) //nolint:errcheck
+	req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	req.RemoteAddr = "10.0.0.7:4242"
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+	for _, want := range []string{"level=DEBUG", `msg="probe request"`, "method=GET", "path=/ready", "status=503", "remote=10.0.0.7:4242", "duration="} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log line %q does not contain %q", line, want)
+		}
+	}
+	if n := strings.Count(line, "\n"); n != 1 {
+		t.Errorf("log lines: got %d, want 1", n)
+	}
+}
+
+// ---- h2c ----
+
+func TestHTTP2Cleartext(t *testing.T) {
+	var h2c http.Protocols
+	h2c.SetUnencryptedHTTP2(true)
+	h2cClient := &http.Client{Transport: &http.Transport{Protocols: &h2c}}
+
+	port := freePort(t)
+	baseURL, cleanup := startHTTPProbe(t, check.HTTPOptions{Port: port, HTTP2Cleartext: true}, fakeState{started: true})
+	defer cleanup()
+
+	resp, err := h2cClient.Get(baseURL + "/live")
+	if err != nil {
+		t.Fatalf("h2c request: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.ProtoMajor != 2 {
+		t.Errorf("h2c /live: got %d over %s, want 200 over HTTP/2", resp.StatusCode, resp.Proto)
+	}
+	if code := doGET(t, baseURL+"/live"); code != http.StatusOK {
+		t.Errorf("HTTP/1.1 /live: got %d, want 200", code)
+	}
+
+	plainPort := freePort(t)
+	plainURL, plainCleanup := startHTTPProbe(t, check.HTTPOptions{Port: plainPort}, fakeState{started: true})
+	defer plainCleanup()
+	if resp, err := h2cClient.Get(plainURL + "/live"); err == nil {
+		_ = resp.Body.Close()
+		t.Error("h2c request without HTTP2Cleartext: want an error")
+	}
+}
+
+// ---- compression ----
+
+func TestCompressGzipsLargeBodies(t *testing.T) {
+	checkers := make(map[string]check.Checker)
+	for i := range 40 {
+		checkers[fmt.Sprintf("dependency-%02d", i)] = okChecker{}
+	}
+	port := freePort(t)
+	baseURL, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:     port,
+		Checkers: check.NewRegistry(checkers),
+		Compress: true,
+	}, fakeState{ready: true, started: true})
+	defer cleanup()
+
+	get := func(path, acceptEncoding string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, baseURL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acceptEncoding != "" {
+			// Setting the header turns off the transport's transparent
+			// decompression, so the test sees the encoded body.
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	resp := get("/ready", "gzip, deflate")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("/ready: got %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding: got %q, want gzip", got)
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results map[string]string
+	if err := json.NewDecoder(zr).Decode(&results); err != nil {
+		t.Fatalf("decode gzipped body: %v", err)
+	}
+	if len(results) != 40 || results["dependency-07"] != "ok" {
+		t.Errorf("decoded results: got %d entries, dependency-07=%q", len(results), results["dependency-07"])
+	}
+
+	if got := get("/ready", "").Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("without Accept-Encoding: Content-Encoding %q, want none", got)
+	}
+	if got := get("/ready", "gzip;q=0").Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("gzip;q=0: Content-Encoding %q, want none", got)
+	}
+	if got := get("/live", "gzip").Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("tiny /live body: Content-Encoding %q, want none", got)
+	}
+}
+
+func TestProbeFailureLoggingSamples(t *testing.T) {
+	var buf bytes.Buffer
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{
+		Checkers:        check.NewRegistry(map[string]check.Checker{"db": okChecker{}, "cache": errChecker{"down"}}),
+		CheckerTimeout:  time.Second,
+		FailureLog:      slog.New(slog.NewTextHandler(&buf, nil)),
+		FailureLogEvery: 10,
+	}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	for range 30 {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ready", nil))
+	}
+
+	out := buf.String()
+	if n := strings.Count(out, "\n"); n != 3 {
+		t.Errorf("log lines: got %d, want 3 for 30 failures sampled 1 in 10:\n%s", n, out)
+	}
+	for _, want := range []string{"level=WARN", `msg="probe failed"`, "endpoint=/ready", "reason=checker-failed", "failing_checkers=[cache]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q does not contain %q", out, want)
+		}
+	}
+}
+
+func TestProbeFailureLogOrderStable(t *testing.T) {
+	var buf bytes.Buffer
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{
+		Checkers: check.NewRegistry(map[string]check.Checker{
+			"zeta":  errChecker{"z down"},
+			"alpha": slowChecker{sleep: time.Second}, // fails last, at the timeout
+			"mid":   okChecker{},
+			"beta":  errChecker{"b down"},
+		}),
+		CheckerTimeout: 20 * time.Millisecond,
+		FailureLog:     slog.New(slog.NewTextHandler(&buf, nil)),
+	}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	for range 5 {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ready", nil))
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("log lines: got %d, want 5:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, "failing_checkers=\"[alpha beta zeta]\"") {
+			t.Errorf("line %d: %q does not list the failing checkers in name order", i, line)
+		}
+	}
+}
+
+// ---- root handler ----
+
+func TestRootHandlerDescribesEndpoints(t *testing.T) {
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:        freePort(t),
+		RootHandler: true,
+		PingPath:    "/ping",
+	}, fakeState{})
+	defer cleanup()
+
+	resp, err := http.Get(url + "/") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/: want 200, got %d", resp.StatusCode)
+	}
+	for _, path := range []string{"/ready", "/live", "/startup", "/ping"} {
+		if !strings.Contains(string(body), path) {
+			t.Errorf("/ body %q does not mention %s", body, path)
+		}
+	}
+	if got := doGET(t, url+"/favicon.ico"); got != http.StatusNoContent {
+		t.Errorf("/favicon.ico: want 204, got %d", got)
+	}
+	if got := doGET(t, url+"/unknown"); got != http.StatusNotFound {
+		t.Errorf("/unknown: want 404, got %d", got)
+	}
+}
+
+func TestMiddlewareWrapsProbe(t *testing.T) {
+	header := func(v string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Middleware", v)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:       freePort(t),
+		Middleware: []func(http.Handler) http.Handler{header("outer"), header("inner")},
+	}, fakeState{ready: true, started: true})
+	defer cleanup()
+
+	resp, err := http.Get(url + "/ready") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/ready: got %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Values("X-Middleware"); len(got) != 2 || got[0] != "outer" || got[1] != "inner" {
+		t.Errorf("X-Middleware: got %v, want [outer inner]", got)
+	}
+}
+
+func TestRootHandlerDisabledByDefault(t *testing.T) {
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{Port: freePort(t)}, fakeState{})
+	defer cleanup()
+	if got := doGET(t, url+"/"); got != http.StatusNotFound {
+		t.Errorf("/: want 404, got %d", got)
+	}
+}
+
+// ---- pprof ----
+
+func TestPprofEnabled(t *testing.T) {
+	port := freePort(t)
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{Port: port, Pprof: true}, fakeState{})
+	defer cleanup()
+	if got := doGET(t, url+"/debug/pprof/cmdline"); got != http.StatusOK {
+		t.Errorf("/debug/pprof/cmdline: want 200, got %d", got)
+	}
+}
+
+func TestPprofTraceOutlivesWriteTimeout(t *testing.T) {
+	port := freePort(t)
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{Port: port, Pprof: true}, fakeState{})
+	defer cleanup()
+	// Longer than the probe server's 2s write timeout.
+	resp, err := http.Get(url + "/debug/pprof/trace?seconds=2.5") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading trace: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || len(body) == 0 {
+		t.Errorf("/debug/pprof/trace: got %d with %d bytes, want 200 with a trace", resp.StatusCode, len(body))
+	}
+	if got := doGET(t, url+"/live"); got != http.StatusOK {
+		t.Errorf("/live: want 200, got %d", got)
+	}
+}
+
+func TestPprofDisabledByDefault(t *testing.T) {
+	port := freePort(t)
+	url, cleanup := startProbeOnPort(t, port, fakeState{}, nil)
+	defer cleanup()
+	if got := doGET(t, url+"/debug/pprof/cmdline"); got != http.StatusNotFound {
+		t.Errorf("/debug/pprof/cmdline: want 404, got %d", got)
+	}
+}
+
+func TestPprofOnExistingMux(t *testing.T) {
+	mux := http.NewServeMux()
+	probe := check.NewExistingHTTPProbe(mux, check.HTTPOptions{Pprof: true})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	if got := doGET(t, ts.URL+"/debug/pprof/cmdline"); got != http.StatusOK {
+		t.Errorf("/debug/pprof/cmdline: want 200, got %d", got)
+	}
+}
+
 // ---- concurrency test ----
 
 func TestConcurrentReady(t *testing.T) {
@@ -385,9 +1000,96 @@ func TestShutdownWithExpiredContextReturnsPromptly(t *testing.T) {
 	}
 }
 
-// ---- httptest.NewRecorder unit tests for handler logic ----
+func TestShutdownForceClosesActiveKeepAliveConnection(t *testing.T) {
+	port := freePort(t)
+	var forced atomic.Bool
+	probe := check.NewHTTPProbe(check.HTTPOptions{
+		Port:                port,
+		Checkers:            check.NewRegistry(map[string]check.Checker{"slow": slowChecker{sleep: 10 * time.Second}}),
+		CheckerTimeout:      20 * time.Second,
+		OnForcedStop:        func() { forced.Store(true) },
+		ForceCloseOnTimeout: true,
+	})
+	baseURL, _ := startProbe(t, probe, port, fakeState{ready: true})
 
-func TestHandlerReadyUnit(t *testing.T) {
+	// Warm up a keep-alive connection, then keep a request in flight on it.
+	client := &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: 1}}
+	resp, err := client.Get(baseURL + "/live")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	reqErr := make(chan error, 1)
+	go func() {
+		resp, err := client.Get(baseURL + "/ready")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		reqErr <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	probe.Shutdown(ctx)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Shutdown with a request in flight took %v, want prompt return after the deadline", elapsed)
+	}
+	select {
+	case err := <-reqErr:
+		if err == nil {
+			t.Error("in-flight request: got a response, want the connection closed")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("in-flight request still open after Shutdown")
+	}
+	if !forced.Load() {
+		t.Error("OnForcedStop not called")
+	}
+}
+
+func TestShutdownLeavesInFlightRequestByDefault(t *testing.T) {
+	port := freePort(t)
+	var forced atomic.Bool
+	probe := check.NewHTTPProbe(check.HTTPOptions{
+		Port:           port,
+		Checkers:       check.NewRegistry(map[string]check.Checker{"slow": slowChecker{sleep: 500 * time.Millisecond}}),
+		CheckerTimeout: 5 * time.Second,
+		OnForcedStop:   func() { forced.Store(true) },
+	})
+	baseURL, _ := startProbe(t, probe, port, fakeState{ready: true})
+
+	reqErr := make(chan error, 1)
+	go func() {
+		resp, err := http.Get(baseURL + "/ready") //nolint:noctx
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		reqErr <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	probe.Shutdown(ctx)
+	if !forced.Load() {
+		t.Error("OnForcedStop not called with a request in flight at the deadline")
+	}
+	select {
+	case err := <-reqErr:
+		if err != nil {
+			t.Errorf("in-flight request: got %v, want it to finish without ForceCloseOnTimeout", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("in-flight request did not finish")
+	}
+}
+
+// ---- httptest.NewRecorder unit tests for handler logic ----
+
+func TestHandlerReadyUnit(t *testing.T) {
 	// We test handler logic using a thin probe started on a real port but
 	// call via httptest.NewRecorder-style pattern through an in-process HTTP call.
 	// (Direct handler access requires exporting; we use integration calls above.)
@@ -484,3 +1186,1019 @@ func TestOnlyGETWrapperViaRecorder(t *testing.T) {
 		_ = rec.Result()
 	}
 }
+
+func TestSplitReadyAndLivePorts(t *testing.T) {
+	port, readyPort, livePort := freePort(t), freePort(t), freePort(t)
+	_, cleanup := startHTTPProbe(t, check.HTTPOptions{Port: port, ReadyPort: readyPort, LivePort: livePort}, fakeState{ready: true, started: true})
+	defer cleanup()
+
+	base := func(p int) string { return fmt.Sprintf("http://127.0.0.1:%d", p) }
+	if code := doGET(t, base(readyPort)+"/ready"); code != http.StatusOK {
+		t.Errorf("/ready on ReadyPort: got %d, want 200", code)
+	}
+	if code := doGET(t, base(livePort)+"/live"); code != http.StatusOK {
+		t.Errorf("/live on LivePort: got %d, want 200", code)
+	}
+	if code := doGET(t, base(port)+"/startup"); code != http.StatusOK {
+		t.Errorf("/startup on Port: got %d, want 200", code)
+	}
+	if code := doGET(t, base(port)+"/ready"); code != http.StatusNotFound {
+		t.Errorf("/ready on Port: got %d, want 404", code)
+	}
+	if code := doGET(t, base(readyPort)+"/live"); code != http.StatusNotFound {
+		t.Errorf("/live on ReadyPort: got %d, want 404", code)
+	}
+}
+
+func TestPartialStartReleasesListeners(t *testing.T) {
+	port, readyPort := freePort(t), freePort(t)
+	busy, err := net.Listen("tcp", fmt.Sprintf(":%d", readyPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = busy.Close() }()
+
+	probe := check.NewHTTPProbe(check.HTTPOptions{Port: port, ReadyPort: readyPort, ShutdownTimeout: time.Second})
+	err = probe.Start(fakeState{ready: true}, func() { t.Error("onStarted called after a failed start") })
+	if err == nil {
+		t.Fatal("Start: expected error for the busy ReadyPort, got nil")
+	}
+	if !strings.Contains(err.Error(), fmt.Sprint(readyPort)) {
+		t.Errorf("Start: got %v, want the bind error for port %d", err, readyPort)
+	}
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatalf("main port still bound after failed start: %v", err)
+	}
+	_ = ln.Close()
+	probe.Shutdown(context.Background()) // must not panic after a failed start
+}
+
+func TestInternalProbeListenerDetail(t *testing.T) {
+	port, internalPort := freePort(t), freePort(t)
+	checkers := map[string]check.Checker{"db": okChecker{}, "cache": errChecker{"down"}}
+	_, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:            port,
+		InternalPort:    internalPort,
+		InternalVerbose: true,
+		Checkers:        check.NewRegistry(checkers),
+		StatusPath:      "/status",
+		Status:          func() any { return map[string]bool{"ready": true} },
+	}, fakeState{ready: true, started: true})
+	defer cleanup()
+
+	get := func(p int, path string) (*http.Response, string) {
+		t.Helper()
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", p, path)) //nolint:noctx
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, body := get(port, "/ready")
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("public /ready: got %d, want 503", resp.StatusCode)
+	}
+	if body != "" || resp.Header.Get(check.NotReadyReasonHeader) != "" {
+		t.Errorf("public /ready: want bare status, got body %q, reason %q", body, resp.Header.Get(check.NotReadyReasonHeader))
+	}
+	if resp, _ := get(port, "/status"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("public /status: got %d, want 404", resp.StatusCode)
+	}
+
+	resp, body = get(internalPort, "/ready")
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("internal /ready: got %d, want 503", resp.StatusCode)
+	}
+	if !strings.Contains(body, `"cache"`) || resp.Header.Get(check.NotReadyReasonHeader) != "checker-failed" {
+		t.Errorf("internal /ready: want checker details, got body %q, reason %q", body, resp.Header.Get(check.NotReadyReasonHeader))
+	}
+	if resp, _ := get(internalPort, "/status"); resp.StatusCode != http.StatusOK {
+		t.Errorf("internal /status: got %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestReadyBodyOnFailureOnly(t *testing.T) {
+	get := func(t *testing.T, url string) (*http.Response, string) {
+		t.Helper()
+		resp, err := http.Get(url + "/ready") //nolint:noctx
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	t.Run("passing", func(t *testing.T) {
+		url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+			Port:                   freePort(t),
+			Checkers:               check.NewRegistry(map[string]check.Checker{"db": okChecker{}}),
+			ReadyBodyOnFailureOnly: true,
+		}, fakeState{ready: true})
+		defer cleanup()
+		resp, body := get(t, url)
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("got %d, want 200", resp.StatusCode)
+		}
+		if body != "" || resp.Header.Get("Content-Type") != "" {
+			t.Errorf("want no body and no Content-Type, got %q (%q)", body, resp.Header.Get("Content-Type"))
+		}
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+			Port:                   freePort(t),
+			Checkers:               check.NewRegistry(map[string]check.Checker{"db": okChecker{}, "cache": errChecker{"down"}}),
+			ReadyBodyOnFailureOnly: true,
+		}, fakeState{ready: true})
+		defer cleanup()
+		resp, body := get(t, url)
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("got %d, want 503", resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type: got %q, want application/json", ct)
+		}
+		if !strings.Contains(body, `"cache":"error: down"`) {
+			t.Errorf("want checker results in body, got %q", body)
+		}
+	})
+}
+
+func TestReadyResultMarshaler(t *testing.T) {
+	envelope := func(results map[string]string) ([]byte, string, error) {
+		b, err := json.Marshal(map[string]any{"data": map[string]any{"checks": results}})
+		return b, "application/vnd.example+json", err
+	}
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:            freePort(t),
+		Checkers:        check.NewRegistry(map[string]check.Checker{"db": okChecker{}}),
+		ResultMarshaler: envelope,
+	}, fakeState{ready: true})
+	defer cleanup()
+
+	resp, err := http.Get(url + "/ready") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got %d, want 200", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/vnd.example+json" {
+		t.Errorf("Content-Type: got %q", ct)
+	}
+	if want := `{"data":{"checks":{"db":"ok"}}}`; string(body) != want {
+		t.Errorf("body: got %s, want %s", body, want)
+	}
+}
+
+func TestReadyResultMarshalerError(t *testing.T) {
+	var reported error
+	var mu sync.Mutex
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:     freePort(t),
+		Checkers: check.NewRegistry(map[string]check.Checker{"db": okChecker{}}),
+		ResultMarshaler: func(map[string]string) ([]byte, string, error) {
+			return nil, "", errors.New("boom")
+		},
+		ErrorHandler: func(err error) {
+			mu.Lock()
+			reported = err
+			mu.Unlock()
+		},
+	}, fakeState{ready: true})
+	defer cleanup()
+
+	if code := doGET(t, url+"/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("got %d, want 503", code)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if reported == nil || !strings.Contains(reported.Error(), "boom") {
+		t.Errorf("error handler: got %v, want the marshaler error", reported)
+	}
+}
+
+func TestReadyRunCheckersDuringShutdown(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+			Port:                      freePort(t),
+			Checkers:                  check.NewRegistry(map[string]check.Checker{"db": okChecker{}}),
+			RunCheckersDuringShutdown: enabled,
+		}, fakeState{ready: true, shuttingDown: true})
+
+		resp, err := http.Get(url + "/ready") //nolint:noctx
+		if err != nil {
+			cleanup()
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		cleanup()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("enabled=%v: got %d, want 503", enabled, resp.StatusCode)
+		}
+		if got := strings.Contains(string(body), `"db":"ok"`); got != enabled {
+			t.Errorf("enabled=%v: body %q, want checker results: %v", enabled, body, enabled)
+		}
+	}
+}
+
+func TestReadyDetachedCheckerContext(t *testing.T) {
+	for _, detached := range []bool{false, true} {
+		var mu sync.Mutex
+		var failures []error
+		mux := http.NewServeMux()
+		check.NewExistingHTTPProbe(mux, check.HTTPOptions{
+			Checkers:               check.NewRegistry(map[string]check.Checker{"db": slowChecker{100 * time.Millisecond}}),
+			CheckerTimeout:         time.Second,
+			DetachedCheckerContext: detached,
+			OnCheckerFailure: func(_ string, err error) {
+				mu.Lock()
+				failures = append(failures, err)
+				mu.Unlock()
+			},
+		}).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+
+		// One client gives up mid-check while another waits for its answer.
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		abandoned, patient := httptest.NewRecorder(), httptest.NewRecorder()
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			mux.ServeHTTP(abandoned, httptest.NewRequest(http.MethodGet, "/ready", nil).WithContext(ctx))
+		}()
+		go func() {
+			defer wg.Done()
+			mux.ServeHTTP(patient, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		}()
+		wg.Wait()
+		cancel()
+
+		if patient.Code != http.StatusOK {
+			t.Errorf("detached=%v: concurrent request got %d, want 200", detached, patient.Code)
+		}
+		mu.Lock()
+		got := len(failures)
+		mu.Unlock()
+		if detached {
+			if abandoned.Code != http.StatusOK {
+				t.Errorf("detached: cancelled request got %d, want 200", abandoned.Code)
+			}
+			if got != 0 {
+				t.Errorf("detached: client cancellation recorded checker failures: %v", failures)
+			}
+		} else if got == 0 {
+			t.Error("request-scoped: expected the cancelled check to record a failure")
+		}
+	}
+}
+
+// warnChecker is a DetailedChecker that always warns with msg.
+type warnChecker struct{ msg string }
+
+func (warnChecker) Check(_ context.Context) error { return nil }
+
+func (w warnChecker) CheckDetailed(_ context.Context) check.CheckResult {
+	return check.CheckResult{Status: check.StatusWarn, Message: w.msg}
+}
+
+func TestReadyDetailedCheckerWarn(t *testing.T) {
+	checkers := map[string]check.Checker{"db": okChecker{}, "replica": warnChecker{"lag 5s"}}
+	get := func(opts check.HTTPOptions) (int, string) {
+		t.Helper()
+		opts.Port = freePort(t)
+		opts.Checkers = check.NewRegistry(checkers)
+		url, cleanup := startHTTPProbe(t, opts, fakeState{ready: true})
+		defer cleanup()
+		resp, err := http.Get(url + "/ready") //nolint:noctx
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get(check.HTTPOptions{})
+	if code != http.StatusOK {
+		t.Errorf("warn: got %d, want 200", code)
+	}
+	if !strings.Contains(body, `"replica":"warn: lag 5s"`) || !strings.Contains(body, `"db":"ok"`) {
+		t.Errorf("warn: body %q lacks the warning note", body)
+	}
+
+	if code, body = get(check.HTTPOptions{HealthJSON: true}); code != http.StatusOK || !strings.Contains(body, `"status":"warn"`) {
+		t.Errorf("health+json: got %d %q, want 200 with status warn", code, body)
+	}
+
+	if code, _ = get(check.HTTPOptions{FailOnWarn: true}); code != http.StatusServiceUnavailable {
+		t.Errorf("FailOnWarn: got %d, want 503", code)
+	}
+	if code, _ = get(check.HTTPOptions{FailOnWarn: true, FailureTolerance: 0.5}); code != http.StatusOK {
+		t.Errorf("FailOnWarn within tolerance: got %d, want 200", code)
+	}
+}
+
+// detailedErrChecker passes Check but fails CheckDetailed, so /ready must
+// prefer the detailed result.
+type detailedErrChecker struct{}
+
+func (detailedErrChecker) Check(_ context.Context) error { return nil }
+
+func (detailedErrChecker) CheckDetailed(_ context.Context) check.CheckResult {
+	return check.CheckResult{Status: check.StatusError, Message: "primary down"}
+}
+
+func TestReadyDetailedCheckerError(t *testing.T) {
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:     freePort(t),
+		Checkers: check.NewRegistry(map[string]check.Checker{"db": detailedErrChecker{}}),
+	}, fakeState{ready: true})
+	defer cleanup()
+	if code := doGET(t, url+"/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("StatusError: got %d, want 503", code)
+	}
+}
+
+func TestReadyReadinessDecider(t *testing.T) {
+	// The cache is advisory: only the database decides readiness.
+	decider := func(results map[string]string) bool { return results["db"] == "ok" }
+	get := func(checkers map[string]check.Checker) (int, string) {
+		t.Helper()
+		url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+			Port:             freePort(t),
+			Checkers:         check.NewRegistry(checkers),
+			ReadinessDecider: decider,
+		}, fakeState{ready: true})
+		defer cleanup()
+		resp, err := http.Get(url + "/ready") //nolint:noctx
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	code, body := get(map[string]check.Checker{"db": okChecker{}, "cache": errChecker{"down"}})
+	if code != http.StatusOK {
+		t.Errorf("cache down: got %d, want 200", code)
+	}
+	if !strings.Contains(body, `"cache":"error: down"`) {
+		t.Errorf("cache down: body %q should still report the cache", body)
+	}
+	if code, _ = get(map[string]check.Checker{"db": errChecker{"down"}, "cache": okChecker{}}); code != http.StatusServiceUnavailable {
+		t.Errorf("db down: got %d, want 503", code)
+	}
+}
+
+func TestReadyAcceptNegotiation(t *testing.T) {
+	port := freePort(t)
+	checkers := map[string]check.Checker{"db": okChecker{}, "cache": errChecker{"down"}}
+	url, cleanup := startProbeOnPort(t, port, fakeState{ready: true}, checkers)
+	defer cleanup()
+
+	get := func(accept string) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, url+"/ready", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /ready: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, body := get("text/plain")
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("text/plain: Content-Type %q", ct)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("text/plain: got %d, want 503", resp.StatusCode)
+	}
+	if want := "cache=error: down\ndb=ok\n"; body != want {
+		t.Errorf("text/plain body: got %q, want %q", body, want)
+	}
+
+	for _, accept := range []string{"", "application/json", "*/*", "text/plain;q=0.5, application/json"} {
+		resp, _ := get(accept)
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Accept %q: Content-Type %q, want application/json", accept, ct)
+		}
+	}
+}
+
+func TestReadyTruncatesLongCheckerErrors(t *testing.T) {
+	port := freePort(t)
+	long := strings.Repeat("x", 1000)
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:               port,
+		MaxCheckerErrorLen: 10,
+		Checkers:           check.NewRegistry(map[string]check.Checker{"db": errChecker{long}}),
+	}, fakeState{ready: true})
+	defer cleanup()
+
+	resp, err := http.Get(url + "/ready") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if want := "error: xxxxxxxxxx…"; body["db"] != want {
+		t.Errorf("got %q, want %q", body["db"], want)
+	}
+}
+
+// failingWriter is a ResponseWriter whose body writes always fail.
+type failingWriter struct {
+	header http.Header
+	code   int
+}
+
+func (f *failingWriter) Header() http.Header       { return f.header }
+func (f *failingWriter) WriteHeader(code int)      { f.code = code }
+func (f *failingWriter) Write([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestReadyBodyWriteErrorReported(t *testing.T) {
+	var reported error
+	mux := http.NewServeMux()
+	probe := check.NewExistingHTTPProbe(mux, check.HTTPOptions{
+		CheckerTimeout: time.Second,
+		Checkers:       check.NewRegistry(map[string]check.Checker{"db": errChecker{"down"}}),
+		ErrorHandler:   func(err error) { reported = err },
+	})
+	if err := probe.Start(fakeState{ready: true}, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := &failingWriter{header: make(http.Header)}
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if w.code != http.StatusServiceUnavailable {
+		t.Errorf("status: got %d, want 503", w.code)
+	}
+	if reported == nil || !strings.Contains(reported.Error(), "connection reset") {
+		t.Errorf("ErrorHandler: got %v, want write error", reported)
+	}
+}
+
+func TestPingEndpoint(t *testing.T) {
+	checkers := check.NewRegistry(map[string]check.Checker{"db": errChecker{"down"}})
+	tests := []struct {
+		name  string
+		state fakeState
+		want  int
+	}{
+		{"not ready, checkers failing", fakeState{}, http.StatusOK},
+		{"draining", fakeState{ready: true, shuttingDown: true}, http.StatusServiceUnavailable},
+	}
+	for _, tc := range tests {
+		port := freePort(t)
+		url, cleanup := startHTTPProbe(t, check.HTTPOptions{Port: port, PingPath: "/ping", Checkers: checkers}, tc.state)
+		if got := doGET(t, url+"/ping"); got != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, got, tc.want)
+		}
+		cleanup()
+	}
+}
+
+func TestUnhealthyStatusCodeOverride(t *testing.T) {
+	port := freePort(t)
+	url, cleanup := startHTTPProbe(t, check.HTTPOptions{Port: port, UnhealthyStatusCode: http.StatusInternalServerError},
+		fakeState{ready: false, shuttingDown: true, started: false})
+	defer cleanup()
+
+	for _, path := range []string{"/ready", "/live", "/startup"} {
+		if got := doGET(t, url+path); got != http.StatusInternalServerError {
+			t.Errorf("%s: got %d, want 500", path, got)
+		}
+	}
+}
+
+func TestHTTPProbeServesOnListener(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	probe := check.NewHTTPProbe(check.HTTPOptions{Listener: lis, ShutdownTimeout: time.Second})
+	if err := probe.Start(fakeState{ready: true, started: true}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	base := "http://" + lis.Addr().String()
+	for _, path := range []string{"/ready", "/live", "/startup"} {
+		resp, err := http.Get(base + path) //nolint:noctx
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: want 200, got %d", path, resp.StatusCode)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	probe.Shutdown(ctx)
+	if _, err := http.Get(base + "/live"); err == nil { //nolint:noctx
+		t.Error("GET after Shutdown: expected error, listener still open")
+	}
+}
+
+func TestNotReadyReasonHeader(t *testing.T) {
+	failing := check.NewRegistry(map[string]check.Checker{
+		"db": errChecker{msg: "down"},
+	})
+	tests := []struct {
+		name  string
+		state fakeState
+		reg   *check.Registry
+		want  string
+	}{
+		{"not ready", fakeState{}, nil, "not-ready"},
+		{"shutting down", fakeState{ready: true, shuttingDown: true}, nil, "shutting-down"},
+		{"checker failed", fakeState{ready: true}, failing, "checker-failed"},
+		{"ready", fakeState{ready: true}, nil, ""},
+	}
+	for _, tc := range tests {
+		mux := http.NewServeMux()
+		check.NewExistingHTTPProbe(mux, check.HTTPOptions{Checkers: tc.reg, CheckerTimeout: time.Second}).Start(tc.state, func() {}) //nolint:errcheck
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		if got := rec.Header().Get(check.NotReadyReasonHeader); got != tc.want {
+			t.Errorf("%s: header = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestFailureTolerance(t *testing.T) {
+	tests := []struct {
+		tolerance float64
+		failing   int
+		want      int
+	}{
+		{0, 0, http.StatusOK},
+		{0, 1, http.StatusServiceUnavailable},
+		{0.25, 1, http.StatusOK},
+		{0.25, 2, http.StatusServiceUnavailable},
+		{0.5, 2, http.StatusOK},
+		{1, 4, http.StatusOK},
+	}
+	for _, tc := range tests {
+		checkers := map[string]check.Checker{}
+		for i := 0; i < 4; i++ {
+			if i < tc.failing {
+				checkers[fmt.Sprintf("c%d", i)] = errChecker{msg: "down"}
+			} else {
+				checkers[fmt.Sprintf("c%d", i)] = okChecker{}
+			}
+		}
+		mux := http.NewServeMux()
+		opts := check.HTTPOptions{
+			Checkers:         check.NewRegistry(checkers),
+			CheckerTimeout:   time.Second,
+			FailureTolerance: tc.tolerance,
+		}
+		check.NewExistingHTTPProbe(mux, opts).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		if rec.Code != tc.want {
+			t.Errorf("tolerance %v, %d/4 failing: got %d, want %d", tc.tolerance, tc.failing, rec.Code, tc.want)
+		}
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if tc.failing > 0 && body["c0"] == "ok" {
+			t.Errorf("tolerance %v: failing checker missing from body: %v", tc.tolerance, body)
+		}
+	}
+}
+
+func TestFailureToleranceIgnoresDisabledCheckers(t *testing.T) {
+	// 1 of 2 enabled checkers failing is 50%, over a 40% tolerance however
+	// many checkers are disabled.
+	reg := check.NewRegistry(map[string]check.Checker{
+		"db":    errChecker{msg: "down"},
+		"cache": okChecker{},
+		"queue": okChecker{},
+		"blob":  okChecker{},
+	})
+	reg.SetDisabled("queue", true)
+	reg.SetDisabled("blob", true)
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{
+		Checkers:         reg,
+		CheckerTimeout:   time.Second,
+		FailureTolerance: 0.4,
+	}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("1 of 2 enabled failing, 2 disabled: got %d, want 503", rec.Code)
+	}
+
+	reg.SetDisabled("db", true)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("failing checker disabled: got %d, want 200", rec.Code)
+	}
+}
+
+func TestExistingHTTPProbePathPrefix(t *testing.T) {
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{PathPrefix: "/internal", PingPath: "/ping"}
+	if err := check.NewExistingHTTPProbe(mux, opts).Start(fakeState{ready: true, started: true}, func() {}); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]int{
+		"/internal/ready":   http.StatusOK,
+		"/internal/live":    http.StatusOK,
+		"/internal/startup": http.StatusOK,
+		"/internal/ping":    http.StatusOK,
+		"/ready":            http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: got %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
+// toggleChecker fails while its flag is set.
+type toggleChecker struct{ fail atomic.Bool }
+
+func (c *toggleChecker) Check(context.Context) error {
+	if c.fail.Load() {
+		return errors.New("down")
+	}
+	return nil
+}
+
+func TestCheckerTransitionHooks(t *testing.T) {
+	var events []string
+	c := &toggleChecker{}
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{
+		Checkers:          check.NewRegistry(map[string]check.Checker{"db": c}),
+		CheckerTimeout:    time.Second,
+		OnCheckerFailure:  func(name string, err error) { events = append(events, "fail "+name+": "+err.Error()) },
+		OnCheckerRecovery: func(name string) { events = append(events, "recover "+name) },
+	}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	scrape := func() {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ready", nil))
+	}
+
+	scrape() // ok: no event
+	c.fail.Store(true)
+	scrape() // ok→fail
+	scrape() // still failing: deduped
+	c.fail.Store(false)
+	scrape() // fail→ok
+	scrape() // still ok
+
+	want := []string{"fail db: down", "recover db"}
+	if strings.Join(events, "|") != strings.Join(want, "|") {
+		t.Errorf("events: got %q, want %q", events, want)
+	}
+}
+
+func TestReadinessFailureJoinsErrors(t *testing.T) {
+	var joined []error
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{
+		Checkers: check.NewRegistry(map[string]check.Checker{
+			"cache": errChecker{"evicted"},
+			"db":    errChecker{"down"},
+			"queue": okChecker{},
+		}),
+		CheckerTimeout:     time.Second,
+		OnReadinessFailure: func(err error) { joined = append(joined, err) },
+	}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+	if len(joined) != 1 {
+		t.Fatalf("handler calls: got %d, want 1", len(joined))
+	}
+	msg := joined[0].Error()
+	for _, want := range []string{"ready checks failed", "cache: evicted", "db: down"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("joined error %q does not contain %q", msg, want)
+		}
+	}
+	if strings.Contains(msg, "queue") {
+		t.Errorf("joined error %q mentions passing checker queue", msg)
+	}
+}
+
+func TestLoadGate(t *testing.T) {
+	var load atomic.Int64
+	calls := &okCounter{}
+	mux := http.NewServeMux()
+	opts := check.HTTPOptions{
+		Checkers:       check.NewRegistry(map[string]check.Checker{"db": calls}),
+		CheckerTimeout: time.Second,
+		LoadGate:       func() (int, int) { return int(load.Load()), 10 },
+	}
+	check.NewExistingHTTPProbe(mux, opts).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return rec
+	}
+
+	load.Store(9)
+	if rec := get(); rec.Code != http.StatusOK {
+		t.Errorf("below limit: got %d, want 200", rec.Code)
+	}
+	load.Store(10)
+	rec := get()
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("at limit: got %d, want 503", rec.Code)
+	}
+	if got := rec.Header().Get(check.NotReadyReasonHeader); got != "overloaded" {
+		t.Errorf("reason: got %q, want overloaded", got)
+	}
+	if got := rec.Header().Get(check.LoadHeader); got != "10/10" {
+		t.Errorf("load header: got %q, want 10/10", got)
+	}
+	if n := calls.n.Load(); n != 1 {
+		t.Errorf("checker calls: got %d, want 1 (skipped while overloaded)", n)
+	}
+	load.Store(3)
+	if rec := get(); rec.Code != http.StatusOK {
+		t.Errorf("back below limit: got %d, want 200", rec.Code)
+	}
+}
+
+type okCounter struct{ n atomic.Int32 }
+
+func (c *okCounter) Check(context.Context) error {
+	c.n.Add(1)
+	return nil
+}
+
+func TestShutdownSharesDeadlineAcrossListeners(t *testing.T) {
+	port, readyPort := freePort(t), freePort(t)
+	probe := check.NewHTTPProbe(check.HTTPOptions{
+		Port:           port,
+		ReadyPort:      readyPort,
+		Checkers:       check.NewRegistry(map[string]check.Checker{"slow": slowChecker{sleep: 10 * time.Second}}),
+		CheckerTimeout: 20 * time.Second,
+	})
+	startProbe(t, probe, port, fakeState{ready: true, started: true})
+
+	// Keep both listeners busy: a /ready request stuck in a checker on
+	// ReadyPort, and a half-written request on Port.
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/ready", readyPort))
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.Write([]byte("GET /startup HTTP/1.1\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// Serial shutdown would take at least twice the budget.
+	const budget = 500 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), budget)
+	defer cancel()
+	start := time.Now()
+	probe.Shutdown(ctx)
+	elapsed := time.Since(start)
+	if elapsed < budget {
+		t.Fatalf("Shutdown returned after %v, before the %v budget: listeners were not busy", elapsed, budget)
+	}
+	if elapsed >= 2*budget-100*time.Millisecond {
+		t.Errorf("Shutdown with two busy listeners took %v, want about %v", elapsed, budget)
+	}
+}
+
+func TestReadyRateLimit(t *testing.T) {
+	port := freePort(t)
+	calls := &okCounter{}
+	baseURL, cleanup := startHTTPProbe(t, check.HTTPOptions{
+		Port:           port,
+		Checkers:       check.NewRegistry(map[string]check.Checker{"db": calls}),
+		ReadyRateLimit: 10,
+	}, fakeState{ready: true, started: true})
+	defer cleanup()
+
+	var (
+		wg     sync.WaitGroup
+		served atomic.Int32
+	)
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				if code := doGET(t, baseURL+"/ready"); code != http.StatusOK {
+					t.Errorf("/ready: got %d, want 200 from the last results", code)
+					return
+				}
+				served.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// One initial run plus at most 10/s over half a second, with slack.
+	if n := calls.n.Load(); n < 2 || n > 8 {
+		t.Errorf("checker runs: got %d for %d requests, want about 6", n, served.Load())
+	}
+	if served.Load() <= calls.n.Load() {
+		t.Errorf("served %d requests with %d checker runs; the flood was not limited", served.Load(), calls.n.Load())
+	}
+}
+
+// slowCounter counts its runs and takes sleep to pass.
+type slowCounter struct {
+	okCounter
+	sleep time.Duration
+}
+
+func (c *slowCounter) Check(ctx context.Context) error {
+	c.n.Add(1)
+	return slowChecker{sleep: c.sleep}.Check(ctx)
+}
+
+func TestReadyRateLimitFloodBeforeFirstResult(t *testing.T) {
+	calls := &slowCounter{sleep: 300 * time.Millisecond}
+	mux := http.NewServeMux()
+	check.NewExistingHTTPProbe(mux, check.HTTPOptions{
+		Checkers:       check.NewRegistry(map[string]check.Checker{"db": calls}),
+		CheckerTimeout: time.Second,
+		ReadyRateLimit: 1,
+	}).Start(fakeState{ready: true, started: true}, func() {}) //nolint:errcheck
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("/ready: got %d, want 200 from the shared first run", rec.Code)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := calls.n.Load(); n != 1 {
+		t.Errorf("checker runs for a flood before the first result: got %d, want 1", n)
+	}
+}
+
+func TestCheckerDependencySkipsDependent(t *testing.T) {
+	migrations, report := &okCounter{}, &okCounter{}
+	mux := http.NewServeMux()
+	check.NewExistingHTTPProbe(mux, check.HTTPOptions{
+		Checkers: check.NewRegistry(map[string]check.Checker{
+			"db":         errChecker{"down"},
+			"migrations": migrations,
+			"report":     report,
+			"cache":      okChecker{},
+		}),
+		CheckerTimeout: time.Second,
+		CheckerDependencies: map[string][]string{
+			"migrations": {"db"},
+			"report":     {"migrations", "cache"},
+		},
+	}).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status: got %d, want 503", rec.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	want := map[string]string{
+		"db":         "error: down",
+		"migrations": "skipped: db failed",
+		"report":     "skipped: migrations failed",
+		"cache":      "ok",
+	}
+	for name, w := range want {
+		if body[name] != w {
+			t.Errorf("%s: got %q, want %q", name, body[name], w)
+		}
+	}
+	if migrations.n.Load() != 0 || report.n.Load() != 0 {
+		t.Errorf("skipped checkers ran: migrations %d, report %d times", migrations.n.Load(), report.n.Load())
+	}
+}
+
+func TestCheckerDependencySkipIsRecorded(t *testing.T) {
+	db := &toggleChecker{}
+	var mu sync.Mutex
+	failed := make(map[string]bool)
+	var readinessErr error
+	reg := check.NewRegistry(map[string]check.Checker{"db": db, "migrations": okChecker{}})
+	mux := http.NewServeMux()
+	check.NewExistingHTTPProbe(mux, check.HTTPOptions{
+		Checkers:            reg,
+		CheckerTimeout:      time.Second,
+		CheckerDependencies: map[string][]string{"migrations": {"db"}},
+		OnCheckerFailure: func(name string, _ error) {
+			mu.Lock()
+			failed[name] = true
+			mu.Unlock()
+		},
+		OnReadinessFailure: func(err error) { readinessErr = err },
+	}).Start(fakeState{ready: true}, func() {}) //nolint:errcheck
+	ready := func() {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ready", nil))
+	}
+
+	ready()
+	db.fail.Store(true)
+	ready()
+	r, _ := reg.Result("migrations")
+	if r.Err == nil || r.Err.Error() != "skipped: db failed" {
+		t.Errorf("recorded migrations result: got %v, want skipped: db failed", r.Err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !failed["db"] || !failed["migrations"] {
+		t.Errorf("failure hooks fired for %v, want db and migrations", failed)
+	}
+	if readinessErr == nil || !strings.Contains(readinessErr.Error(), "migrations: skipped: db failed") {
+		t.Errorf("OnReadinessFailure: got %v, want it to name the skipped migrations", readinessErr)
+	}
+}
+
+func TestExistingMuxPatternConflict(t *testing.T) {
+	noop := func(http.ResponseWriter, *http.Request) {}
+
+	exact := http.NewServeMux()
+	exact.HandleFunc("/ready", noop)
+	err := check.NewExistingHTTPProbe(exact, check.HTTPOptions{}).Start(fakeState{}, func() {
+		t.Error("onStarted called after a failed registration")
+	})
+	if !errors.Is(err, check.ErrPatternConflict) || !strings.Contains(err.Error(), `"/ready"`) {
+		t.Errorf("exact collision: got %v, want ErrPatternConflict naming /ready", err)
+	}
+	if _, pattern := exact.Handler(httptest.NewRequest(http.MethodGet, "/live", nil)); pattern != "" {
+		t.Errorf("/live registered as %q despite the collision, want nothing registered", pattern)
+	}
+
+	// A method-specific wildcard is neither more nor less specific than
+	// /ready, so ServeMux would panic on registration.
+	wildcard := http.NewServeMux()
+	wildcard.HandleFunc("GET /{name}", noop)
+	err = check.NewExistingHTTPProbe(wildcard, check.HTTPOptions{}).Start(fakeState{}, func() {})
+	if !errors.Is(err, check.ErrPatternConflict) {
+		t.Errorf("conflicting wildcard: got %v, want ErrPatternConflict", err)
+	}
+
+	// A conflict on a path that sorts after the probe paths still leaves
+	// the mux untouched, so Start can be retried without that path.
+	nested := http.NewServeMux()
+	nested.HandleFunc("GET /x/{name}", noop)
+	err = check.NewExistingHTTPProbe(nested, check.HTTPOptions{PingPath: "/x/ping"}).Start(fakeState{}, func() {})
+	if !errors.Is(err, check.ErrPatternConflict) || !strings.Contains(err.Error(), `"/x/ping"`) {
+		t.Errorf("conflicting ping path: got %v, want ErrPatternConflict naming /x/ping", err)
+	}
+	if _, pattern := nested.Handler(httptest.NewRequest(http.MethodGet, "/live", nil)); pattern != "" {
+		t.Errorf("/live registered as %q despite the conflict, want nothing registered", pattern)
+	}
+	if err := check.NewExistingHTTPProbe(nested, check.HTTPOptions{}).Start(fakeState{}, func() {}); err != nil {
+		t.Errorf("retry without the ping path: unexpected error: %v", err)
+	}
+
+	// A catch-all does not conflict: the probe paths are more specific.
+	catchAll := http.NewServeMux()
+	catchAll.HandleFunc("/", noop)
+	if err := check.NewExistingHTTPProbe(catchAll, check.HTTPOptions{Pprof: true}).Start(fakeState{}, func() {}); err != nil {
+		t.Errorf("catch-all: unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// ListenOptions controls how standalone probes create their listeners.
+type ListenOptions struct {
+	// Host is the address the listeners bind to, e.g. "127.0.0.1" or "::1".
+	// Empty binds all interfaces.
+	Host string
+	// BindAttempts is the number of net.Listen attempts made while the
+	// address is in use, sleeping BindBackoff between them. Zero and one
+	// both mean a single attempt; other listen errors are never retried.
+	BindAttempts int
+	BindBackoff  time.Duration
+	// ReuseAddr sets SO_REUSEADDR on the listening socket when true and
+	// clears it when false (Unix only).
+	ReuseAddr bool
+	// Config, when set, creates the listeners in place of the default
+	// net.ListenConfig; ReuseAddr is then ignored.
+	Config *net.ListenConfig
+	// Clock drives the bind retry backoff. Nil means RealClock.
+	Clock Clock
+	// Context, when set, bounds the listen calls and bind retries, so a
+	// shutdown during startup stops them. Nil means context.Background().
+	Context context.Context
+}
+
+// addr returns the host:port to bind for port, bracketing IPv6 literals.
+func (lo ListenOptions) addr(port int) string {
+	return net.JoinHostPort(lo.Host, strconv.Itoa(port))
+}
+
+// listen opens a TCP listener on addr, retrying EADDRINUSE according to lo.
+// It returns the last error if every attempt fails, or the context's error
+// if it ends first.
+func listen(addr string, lo ListenOptions) (net.Listener, error) {
+	lc := net.ListenConfig{Control: setReuseAddr(lo.ReuseAddr)}
+	if lo.Config != nil {
+		lc = *lo.Config
+	}
+	ctx := lo.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var err error
+	for attempt := 0; ; attempt++ {
+		var ln net.Listener
+		ln, err = lc.Listen(ctx, "tcp", addr)
+		if err == nil {
+			return ln, nil
+		}
+		if attempt+1 >= lo.BindAttempts || !errors.Is(err, syscall.EADDRINUSE) {
+			return nil, err
+		}
+		select {
+		case <-clockOr(lo.Clock).After(lo.BindBackoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
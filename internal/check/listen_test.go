@@ -0,0 +1,194 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func holdPort(t *testing.T, port int) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatalf("hold port %d: %v", port, err)
+	}
+	return ln
+}
+
+func TestBindRetrySucceedsOnceFreed(t *testing.T) {
+	port := freePort(t)
+	held := holdPort(t, port)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = held.Close()
+	}()
+
+	probe := check.NewHTTPProbe(check.HTTPOptions{
+		Port:     port,
+		Checkers: check.NewRegistry(nil),
+		Listen:   check.ListenOptions{BindAttempts: 40, BindBackoff: 25 * time.Millisecond},
+	})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start with retry: %v", err)
+	}
+	defer probe.Shutdown(context.Background())
+	if got := doGET(t, fmt.Sprintf("http://127.0.0.1:%d/live", port)); got != 200 {
+		t.Errorf("/live: want 200, got %d", got)
+	}
+}
+
+func TestBindRetryGRPCSucceedsOnceFreed(t *testing.T) {
+	port := freePort(t)
+	held := holdPort(t, port)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = held.Close()
+	}()
+
+	probe := check.NewGRPCProbe(check.GRPCOptions{
+		Port:   port,
+		Listen: check.ListenOptions{BindAttempts: 40, BindBackoff: 25 * time.Millisecond},
+	})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start with retry: %v", err)
+	}
+	probe.Shutdown(context.Background())
+}
+
+func TestBindRetryReturnsLastError(t *testing.T) {
+	port := freePort(t)
+	held := holdPort(t, port)
+	defer func() { _ = held.Close() }()
+
+	probe := check.NewHTTPProbe(check.HTTPOptions{
+		Port:   port,
+		Listen: check.ListenOptions{BindAttempts: 3, BindBackoff: time.Millisecond},
+	})
+	if err := probe.Start(fakeState{}, func() {}); err == nil {
+		t.Error("expected error after exhausting retries, got nil")
+	}
+}
+
+func TestBindRetryOnlyOnAddressInUse(t *testing.T) {
+	var calls atomic.Int32
+	lc := &net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			calls.Add(1)
+			return syscall.EACCES
+		},
+	}
+	start := func(attempts int) int32 {
+		calls.Store(0)
+		probe := check.NewHTTPProbe(check.HTTPOptions{
+			Port:   freePort(t),
+			Listen: check.ListenOptions{Host: "127.0.0.1", Config: lc, BindAttempts: attempts, BindBackoff: time.Millisecond},
+		})
+		if err := probe.Start(fakeState{}, func() {}); !errors.Is(err, syscall.EACCES) {
+			t.Fatalf("Start: got %v, want EACCES", err)
+		}
+		return calls.Load()
+	}
+	// net may invoke Control more than once per listen call, so compare
+	// against a single attempt rather than a fixed count.
+	if once, five := start(1), start(5); five != once {
+		t.Errorf("Control calls: got %d with 5 attempts, want %d as with 1: only EADDRINUSE is retried", five, once)
+	}
+}
+
+func TestListenConfigControlInvoked(t *testing.T) {
+	var called atomic.Bool
+	lc := &net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			called.Store(true)
+			return nil
+		},
+	}
+	probe := check.NewHTTPProbe(check.HTTPOptions{
+		Port:   freePort(t),
+		Listen: check.ListenOptions{Config: lc},
+	})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer probe.Shutdown(context.Background())
+	if !called.Load() {
+		t.Error("ListenConfig.Control was not invoked")
+	}
+}
+
+func TestBindRetryStopsOnContextCancel(t *testing.T) {
+	port := freePort(t)
+	held := holdPort(t, port)
+	defer func() { _ = held.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	probe := check.NewHTTPProbe(check.HTTPOptions{
+		Port: port,
+		Listen: check.ListenOptions{
+			BindAttempts: 1000,
+			BindBackoff:  10 * time.Millisecond,
+			Context:      ctx,
+		},
+	})
+	start := time.Now()
+	err := probe.Start(fakeState{}, func() {})
+	if err == nil {
+		t.Fatal("expected error after cancel, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("bind retries outlived cancel: %v", elapsed)
+	}
+}
+
+func requireIPv6Loopback(t *testing.T) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	_ = ln.Close()
+}
+
+func TestListenHostIPv6HTTP(t *testing.T) {
+	requireIPv6Loopback(t)
+	port := freePort(t)
+	probe := check.NewHTTPProbe(check.HTTPOptions{
+		Port:   port,
+		Listen: check.ListenOptions{Host: "::1"},
+	})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start on ::1: %v", err)
+	}
+	defer probe.Shutdown(context.Background())
+	if got := doGET(t, fmt.Sprintf("http://[::1]:%d/live", port)); got != 200 {
+		t.Errorf("/live over [::1]: want 200, got %d", got)
+	}
+	if conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), time.Second); err == nil {
+		_ = conn.Close()
+		t.Error("probe bound to ::1 accepted an IPv4 connection")
+	}
+}
+
+func TestListenHostIPv6GRPC(t *testing.T) {
+	requireIPv6Loopback(t)
+	port := freePort(t)
+	probe := check.NewGRPCProbe(check.GRPCOptions{
+		Port:   port,
+		Listen: check.ListenOptions{Host: "::1"},
+	})
+	if err := probe.Start(fakeState{}, func() {}); err != nil {
+		t.Fatalf("Start on ::1: %v", err)
+	}
+	defer probe.Shutdown(context.Background())
+	client, conn := grpcHealthClient(t, fmt.Sprintf("[::1]:%d", port))
+	defer func() { _ = conn.Close() }()
+	checkStatus(t, client, "")
+}
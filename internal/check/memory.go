@@ -0,0 +1,33 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+type memoryChecker struct {
+	maxHeapBytes uint64
+}
+
+// NewMemoryChecker returns a Checker that fails when the Go heap (HeapAlloc)
+// exceeds maxHeapBytes, letting a pod shed load before it runs out of memory.
+//
+// runtime.ReadMemStats briefly stops the world. That is cheap at normal probe
+// frequencies (one call every few seconds), but avoid scraping /ready more
+// than a few times per second with this checker registered.
+func NewMemoryChecker(maxHeapBytes uint64) Checker {
+	return &memoryChecker{maxHeapBytes: maxHeapBytes}
+}
+
+func (m *memoryChecker) Check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	if ms.HeapAlloc > m.maxHeapBytes {
+		return fmt.Errorf("heap usage %d bytes exceeds limit %d bytes", ms.HeapAlloc, m.maxHeapBytes)
+	}
+	return nil
+}
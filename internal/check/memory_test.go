@@ -0,0 +1,23 @@
+package check_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func TestMemoryCheckerUnderThreshold(t *testing.T) {
+	c := check.NewMemoryChecker(math.MaxUint64)
+	if err := c.Check(context.Background()); err != nil {
+		t.Errorf("expected nil under threshold, got %v", err)
+	}
+}
+
+func TestMemoryCheckerOverThreshold(t *testing.T) {
+	c := check.NewMemoryChecker(1)
+	if err := c.Check(context.Background()); err == nil {
+		t.Error("expected error when heap exceeds 1 byte, got nil")
+	}
+}
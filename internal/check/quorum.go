@@ -0,0 +1,56 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type quorumChecker struct {
+	min      int
+	checkers []Checker
+}
+
+// Quorum returns a Checker that runs checkers concurrently and passes when at
+// least min of them succeed, e.g. "2 of 3 replicas reachable". Otherwise it
+// returns an error listing each failure by its position in checkers. A min
+// above len(checkers) requires all of them. A min below 1 is a wiring
+// mistake: the checker then always fails, naming it, without running
+// checkers. With no checkers it always fails.
+func Quorum(min int, checkers ...Checker) Checker {
+	if min > len(checkers) && min > 0 {
+		min = max(len(checkers), 1)
+	}
+	return &quorumChecker{min: min, checkers: checkers}
+}
+
+func (q *quorumChecker) Check(ctx context.Context) error {
+	if q.min < 1 {
+		return fmt.Errorf("quorum: min %d must be at least 1", q.min)
+	}
+	errs := make([]error, len(q.checkers))
+	done := make(chan struct{}, len(q.checkers))
+	for i, c := range q.checkers {
+		i, c := i, c
+		go func() {
+			errs[i] = c.Check(ctx)
+			done <- struct{}{}
+		}()
+	}
+	for range q.checkers {
+		<-done
+	}
+
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("[%d] %v", i, err))
+		}
+	}
+	healthy := len(q.checkers) - len(failures)
+	if healthy >= q.min {
+		return nil
+	}
+	return fmt.Errorf("quorum not met: %d of %d healthy, need %d: %s",
+		healthy, len(q.checkers), q.min, strings.Join(failures, "; "))
+}
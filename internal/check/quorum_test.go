@@ -0,0 +1,33 @@
+package check_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func TestQuorum(t *testing.T) {
+	ok, bad := okChecker{}, errChecker{"down"}
+	tests := []struct {
+		name     string
+		min      int
+		checkers []check.Checker
+		wantErr  bool
+	}{
+		{"all pass", 2, []check.Checker{ok, ok, ok}, false},
+		{"exactly min", 2, []check.Checker{ok, bad, ok}, false},
+		{"below min", 2, []check.Checker{ok, bad, bad}, true},
+		{"none pass", 1, []check.Checker{bad, bad}, true},
+		{"zero min rejected", 0, []check.Checker{ok, ok}, true},
+		{"negative min rejected", -1, []check.Checker{ok, ok}, true},
+		{"min above count needs all", 5, []check.Checker{ok, ok, ok}, false},
+		{"min above count fails on one", 5, []check.Checker{ok, bad, ok}, true},
+	}
+	for _, tc := range tests {
+		err := check.Quorum(tc.min, tc.checkers...).Check(context.Background())
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: got err %v, wantErr %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
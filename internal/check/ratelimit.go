@@ -0,0 +1,104 @@
+package check
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// readyLimiter caps how often /ready runs its checkers. It is a token bucket
+// holding a single token that refills at rps per second; requests that find
+// the bucket empty are answered from the last results instead.
+type readyLimiter struct {
+	clock    Clock
+	interval time.Duration
+	// stored is closed once the first results are stored.
+	stored chan struct{}
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	running bool // the first run is in progress
+	cached  map[string]string
+}
+
+func newReadyLimiter(rps int, clock Clock) *readyLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	return &readyLimiter{clock: clockOr(clock), interval: time.Second / time.Duration(rps), tokens: 1, stored: make(chan struct{})}
+}
+
+// take reports whether a checker run is allowed now. Until the first results
+// are stored, only the first request runs the checkers; later ones get wait,
+// which is closed once those results are stored, so a flood against slow
+// checkers reaches the dependencies once.
+func (l *readyLimiter) take() (cached map[string]string, ok bool, wait <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	if !l.last.IsZero() {
+		l.tokens = min(1, l.tokens+float64(now.Sub(l.last))/float64(l.interval))
+	}
+	l.last = now
+	if l.cached == nil {
+		if l.running {
+			return nil, false, l.stored
+		}
+		l.running = true
+		l.tokens = max(0, l.tokens-1)
+		return nil, true, nil
+	}
+	if l.tokens >= 1 {
+		l.tokens--
+		return nil, true, nil
+	}
+	return maps.Clone(l.cached), false, nil
+}
+
+func (l *readyLimiter) store(results map[string]string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cached == nil {
+		close(l.stored)
+	}
+	l.cached = maps.Clone(results)
+}
+
+// results returns a copy of the last stored results.
+func (l *readyLimiter) results() map[string]string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return maps.Clone(l.cached)
+}
+
+// readyResults runs the /ready checkers, or returns the last results when
+// opts.ReadyRateLimit has been reached. Requests that arrive while the first
+// run is in progress share its results; one whose ctx ends first reports
+// every checker as failing with the ctx error.
+func readyResults(ctx context.Context, checkers map[string]Checker, opts *HTTPOptions) map[string]string {
+	l := opts.readyLimiter
+	if l == nil {
+		return runCheckers(ctx, checkers, opts)
+	}
+	cached, ok, wait := l.take()
+	if wait != nil {
+		select {
+		case <-wait:
+			return l.results()
+		case <-ctx.Done():
+			out := make(map[string]string, len(checkers))
+			for name := range checkers {
+				out[name] = "error: " + ctx.Err().Error()
+			}
+			return out
+		}
+	}
+	if !ok {
+		return cached
+	}
+	results := runCheckers(ctx, checkers, opts)
+	l.store(results)
+	return results
+}
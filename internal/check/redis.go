@@ -0,0 +1,42 @@
+package check
+
+import (
+	"context"
+	"fmt"
+)
+
+// RedisPinger sends a Redis PING and returns the reply. It keeps the checker
+// independent of any Redis client; with go-redis, adapt a client as
+// RedisPingFunc(func(ctx context.Context) (string, error) {
+// return rdb.Ping(ctx).Result() }).
+type RedisPinger interface {
+	Ping(ctx context.Context) (string, error)
+}
+
+// RedisPingFunc adapts a function to RedisPinger.
+type RedisPingFunc func(ctx context.Context) (string, error)
+
+// Ping calls f(ctx).
+func (f RedisPingFunc) Ping(ctx context.Context) (string, error) { return f(ctx) }
+
+type redisChecker struct {
+	pinger RedisPinger
+}
+
+// NewRedisChecker returns a Checker that pings Redis through p within the
+// checker context's deadline. An error, or a reply other than "PONG", fails
+// the check.
+func NewRedisChecker(p RedisPinger) Checker {
+	return &redisChecker{pinger: p}
+}
+
+func (r *redisChecker) Check(ctx context.Context) error {
+	reply, err := r.pinger.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("redis ping: %w", err)
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("redis ping: unexpected reply %q", reply)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func TestRedisChecker(t *testing.T) {
+	errConn := errors.New("connection refused")
+	for name, tc := range map[string]struct {
+		reply   string
+		err     error
+		wantErr string
+	}{
+		"pong":       {reply: "PONG"},
+		"error":      {err: errConn, wantErr: "connection refused"},
+		"wrong ping": {reply: "LOADING", wantErr: `"LOADING"`},
+	} {
+		c := check.NewRedisChecker(check.RedisPingFunc(func(context.Context) (string, error) {
+			return tc.reply, tc.err
+		}))
+		err := c.Check(context.Background())
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: got %v, want error containing %s", name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestRedisCheckerUsesContextDeadline(t *testing.T) {
+	c := check.NewRedisChecker(check.RedisPingFunc(func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.Check(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,147 @@
+package check
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrUnknownChecker is returned when a checker name is not registered.
+var ErrUnknownChecker = errors.New("unknown checker")
+
+// Result is the outcome of the most recent run of a single checker. Warning
+// holds the message of a DetailedChecker warning, which has a nil Err.
+type Result struct {
+	Err     error
+	Warning string
+	Time    time.Time
+}
+
+// Registry holds the named checkers run on /ready together with their latest
+// results. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	checkers map[string]Checker
+	results  map[string]Result
+	disabled map[string]bool
+}
+
+// NewRegistry returns a Registry seeded with checkers. The map is copied.
+func NewRegistry(checkers map[string]Checker) *Registry {
+	r := &Registry{
+		checkers: make(map[string]Checker, len(checkers)),
+		results:  make(map[string]Result, len(checkers)),
+		disabled: make(map[string]bool),
+	}
+	for name, c := range checkers {
+		r.checkers[name] = c
+	}
+	return r
+}
+
+// Add registers c under name, replacing any checker with that name.
+func (r *Registry) Add(name string, c Checker) {
+	r.mu.Lock()
+	r.checkers[name] = c
+	delete(r.results, name)
+	delete(r.disabled, name)
+	r.mu.Unlock()
+}
+
+// Remove unregisters the named checker and forgets its result.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	delete(r.checkers, name)
+	delete(r.results, name)
+	delete(r.disabled, name)
+	r.mu.Unlock()
+}
+
+// SetDisabled mutes or unmutes the named checker: a disabled checker stays
+// registered but is not run. Unknown names are ignored. Replacing or removing
+// a checker re-enables its name.
+func (r *Registry) SetDisabled(name string, disabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.checkers[name]; !ok {
+		return
+	}
+	if disabled {
+		r.disabled[name] = true
+	} else {
+		delete(r.disabled, name)
+	}
+}
+
+// Disabled reports whether the named checker is disabled.
+func (r *Registry) Disabled(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.disabled[name]
+}
+
+// Len returns the number of registered checkers.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.checkers)
+}
+
+// Snapshot returns a copy of the registered checkers.
+func (r *Registry) Snapshot() map[string]Checker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]Checker, len(r.checkers))
+	for name, c := range r.checkers {
+		out[name] = c
+	}
+	return out
+}
+
+// Record stores the result of running the named checker at ts and returns
+// the result it replaced, if any. Results for checkers removed while they ran
+// are dropped, which ok reports as false.
+func (r *Registry) Record(name string, err error, ts time.Time) (prev Result, hadPrev, ok bool) {
+	return r.record(name, Result{Err: err, Time: ts})
+}
+
+// RecordWarning is Record for a passing run that reported warning.
+func (r *Registry) RecordWarning(name, warning string, ts time.Time) (prev Result, hadPrev, ok bool) {
+	return r.record(name, Result{Warning: warning, Time: ts})
+}
+
+func (r *Registry) record(name string, res Result) (prev Result, hadPrev, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.checkers[name]; !ok {
+		return Result{}, false, false
+	}
+	prev, hadPrev = r.results[name]
+	r.results[name] = res
+	return prev, hadPrev, true
+}
+
+// Result returns the latest result for name. The bool is false if no checker
+// with that name is registered; a registered checker that has not run yet
+// returns a zero Result and true.
+func (r *Registry) Result(name string) (Result, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, ok := r.checkers[name]; !ok {
+		return Result{}, false
+	}
+	return r.results[name], true
+}
+
+// Results returns the latest result of every checker that has run at least once.
+func (r *Registry) Results() map[string]Result {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]Result, len(r.results))
+	for name, res := range r.results {
+		if _, ok := r.checkers[name]; ok {
+			out[name] = res
+		}
+	}
+	return out
+}
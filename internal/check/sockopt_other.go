@@ -0,0 +1,12 @@
+//go:build !unix
+
+package check
+
+import "syscall"
+
+// setReuseAddr returns a no-op Control func on non-Unix platforms, where
+// SO_REUSEADDR lets another process steal a bound port instead of skipping
+// TIME_WAIT.
+func setReuseAddr(bool) func(_, _ string, _ syscall.RawConn) error {
+	return func(_, _ string, _ syscall.RawConn) error { return nil }
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package check
+
+import "syscall"
+
+// setReuseAddr returns a net.ListenConfig Control func that sets SO_REUSEADDR
+// on the raw socket when enabled and clears it otherwise. The Go runtime sets
+// it on every listener by default, so a restarted process can bind a port
+// whose previous connections are still in TIME_WAIT.
+func setReuseAddr(enabled bool) func(_, _ string, c syscall.RawConn) error {
+	value := 0
+	if enabled {
+		value = 1
+	}
+	return func(_, _ string, c syscall.RawConn) error {
+		var opErr error
+		err := c.Control(func(fd uintptr) {
+			opErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, value)
+		})
+		if err != nil {
+			return err
+		}
+		return opErr
+	}
+}
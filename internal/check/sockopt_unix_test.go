@@ -0,0 +1,46 @@
+//go:build unix
+
+package check_test
+
+import (
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+func reuseAddr(t *testing.T, ln net.Listener) int {
+	t.Helper()
+	rc, err := ln.(*net.TCPListener).SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		value int
+		opErr error
+	)
+	if err := rc.Control(func(fd uintptr) {
+		value, opErr = syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if opErr != nil {
+		t.Fatal(opErr)
+	}
+	return value
+}
+
+func TestReuseAddr(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		ln, err := check.Listen("127.0.0.1:0", check.ListenOptions{ReuseAddr: enabled})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := reuseAddr(t, ln) != 0
+		_ = ln.Close()
+		if got != enabled {
+			t.Errorf("ReuseAddr %v: SO_REUSEADDR set = %v", enabled, got)
+		}
+	}
+}
@@ -6,3 +6,25 @@ type StateReader interface {
 	ShuttingDown() bool
 	Started() bool
 }
+
+// LivenessReader is optionally implemented by a StateReader whose liveness
+// can fail independently of shutdown, e.g. when a supervised worker exits.
+type LivenessReader interface {
+	Live() bool
+}
+
+// isLive reports whether state considers the process live. States that do
+// not implement LivenessReader are always live.
+func isLive(state StateReader) bool {
+	if lr, ok := state.(LivenessReader); ok {
+		return lr.Live()
+	}
+	return true
+}
+
+// StartupProgressReader is optionally implemented by a StateReader that
+// tracks warmup progress. /startup reports it in StartupProgressHeader while
+// total is positive.
+type StartupProgressReader interface {
+	StartupProgress() (done, total int)
+}
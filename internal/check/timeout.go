@@ -0,0 +1,39 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type hardTimeoutChecker struct {
+	checker Checker
+	d       time.Duration
+}
+
+// WithHardTimeout returns a Checker that gives up on c after d even if c
+// ignores context cancellation, returning an error that matches
+// context.DeadlineExceeded. c still receives a context with deadline d.
+//
+// A checker that never returns leaks the goroutine running it: the wrapper
+// abandons the call but cannot stop it. Use it only for checkers you cannot
+// make context-aware, such as third-party clients.
+func WithHardTimeout(c Checker, d time.Duration) Checker {
+	return &hardTimeoutChecker{checker: c, d: d}
+}
+
+func (h *hardTimeoutChecker) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, h.d)
+	defer cancel()
+	done := make(chan error, 1) // buffered so an abandoned call can still finish
+	go func() { done <- h.checker.Check(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("checker exceeded hard timeout of %v: %w", h.d, ctx.Err())
+		}
+		return ctx.Err()
+	}
+}
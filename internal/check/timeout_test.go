@@ -0,0 +1,40 @@
+package check_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+// stuckChecker blocks until release is closed, ignoring its context.
+type stuckChecker struct{ release chan struct{} }
+
+func (s stuckChecker) Check(_ context.Context) error {
+	<-s.release
+	return nil
+}
+
+func TestWithHardTimeoutAbandonsBlockedChecker(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	c := check.WithHardTimeout(stuckChecker{release}, 50*time.Millisecond)
+
+	start := time.Now()
+	err := c.Check(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Check: got %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Check returned after %v, want about 50ms", elapsed)
+	}
+}
+
+func TestWithHardTimeoutPassesThroughResult(t *testing.T) {
+	c := check.WithHardTimeout(errChecker{"down"}, time.Second)
+	if err := c.Check(context.Background()); err == nil || err.Error() != "down" {
+		t.Errorf("Check: got %v, want down", err)
+	}
+}
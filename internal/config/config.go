@@ -1,15 +1,36 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"google.golang.org/grpc"
 
 	"github.com/kroderdev/pod-lifecycle-go/internal/check"
 )
 
+// Sentinel errors wrapped by ApplyOptions, for use with errors.Is.
+var (
+	// ErrInvalidPort reports a port outside [1, 65535].
+	ErrInvalidPort = errors.New("invalid port")
+	// ErrConflictingOptions reports options that cannot be combined.
+	ErrConflictingOptions = errors.New("conflicting options")
+	// ErrInvalidOption reports any other out-of-range or missing option value.
+	ErrInvalidOption = errors.New("invalid option")
+)
+
 // CheckMechanism is the probe mechanism used for readiness, liveness, and startup.
 type CheckMechanism int
 
@@ -20,27 +41,135 @@ const (
 	CheckGRPC
 )
 
+// CheckerDeadlineStrategy selects how a /ready checker's deadline relates to
+// the request that runs it.
+type CheckerDeadlineStrategy int
+
+const (
+	// CheckerDeadlineMin bounds each check by the earlier of the request
+	// deadline and WithCheckerTimeout, and cancels it when the client goes
+	// away.
+	CheckerDeadlineMin CheckerDeadlineStrategy = iota
+	// CheckerDeadlineFixed gives each check the full WithCheckerTimeout from
+	// the moment it starts, whatever the request deadline.
+	CheckerDeadlineFixed
+)
+
 // Config holds PodManager configuration.
 type Config struct {
-	CheckMechanism     CheckMechanism
-	HTTPPort           int
-	GRPCPort           int
-	ShutdownTimeout    time.Duration
-	CheckerTimeout     time.Duration
-	Checkers           map[string]check.Checker
-	ErrorHandler       func(error)
-	ExistingGRPCServer *grpc.Server
-	ExistingHTTPMux    *http.ServeMux
+	CheckMechanism                 CheckMechanism
+	HTTPPort                       int
+	GRPCPort                       int
+	ShutdownTimeout                time.Duration
+	GRPCShutdownTimeout            time.Duration
+	CheckerTimeout                 time.Duration
+	Checkers                       map[string]check.Checker
+	ErrorHandler                   func(error)
+	ExistingGRPCServer             *grpc.Server
+	ExistingHTTPMux                *http.ServeMux
+	DrainProgressInterval          time.Duration
+	OnDrainProgress                func(elapsed time.Duration)
+	UniformJSONBodies              bool
+	Pprof                          bool
+	BindAttempts                   int
+	BindBackoff                    time.Duration
+	ReuseAddr                      bool
+	ReadHeaderTimeout              time.Duration
+	ReadyResponseWriter            func(w http.ResponseWriter, ok bool, results map[string]string)
+	HTTPListener                   net.Listener
+	GRPCListener                   net.Listener
+	MinUptime                      time.Duration
+	VersionHeaderName              string
+	VersionHeaderValue             string
+	RequireCheckers                bool
+	EarlySignalHandling            bool
+	ManageGRPCServer               bool
+	ReadyPort                      int
+	LivePort                       int
+	InternalProbePort              int
+	InternalProbeVerbose           bool
+	GRPCReflection                 bool
+	GRPCChannelz                   bool
+	MaxCheckerErrorLen             int
+	ReadyRequiresStarted           bool
+	PingPath                       string
+	UnhealthyStatusCode            int
+	ConfirmNotReady                int
+	GRPCMaxConcurrentStreams       uint32
+	GRPCMaxRecvMsgSize             int
+	ReadinessGates                 []func() bool
+	GRPCStartupShutdownGrace       time.Duration
+	Clock                          check.Clock
+	ReadinessFailureTolerance      float64
+	StatusPath                     string
+	MetricsPath                    string
+	SignalActions                  map[os.Signal]SignalAction
+	ShutdownMetrics                ShutdownMetricsRecorder
+	TransitionAudit                func(TransitionEvent)
+	ProbePathPrefix                string
+	HealthJSONFormat               bool
+	LivenessIgnoresShutdown        bool
+	CheckerFailureHandler          func(name string, err error)
+	CheckerRecoveryHandler         func(name string)
+	LoadGate                       func() (load, limit int)
+	ListenConfig                   *net.ListenConfig
+	BindAddress                    string
+	RootHandler                    bool
+	ReadinessFailureHandler        func(error)
+	ProbeAccessLog                 *slog.Logger
+	FailureBody                    func(endpoint string) string
+	ReadyRequiresAppListening      bool
+	StartupCheckers                []string
+	ReadyBodyOnFailureOnly         bool
+	StartupRequiresSetStarted      bool
+	HTTPMiddleware                 []func(http.Handler) http.Handler
+	ReadinessFile                  string
+	ResultMarshaler                func(results map[string]string) ([]byte, string, error)
+	SelfTerminateOnLivenessFailure bool
+	RunCheckersDuringShutdown      bool
+	ProbeFailureLog                *slog.Logger
+	ProbeFailureLogEvery           int
+	DetachedCheckerContext         bool
+	FailOnWarn                     bool
+	ReadinessGateUpdater           func(ready bool) error
+	ReadinessDecider               func(results map[string]string) bool
+	DeferredClosers                []io.Closer
+	ConfigLog                      *slog.Logger
+	RecoveryHoldDown               time.Duration
+	LivenessDrainDelay             time.Duration
+	ProbeCompression               bool
+	ForceCloseOnTimeout            bool
+	ReservedCheckerNameLog         *slog.Logger
+	HTTP2Cleartext                 bool
+	ReadyRateLimit                 int
+	CheckerDependencies            map[string][]string
+	CheckerDeadline                CheckerDeadlineStrategy
+	ReadinessWebhook               string
+	NoProbe                        bool
+	VerboseFieldNames              map[string]string
+	CheckerSelfTestLog             *slog.Logger
+
+	// Track which options were set explicitly, to detect conflicts.
+	mechanismSet     bool
+	httpPortSet      bool
+	grpcPortSet      bool
+	versionHeaderSet bool
 }
 
 func defaultConfig() Config {
 	return Config{
-		CheckMechanism:  CheckHTTP,
-		HTTPPort:        8080,
-		GRPCPort:        50051,
-		ShutdownTimeout: 5 * time.Second,
-		CheckerTimeout:  2 * time.Second,
-		Checkers:        make(map[string]check.Checker),
+		CheckMechanism:       CheckHTTP,
+		HTTPPort:             8080,
+		GRPCPort:             50051,
+		ShutdownTimeout:      5 * time.Second,
+		CheckerTimeout:       2 * time.Second,
+		Checkers:             make(map[string]check.Checker),
+		ReuseAddr:            true,
+		MaxCheckerErrorLen:   256,
+		ReadyRequiresStarted: true,
+		UnhealthyStatusCode:  http.StatusServiceUnavailable,
+		Clock:                check.RealClock{},
+		SignalActions:        defaultSignalActions(),
 	}
 }
 
@@ -51,6 +180,7 @@ type Option func(*Config)
 func WithCheckMechanism(m CheckMechanism) Option {
 	return func(c *Config) {
 		c.CheckMechanism = m
+		c.mechanismSet = true
 	}
 }
 
@@ -58,16 +188,56 @@ func WithCheckMechanism(m CheckMechanism) Option {
 func WithHTTPPort(port int) Option {
 	return func(c *Config) {
 		c.HTTPPort = port
+		c.httpPortSet = true
 	}
 }
 
+// WithReadyPort serves /ready on its own HTTP listener at port instead of the
+// shared HTTPPort, e.g. to satisfy network policies that separate probes.
+func WithReadyPort(port int) Option {
+	return func(c *Config) { c.ReadyPort = port }
+}
+
+// WithInternalProbe adds a probe listener at port for in-cluster callers.
+// The main listeners then answer with status codes only, while the internal
+// one serves every endpoint, including status, metrics, and pprof, with
+// checker bodies and detail headers when verbose is true.
+func WithInternalProbe(port int, verbose bool) Option {
+	return func(c *Config) {
+		c.InternalProbePort = port
+		c.InternalProbeVerbose = verbose
+	}
+}
+
+// WithLivePort serves /live on its own HTTP listener at port instead of the
+// shared HTTPPort.
+func WithLivePort(port int) Option {
+	return func(c *Config) { c.LivePort = port }
+}
+
 // WithGRPCPort sets the port for gRPC health probes.
 func WithGRPCPort(port int) Option {
 	return func(c *Config) {
 		c.GRPCPort = port
+		c.grpcPortSet = true
 	}
 }
 
+// WithHTTPListener makes the standalone HTTP probe serve on ln instead of
+// binding HTTPPort, e.g. for systemd socket activation. It cannot be combined
+// with WithHTTPPort, WithReadyPort, or WithLivePort; the probe closes ln on
+// shutdown.
+func WithHTTPListener(ln net.Listener) Option {
+	return func(c *Config) { c.HTTPListener = ln }
+}
+
+// WithGRPCListener makes the standalone gRPC probe serve on ln instead of
+// binding GRPCPort; the probe closes ln on shutdown. It requires the
+// CheckGRPC mechanism and cannot be combined with an existing server or mux.
+func WithGRPCListener(ln net.Listener) Option {
+	return func(c *Config) { c.GRPCListener = ln }
+}
+
 // WithShutdownTimeout sets the maximum time to wait for probe servers to drain.
 func WithShutdownTimeout(d time.Duration) Option {
 	return func(c *Config) {
@@ -75,6 +245,53 @@ func WithShutdownTimeout(d time.Duration) Option {
 	}
 }
 
+// WithGRPCShutdownTimeout sets the maximum time to wait for the standalone gRPC
+// probe to drain before it is force-stopped. When unset (zero), ShutdownTimeout
+// applies. Useful when long-lived Watch streams need more time than HTTP drains.
+func WithGRPCShutdownTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.GRPCShutdownTimeout = d
+	}
+}
+
+// WithDrainProgress calls fn every interval with the elapsed drain time while
+// the standalone gRPC probe waits for in-flight RPCs to finish during shutdown.
+// It does not change when the server is force-stopped.
+func WithDrainProgress(interval time.Duration, fn func(elapsed time.Duration)) Option {
+	return func(c *Config) {
+		c.DrainProgressInterval = interval
+		c.OnDrainProgress = fn
+	}
+}
+
+// WithBindRetry makes the standalone HTTP and gRPC probes try binding their
+// port up to attempts times while it is in use (EADDRINUSE), sleeping backoff
+// between attempts, before Start gives up and returns the last error. This
+// rides out a previous process still holding the port during fast restarts.
+// Other listen errors fail at once. Default: a single attempt.
+func WithBindRetry(attempts int, backoff time.Duration) Option {
+	return func(c *Config) {
+		c.BindAttempts = attempts
+		c.BindBackoff = backoff
+	}
+}
+
+// WithReuseAddr controls whether standalone probe listeners set SO_REUSEADDR,
+// which avoids bind failures from TIME_WAIT sockets on restart. Enabled by
+// default, as for any Go listener; false clears it. Ignored on non-Unix
+// platforms and when WithListenConfig is set.
+func WithReuseAddr(enabled bool) Option {
+	return func(c *Config) { c.ReuseAddr = enabled }
+}
+
+// WithReadHeaderTimeout sets how long the standalone HTTP probe server waits
+// for request headers before closing the connection, guarding against
+// slow-header (slowloris) clients. Defaults to the 2s read timeout; a longer
+// value raises the read timeout to match.
+func WithReadHeaderTimeout(d time.Duration) Option {
+	return func(c *Config) { c.ReadHeaderTimeout = d }
+}
+
 // WithCheckerTimeout sets the per-checker deadline for /ready dependency checks.
 func WithCheckerTimeout(d time.Duration) Option {
 	return func(c *Config) {
@@ -82,8 +299,16 @@ func WithCheckerTimeout(d time.Duration) Option {
 	}
 }
 
+// WithMaxCheckerErrorLen truncates checker error messages in /ready bodies to
+// n characters (default 256), so a checker returning e.g. a full SQL error
+// cannot bloat the response. Zero disables truncation.
+func WithMaxCheckerErrorLen(n int) Option {
+	return func(c *Config) { c.MaxCheckerErrorLen = n }
+}
+
 // WithChecker registers a named dependency checker run on every /ready request.
-// Registering the same name twice overwrites the previous checker.
+// Registering the same name twice overwrites the previous checker. A nil
+// checker makes construction fail instead of panicking on the first /ready.
 func WithChecker(name string, ch check.Checker) Option {
 	return func(c *Config) {
 		if c.Checkers == nil {
@@ -93,6 +318,126 @@ func WithChecker(name string, ch check.Checker) Option {
 	}
 }
 
+// WithStartupCheckers makes startup (HTTP /startup and gRPC startup) also
+// wait until each named checker has passed once after the probe starts. The
+// checkers are polled in the background until then; once satisfied, startup
+// stays complete. With the default WithReadyRequiresStarted, readiness waits
+// too. Every name must be registered with WithChecker.
+func WithStartupCheckers(names ...string) Option {
+	return func(c *Config) { c.StartupCheckers = append(c.StartupCheckers, names...) }
+}
+
+// WithCheckerDependency makes the named checker depend on prerequisite:
+// when prerequisite fails, /ready skips name and reports it as
+// "skipped: <prerequisite> failed", e.g. not checking migrations while the
+// database is down. A skipped checker counts as failing: the skip is its
+// recorded result, as seen by CheckerStatus and the failure hooks. Both names
+// must be registered with WithChecker, and dependencies must not form a
+// cycle.
+func WithCheckerDependency(name, prerequisite string) Option {
+	return func(c *Config) {
+		if c.CheckerDependencies == nil {
+			c.CheckerDependencies = make(map[string][]string)
+		}
+		c.CheckerDependencies[name] = append(c.CheckerDependencies[name], prerequisite)
+	}
+}
+
+// checkDependencies rejects WithCheckerDependency names that are not
+// registered and dependency cycles.
+func checkDependencies(cfg Config) error {
+	for _, name := range slices.Sorted(maps.Keys(cfg.CheckerDependencies)) {
+		for _, n := range append([]string{name}, cfg.CheckerDependencies[name]...) {
+			if _, ok := cfg.Checkers[n]; !ok {
+				return fmt.Errorf("%w: checker dependency %q is not registered with WithChecker", ErrInvalidOption, n)
+			}
+		}
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	marks := make(map[string]int)
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			i := slices.Index(path, name)
+			return fmt.Errorf("%w: checker dependency cycle %s", ErrInvalidOption, strings.Join(append(path[i:], name), " -> "))
+		case done:
+			return nil
+		}
+		marks[name] = visiting
+		path = append(path, name)
+		for _, pre := range cfg.CheckerDependencies[name] {
+			if err := visit(pre); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = done
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.CheckerDependencies)) {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateCheckerName returns an ErrInvalidOption error when name is empty or
+// contains whitespace, which would make it unreadable in /ready bodies.
+func ValidateCheckerName(name string) error {
+	if name == "" || strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("%w: checker name %q must be non-empty and contain no whitespace", ErrInvalidOption, name)
+	}
+	return nil
+}
+
+// ValidateChecker returns an ErrInvalidOption error when name is not a valid
+// checker name or c is nil, either as a nil interface or a nil check.Func,
+// which would panic on the first /ready.
+func ValidateChecker(name string, c check.Checker) error {
+	if err := ValidateCheckerName(name); err != nil {
+		return err
+	}
+	if f, ok := c.(check.Func); c == nil || (ok && f == nil) {
+		return fmt.Errorf("%w: checker %q is nil", ErrInvalidOption, name)
+	}
+	return nil
+}
+
+// WithReadyRequiresStarted controls whether readiness also requires startup to
+// have completed, so the probe never reports ready before it reports started.
+// Enabled by default.
+func WithReadyRequiresStarted(enabled bool) Option {
+	return func(c *Config) { c.ReadyRequiresStarted = enabled }
+}
+
+// WithReadyRequiresAppListening makes readiness also wait for
+// PodManager.SetAppListening, so the probe cannot report ready before the
+// application's own server accepts connections. Off by default.
+func WithReadyRequiresAppListening(enabled bool) Option {
+	return func(c *Config) { c.ReadyRequiresAppListening = enabled }
+}
+
+// WithReadinessGate adds fn as an extra readiness condition, e.g. "is leader"
+// from a leader-election library: readiness fails while fn returns false.
+// HTTP probes call fn on every /ready request; gRPC probes evaluate it when
+// the pod state changes. It may be given more than once; all gates must pass.
+func WithReadinessGate(fn func() bool) Option {
+	return func(c *Config) { c.ReadinessGates = append(c.ReadinessGates, fn) }
+}
+
+// WithMinUptime keeps readiness failing until the probe has been up for at
+// least d, even if SetReady was called earlier. This avoids pods that flap
+// in and out of rotation right after starting. Default 0 (no minimum).
+func WithMinUptime(d time.Duration) Option {
+	return func(c *Config) { c.MinUptime = d }
+}
+
 // WithErrorHandler sets a callback for non-fatal server errors (e.g. unexpected Serve errors).
 func WithErrorHandler(h func(error)) Option {
 	return func(c *Config) {
@@ -100,14 +445,619 @@ func WithErrorHandler(h func(error)) Option {
 	}
 }
 
+// WithUniformJSONBodies makes HTTP probe responses that carry no checker
+// results (/live, /startup, and /ready without checkers) include a small JSON
+// body such as {"status":"ok"}. Off by default: those responses have no body.
+func WithUniformJSONBodies(enabled bool) Option {
+	return func(c *Config) { c.UniformJSONBodies = enabled }
+}
+
+// WithFailureBody makes failing /live and /startup HTTP responses carry body,
+// for dashboards that require one. A valid JSON body is sent as
+// application/json, anything else as text/plain. It overrides the
+// WithUniformJSONBodies body for those failures.
+func WithFailureBody(body string) Option {
+	return func(c *Config) { c.FailureBody = func(string) string { return body } }
+}
+
+// WithFailureBodyFunc is like WithFailureBody but calls fn with the failing
+// endpoint ("/live" or "/startup") to build the body.
+func WithFailureBodyFunc(fn func(endpoint string) string) Option {
+	return func(c *Config) { c.FailureBody = fn }
+}
+
+// WithUnhealthyStatusCode sets the status code failing HTTP probes return
+// instead of 503, for load balancers that treat 503 specially. code must be
+// a 4xx or 5xx status.
+func WithUnhealthyStatusCode(code int) Option {
+	return func(c *Config) { c.UnhealthyStatusCode = code }
+}
+
+// WithConfirmNotReady makes shutdown wait, before stopping the probe server,
+// until it has served n not-ready /ready responses, confirming the
+// orchestrator has observed the pod leaving rotation. The wait takes at most
+// half the shutdown timeout, leaving the rest for the probe server to drain.
+// HTTP probes only; zero disables.
+func WithConfirmNotReady(n int) Option {
+	return func(c *Config) { c.ConfirmNotReady = n }
+}
+
+// WithPingEndpoint registers an HTTP handler at path that returns 200 while
+// the probe server is up and 503 once shutdown begins. Unlike /live and
+// /ready it never runs checkers, so it is cheap to scrape frequently.
+func WithPingEndpoint(path string) Option {
+	return func(c *Config) { c.PingPath = path }
+}
+
+// WithPprof registers the net/http/pprof handlers under /debug/pprof/ on the
+// HTTP probe mux (standalone or WithExistingHTTPMux). Off by default.
+//
+// Profiles expose memory contents, command-line arguments, and symbol names, and
+// CPU profiles are expensive to collect. Only enable this when the probe port is
+// internal to the cluster and never exposed through an Ingress or public
+// Service. CPU profiles and traces run for their full duration: pprof extends
+// the probe server's 2s write deadline by the requested seconds.
+func WithPprof(enabled bool) Option {
+	return func(c *Config) { c.Pprof = enabled }
+}
+
+// WithReadyResponseWriter replaces the built-in /ready response with fn. fn is
+// called for every /ready request with the overall verdict and the checker
+// results (nil when checkers did not run) and must write the status code and
+// body itself.
+func WithReadyResponseWriter(fn func(w http.ResponseWriter, ok bool, results map[string]string)) Option {
+	return func(c *Config) { c.ReadyResponseWriter = fn }
+}
+
+// WithVersionHeader adds the header name: value to every HTTP probe response
+// (200 and 503 alike), e.g. WithVersionHeader("X-App-Version", version), so
+// operators can tell which build is behind an endpoint. name must be a valid
+// header field name and value must not contain CR, LF, or NUL. Off by default.
+func WithVersionHeader(name, value string) Option {
+	return func(c *Config) {
+		c.VersionHeaderName = name
+		c.VersionHeaderValue = value
+		c.versionHeaderSet = true
+	}
+}
+
+// validHeaderName reports whether name is a non-empty HTTP token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("!#$%&'*+-.^_`|~", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithRequireCheckers makes construction fail when no dependency checkers are
+// registered, for teams that treat a checker-less readiness probe as a
+// misconfiguration. Default false.
+func WithRequireCheckers(required bool) Option {
+	return func(c *Config) { c.RequireCheckers = required }
+}
+
+// WithEarlySignalHandling installs the SIGTERM/SIGINT handler when the
+// PodManager is constructed instead of in Start. A signal received before
+// Start begins shutdown immediately, and a later Start returns cleanly without
+// binding instead of the process dying with no drain.
+func WithEarlySignalHandling(enabled bool) Option {
+	return func(c *Config) { c.EarlySignalHandling = enabled }
+}
+
+// WithGRPCMaxConcurrentStreams caps concurrent streams per connection on the
+// standalone gRPC probe, e.g. to limit health Watch fan-out in a sidecar.
+func WithGRPCMaxConcurrentStreams(n uint32) Option {
+	return func(c *Config) { c.GRPCMaxConcurrentStreams = n }
+}
+
+// WithGRPCMaxRecvMsgSize caps the size in bytes of messages the standalone
+// gRPC probe accepts. Health requests are tiny, so a small limit is safe.
+func WithGRPCMaxRecvMsgSize(bytes int) Option {
+	return func(c *Config) { c.GRPCMaxRecvMsgSize = bytes }
+}
+
+// WithGRPCReflection registers the gRPC server reflection service on the
+// standalone gRPC probe, so grpcurl and similar tools can list its services.
+func WithGRPCReflection(enabled bool) Option {
+	return func(c *Config) { c.GRPCReflection = enabled }
+}
+
+// WithGRPCChannelz registers the channelz service on the standalone gRPC
+// probe, exposing its connections and streams (e.g. Watch streams stuck
+// during shutdown) to channelz clients. Off by default.
+func WithGRPCChannelz(enabled bool) Option {
+	return func(c *Config) { c.GRPCChannelz = enabled }
+}
+
+// WithGRPCStartupShutdownGrace keeps the gRPC "startup" service SERVING for d
+// after shutdown begins, while "ready" and "live" go NOT_SERVING at once. The
+// hold counts against the shutdown timeout. Zero (the default) flips all
+// services together.
+func WithGRPCStartupShutdownGrace(d time.Duration) Option {
+	return func(c *Config) { c.GRPCStartupShutdownGrace = d }
+}
+
+// WithClock replaces the clock behind time-based features (min uptime, the
+// gRPC startup hold and drain progress, the shutdown timeout and reports,
+// bind retry backoff, checker result timestamps, Cached TTLs). It exists for
+// deterministic tests; production code should keep the default real clock.
+func WithClock(clock check.Clock) Option {
+	return func(c *Config) { c.Clock = clock }
+}
+
+// WithReadinessFailureTolerance lets /ready stay 200 while up to fraction
+// (0 to 1) of the registered checkers fail, e.g. 0.25 tolerates one of four.
+// Checkers muted with DisableChecker are left out of the count. Failing
+// checkers still appear in the body. The default 0 fails on any checker
+// failure.
+func WithReadinessFailureTolerance(fraction float64) Option {
+	return func(c *Config) { c.ReadinessFailureTolerance = fraction }
+}
+
+// WithTextMetricsEndpoint registers a GET handler at path that serves a few
+// lifecycle gauges and counters (readiness, shutdown state, uptime, checker
+// results, shutdowns started) in the Prometheus text exposition format, for
+// scraping without a metrics library. Off by default. Requires HTTP probes.
+func WithTextMetricsEndpoint(path string) Option {
+	return func(c *Config) { c.MetricsPath = path }
+}
+
+// WithStatusEndpoint registers a GET handler at path that returns the
+// manager's state (ready, shuttingDown, started, serving, uptime, readyFor,
+// and the last checker results) as JSON. It is meant for debugging and should
+// only be reachable from inside the cluster. Requires HTTP probes.
+func WithStatusEndpoint(path string) Option {
+	return func(c *Config) { c.StatusPath = path }
+}
+
+// TransitionEvent describes a change of one of the manager's state flags.
+type TransitionEvent struct {
+	Field    string // "ready", "started", "shuttingDown", "draining", "paused", "appListening", or "appStarted"
+	Old, New bool
+	Time     time.Time
+}
+
+// WithTransitionAudit calls fn synchronously for every change of the ready,
+// started, shuttingDown, draining, paused, appListening, and appStarted
+// flags, e.g. to keep an audit trail. fn must return promptly. A panic in fn
+// during the started transition, which runs inside Start, is recovered and
+// reported to the WithErrorHandler callback.
+func WithTransitionAudit(fn func(TransitionEvent)) Option {
+	return func(c *Config) { c.TransitionAudit = fn }
+}
+
+// ShutdownMetricsRecorder receives shutdown metrics. Implementations must be
+// safe for concurrent use.
+type ShutdownMetricsRecorder interface {
+	// ObserveShutdownDuration records how long a shutdown took, e.g. into a
+	// histogram.
+	ObserveShutdownDuration(d time.Duration)
+	// IncForcedStop counts a shutdown in which the probe server hit the
+	// deadline with requests still in flight.
+	IncForcedStop()
+}
+
+// WithShutdownMetrics records shutdown duration and forced stops of the probe
+// server (or a managed gRPC server) on r.
+func WithShutdownMetrics(r ShutdownMetricsRecorder) Option {
+	return func(c *Config) { c.ShutdownMetrics = r }
+}
+
+// forcedStopHook returns the callback probes invoke on a forced stop, or nil.
+func forcedStopHook(cfg Config, hooks Hooks) func() {
+	switch {
+	case cfg.ShutdownMetrics == nil:
+		return hooks.OnForcedStop
+	case hooks.OnForcedStop == nil:
+		return cfg.ShutdownMetrics.IncForcedStop
+	}
+	return func() {
+		cfg.ShutdownMetrics.IncForcedStop()
+		hooks.OnForcedStop()
+	}
+}
+
+// WithProbePathPrefix mounts the probe endpoints under prefix on the mux given
+// to WithExistingHTTPMux, e.g. "/internal" serves "/internal/ready". prefix
+// must start with / and must not end with /.
+func WithProbePathPrefix(prefix string) Option {
+	return func(c *Config) { c.ProbePathPrefix = prefix }
+}
+
+// WithHealthJSONFormat makes /ready answer with an application/health+json
+// body (IETF health check response draft): a top-level status of pass, warn,
+// or fail, and a checks entry per checker with componentType, status, time,
+// and output. WithReadyResponseWriter takes precedence.
+func WithHealthJSONFormat(enabled bool) Option {
+	return func(c *Config) { c.HealthJSONFormat = enabled }
+}
+
+// WithVerboseFieldNames renames keys of the WithHealthJSONFormat /ready body
+// to fit a team's schema conventions, e.g. {"status": "state", "output":
+// "err"}. The keys that can be renamed are status (top-level and
+// per-checker), checks, componentType, time, and output; unset keys keep
+// their built-in names. The map is copied. It requires WithHealthJSONFormat.
+func WithVerboseFieldNames(names map[string]string) Option {
+	return func(c *Config) { c.VerboseFieldNames = maps.Clone(names) }
+}
+
+// checkVerboseFieldNames rejects unknown or empty WithVerboseFieldNames
+// entries and renames that would give two keys of one object the same name.
+func checkVerboseFieldNames(names map[string]string) error {
+	for _, from := range slices.Sorted(maps.Keys(names)) {
+		if !check.IsHealthJSONField(from) {
+			return fmt.Errorf("%w: WithVerboseFieldNames: unknown field %q", ErrInvalidOption, from)
+		}
+		if names[from] == "" {
+			return fmt.Errorf("%w: WithVerboseFieldNames: empty name for %q", ErrInvalidOption, from)
+		}
+	}
+	for _, object := range [][]string{{"status", "checks"}, {"componentType", "status", "time", "output"}} {
+		seen := make(map[string]string, len(object))
+		for _, field := range object {
+			name := field
+			if n, ok := names[field]; ok {
+				name = n
+			}
+			if prev, dup := seen[name]; dup {
+				return fmt.Errorf("%w: WithVerboseFieldNames: %q and %q would both be named %q", ErrInvalidOption, prev, field, name)
+			}
+			seen[name] = field
+		}
+	}
+	return nil
+}
+
+// WithLivenessIgnoresShutdown keeps /live and the gRPC "live" service healthy
+// during graceful shutdown, so only readiness signals the drain and the
+// kubelet does not restart a pod that is shutting down on purpose. Liveness
+// failures (e.g. from Supervise) still report. Off by default for
+// compatibility; enabling it is recommended.
+func WithLivenessIgnoresShutdown(enabled bool) Option {
+	return func(c *Config) { c.LivenessIgnoresShutdown = enabled }
+}
+
+// WithLivenessDrainDelay makes shutdown two-phase: readiness fails at once
+// while /live and the gRPC "live" service stay healthy for d, then liveness
+// fails too before the probe server stops. The delay counts against the
+// shutdown timeout. 0, the default, leaves liveness to
+// WithLivenessIgnoresShutdown.
+func WithLivenessDrainDelay(d time.Duration) Option {
+	return func(c *Config) { c.LivenessDrainDelay = d }
+}
+
+// WithCheckerFailureHandler calls fn once when a checker starts failing on
+// /ready, after passing or on its first run. Consecutive failures are not
+// reported again until the checker has recovered.
+func WithCheckerFailureHandler(fn func(name string, err error)) Option {
+	return func(c *Config) { c.CheckerFailureHandler = fn }
+}
+
+// WithCheckerRecoveryHandler calls fn once when a failing checker passes
+// again.
+func WithCheckerRecoveryHandler(fn func(name string)) Option {
+	return func(c *Config) { c.CheckerRecoveryHandler = fn }
+}
+
+// WithLoadGate makes /ready fail with reason "overloaded" while fn reports
+// load >= limit, e.g. in-flight requests against a concurrency cap, so the
+// load balancer backs off. The numbers are sent in the X-Load header as
+// "load/limit". fn is called on every /ready request and must be cheap.
+// Requires HTTP probes.
+func WithLoadGate(fn func() (load, limit int)) Option {
+	return func(c *Config) { c.LoadGate = fn }
+}
+
+// WithListenConfig makes the standalone HTTP and gRPC probes create their
+// listeners with lc, for full control over socket options (keepalive,
+// buffer sizes, TCP_FASTOPEN, ...). It replaces the default configuration,
+// so WithReuseAddr no longer applies.
+func WithListenConfig(lc *net.ListenConfig) Option {
+	return func(c *Config) { c.ListenConfig = lc }
+}
+
+// WithBindAddress makes the standalone HTTP and gRPC probes listen on host
+// only, e.g. "127.0.0.1" or the IPv6 literal "::1" (without brackets),
+// instead of all interfaces. The port options still choose the ports.
+func WithBindAddress(host string) Option {
+	return func(c *Config) { c.BindAddress = host }
+}
+
+// WithRootHandler makes the standalone HTTP probe answer / with a short
+// plain-text description of its endpoints and /favicon.ico with 204, so
+// opening the probe port in a browser does not fill access logs with 404s.
+// Off by default; it cannot be combined with WithExistingHTTPMux or gRPC
+// probes.
+func WithRootHandler(enabled bool) Option {
+	return func(c *Config) { c.RootHandler = enabled }
+}
+
+// WithReadinessFailureHandler calls fn after every /ready evaluation in which
+// at least one checker failed, with one error joining all failures (each
+// prefixed with the checker name), so a failed probe produces a single log
+// entry. Unlike WithCheckerFailureHandler it fires on every failing
+// evaluation, not only on transitions. It requires HTTP probes.
+func WithReadinessFailureHandler(fn func(error)) Option {
+	return func(c *Config) { c.ReadinessFailureHandler = fn }
+}
+
+// WithProbeAccessLog logs every HTTP probe request (/ready, /live, /startup,
+// and the optional ping and status endpoints) to log at debug level, with
+// method, path, status, duration, and remote address. Off by default, since
+// kubelet probes would otherwise flood the logs. It requires HTTP probes.
+func WithProbeAccessLog(log *slog.Logger) Option {
+	return func(c *Config) { c.ProbeAccessLog = log }
+}
+
+// WithProbeCompression gzip-encodes HTTP probe responses when the client
+// sends Accept-Encoding: gzip, for frequent scrapers of large verbose /ready
+// bodies. Bodies under 512 bytes are sent as is. Off by default. It requires
+// HTTP probes.
+func WithProbeCompression(enabled bool) Option {
+	return func(c *Config) { c.ProbeCompression = enabled }
+}
+
+// WithReadyRateLimit caps how often /ready runs its checkers at rps per
+// second, so a misbehaving scraper cannot overload the dependencies they
+// call. Requests over the rate get the last computed verdict and results;
+// those that arrive before the first run finishes wait for it and share its
+// results. Zero, the default, means no limit. It requires HTTP probes.
+func WithReadyRateLimit(rps int) Option {
+	return func(c *Config) { c.ReadyRateLimit = rps }
+}
+
+// WithReadyBodyOnFailureOnly makes /ready send checker results only when it
+// fails: a passing /ready answers 200 with an empty body, saving bandwidth
+// when many checkers are scraped often. It does not apply to
+// WithHealthJSONFormat or WithReadyResponseWriter.
+func WithReadyBodyOnFailureOnly(enabled bool) Option {
+	return func(c *Config) { c.ReadyBodyOnFailureOnly = enabled }
+}
+
+// WithStartupRequiresSetStarted keeps the startup probe (HTTP /startup and
+// gRPC "startup") failing until PodManager.SetStarted is called, instead of
+// passing as soon as the probe serves. With the default
+// WithReadyRequiresStarted, readiness waits for it too. Off by default.
+func WithStartupRequiresSetStarted(enabled bool) Option {
+	return func(c *Config) { c.StartupRequiresSetStarted = enabled }
+}
+
+// WithHTTP2Cleartext makes the standalone HTTP probe server accept HTTP/2
+// without TLS (h2c with prior knowledge), for gateways and meshes that speak
+// it on the probe port. HTTP/1.1, which kubelet probes use, keeps working.
+// Off by default. It uses net/http's own HTTP/2 support, so it adds no
+// dependency.
+func WithHTTP2Cleartext(enabled bool) Option {
+	return func(c *Config) { c.HTTP2Cleartext = enabled }
+}
+
+// WithHTTPMiddleware wraps the standalone HTTP probe's handler with mw, e.g.
+// for auth, logging, or tracing, without switching to WithExistingHTTPMux.
+// Repeated calls compose in order: the first middleware is the outermost.
+func WithHTTPMiddleware(mw func(http.Handler) http.Handler) Option {
+	return func(c *Config) { c.HTTPMiddleware = append(c.HTTPMiddleware, mw) }
+}
+
+// WithReadinessFile writes the manager's readiness to path, "ready" or
+// "not-ready", whenever it changes, for exec probes such as
+// `grep -qx ready <path>`. The file is removed when shutdown begins. It
+// reflects SetReady and the manager-level gates, not checker results, which
+// only HTTP /ready evaluates.
+func WithReadinessFile(path string) Option {
+	return func(c *Config) { c.ReadinessFile = path }
+}
+
+// WithResultMarshaler encodes the checker results of /ready with fn, which
+// returns the body and its Content-Type, in place of the default JSON map,
+// e.g. to follow house JSON conventions. Clients that prefer text/plain still
+// get name=value lines. If fn fails, /ready answers with the unhealthy status
+// and no results, and the error goes to the WithErrorHandler callback.
+func WithResultMarshaler(fn func(results map[string]string) ([]byte, string, error)) Option {
+	return func(c *Config) { c.ResultMarshaler = fn }
+}
+
+// WithSelfTerminateOnLivenessFailure makes a failed liveness watchdog (a
+// worker passed to PodManager.Supervise exiting before shutdown) also start a
+// graceful shutdown, instead of only failing liveness and waiting for the
+// kubelet to restart the container. Start and StartContext then return an
+// error matching ErrLivenessFailure, so the process can exit non-zero. Off by
+// default: the kubelet restart is usually enough, and self-termination skips
+// the liveness failureThreshold grace that absorbs brief stalls.
+func WithSelfTerminateOnLivenessFailure(enabled bool) Option {
+	return func(c *Config) { c.SelfTerminateOnLivenessFailure = enabled }
+}
+
+// WithRunCheckersDuringShutdown keeps running the checkers on /ready once
+// shutdown has begun, so dashboards still see per-checker results in the
+// body while the pod drains. The status stays failing. Off by default, which
+// answers /ready without running checkers during shutdown.
+func WithRunCheckersDuringShutdown(enabled bool) Option {
+	return func(c *Config) { c.RunCheckersDuringShutdown = enabled }
+}
+
+// WithForceCloseOnTimeout closes the HTTP probe servers once the shutdown
+// timeout expires, cutting off requests still in flight, keep-alive
+// connections included, like the gRPC Stop fallback. Off by default: shutdown
+// stops waiting at the deadline but leaves those requests to finish.
+func WithForceCloseOnTimeout(enabled bool) Option {
+	return func(c *Config) { c.ForceCloseOnTimeout = enabled }
+}
+
+// WithProbeFailureLogging logs a warning with the reason and failing checkers
+// for one in every sampleEvery failing /ready, /live, and /startup responses,
+// starting with the first. Probes are polled often, so sampling keeps a
+// persistent failure visible without flooding the logs. sampleEvery must be
+// at least 1; 1 logs every failure.
+func WithProbeFailureLogging(log *slog.Logger, sampleEvery int) Option {
+	return func(c *Config) {
+		c.ProbeFailureLog = log
+		c.ProbeFailureLogEvery = sampleEvery
+	}
+}
+
+// WithCheckerDeadlineStrategy chooses how /ready checker deadlines relate to
+// the request. The default, CheckerDeadlineMin, lets a short request
+// deadline (e.g. one set by middleware) cut checks off before
+// WithCheckerTimeout. CheckerDeadlineFixed always allows the full checker
+// timeout, at the cost that a slow check can outlive the response it was
+// run for; it detaches checkers from the request as WithDetachedCheckerContext
+// does. Requires HTTP probes.
+func WithCheckerDeadlineStrategy(s CheckerDeadlineStrategy) Option {
+	return func(c *Config) { c.CheckerDeadline = s }
+}
+
+// WithDetachedCheckerContext runs /ready checkers under a context that the
+// probe client disconnecting does not cancel, so an abandoned request cannot
+// record a spurious "context canceled" failure that flips checker state for
+// every other caller. WithCheckerTimeout still bounds each check. Off by
+// default: checkers inherit the request context. Requires HTTP probes.
+func WithDetachedCheckerContext(enabled bool) Option {
+	return func(c *Config) { c.DetachedCheckerContext = enabled }
+}
+
+// WithFailOnWarn counts warnings from DetailedChecker checkers as /ready
+// failures, subject to WithReadinessFailureTolerance. Off by default: a
+// warning shows in the /ready body but keeps the 200. Requires HTTP probes.
+func WithFailOnWarn(enabled bool) Option {
+	return func(c *Config) { c.FailOnWarn = enabled }
+}
+
+// WithReadinessGateUpdater calls fn with the pod's effective readiness once
+// the probe has started and again on every change, including the drop to
+// false at shutdown, e.g. to patch a custom pod condition used by a
+// Kubernetes readiness gate (spec.readinessGates) through client-go. fn runs
+// synchronously on the state change, so keep it short or hand off to a
+// goroutine. Errors go to the error handler and the update is retried up to
+// five times, after 1s and then twice as long each time, and again on the
+// next state change.
+func WithReadinessGateUpdater(fn func(ready bool) error) Option {
+	return func(c *Config) { c.ReadinessGateUpdater = fn }
+}
+
+// WithReadinessWebhook POSTs a JSON payload {"pod", "status", "time"} to url
+// once the probe has started and on every change of the pod's status:
+// "ready", "not-ready", or "shutting-down". The pod is the hostname, which
+// Kubernetes sets to the pod name. Deliveries run on a background worker, so
+// state changes never wait for the webhook; each request is bounded by a 5s
+// timeout, and failures, non-2xx responses, and events dropped because the
+// queue is full go to the error handler.
+func WithReadinessWebhook(url string) Option {
+	return func(c *Config) { c.ReadinessWebhook = url }
+}
+
+// checkWebhookURL rejects a WithReadinessWebhook URL that is not absolute
+// http or https.
+func checkWebhookURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: WithReadinessWebhook: %v", ErrInvalidOption, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: WithReadinessWebhook URL %q must be absolute http or https", ErrInvalidOption, raw)
+	}
+	return nil
+}
+
+// WithReadinessDecider makes fn decide the /ready verdict from the checker
+// results (name to "ok", "error: ...", "warn: ...", or "disabled"), in place
+// of the default all-must-pass rule, WithReadinessFailureTolerance, and
+// WithFailOnWarn, e.g. to treat a cache checker as advisory. The body still
+// reports every result. fn is only consulted when checkers ran; the manager
+// state (SetReady, shutdown) still gates readiness. Requires HTTP probes.
+func WithReadinessDecider(fn func(results map[string]string) bool) Option {
+	return func(c *Config) { c.ReadinessDecider = fn }
+}
+
+// WithDeferredClose closes c at the very end of shutdown, after the probe
+// server has stopped and the final lifecycle events have been emitted, e.g.
+// to flush a buffering log handler so shutdown logs reach their sink before
+// the process exits. Repeated calls register more closers, which run once
+// each in reverse order, like deferred calls. Close errors go to the error
+// handler.
+func WithDeferredClose(c io.Closer) Option {
+	return func(cfg *Config) { cfg.DeferredClosers = append(cfg.DeferredClosers, c) }
+}
+
+// WithLogConfigAtStartup logs the effective configuration (mechanism, ports,
+// timeouts, checker names) to log as one INFO line once the probe has bound,
+// for auditing what a pod actually runs with. The configuration holds no
+// secrets. Nil, the default, logs nothing.
+func WithLogConfigAtStartup(log *slog.Logger) Option {
+	return func(c *Config) { c.ConfigLog = log }
+}
+
+// WithReservedCheckerNameWarnings logs a warning to log for each checker,
+// registered by option or AddChecker, whose name collides with a key that
+// probe bodies use themselves ("status", "checks"), since it makes /ready
+// bodies ambiguous. The checker is still registered. Nil, the default, does
+// not check names.
+func WithReservedCheckerNameWarnings(log *slog.Logger) Option {
+	return func(c *Config) { c.ReservedCheckerNameLog = log }
+}
+
+// WithStartupCheckerSelfTest runs every registered checker once, in the
+// background, when the probe starts, and logs a warning to log for each that
+// fails or panics, so wiring mistakes such as an unconfigured client show up
+// at deploy time rather than on the first /ready. Failures are not fatal:
+// dependencies may legitimately be down at start. Each check is bounded by
+// WithCheckerTimeout and its result is not recorded. Nil, the default,
+// skips the self-test.
+func WithStartupCheckerSelfTest(log *slog.Logger) Option {
+	return func(c *Config) { c.CheckerSelfTestLog = log }
+}
+
+// WithRecoveryHoldDown keeps readiness failing for d after CancelDrain ends
+// a drain, so a pod returning to service does not flap straight back to
+// ready while load balancers are still converging. 0, the default, honors
+// the ready flag again at once.
+func WithRecoveryHoldDown(d time.Duration) Option {
+	return func(c *Config) { c.RecoveryHoldDown = d }
+}
+
 // WithExistingGRPCServer registers the gRPC health service on s instead of starting
 // a separate probe server. s must not yet be serving when this option is applied.
+// Combining it with WithHTTPPort, WithGRPCPort, or CheckHTTP is an error. It
+// replaces an earlier WithManagedGRPCServer, so the PodManager never stops s.
 func WithExistingGRPCServer(s *grpc.Server) Option {
-	return func(c *Config) { c.ExistingGRPCServer = s }
+	return func(c *Config) {
+		c.ExistingGRPCServer = s
+		c.ManageGRPCServer = false
+	}
+}
+
+// WithManagedGRPCServer is like WithExistingGRPCServer, but the PodManager also
+// owns s: on shutdown it calls GracefulStop, then Stop once the shutdown
+// timeout expires.
+func WithManagedGRPCServer(s *grpc.Server) Option {
+	return func(c *Config) {
+		c.ExistingGRPCServer = s
+		c.ManageGRPCServer = true
+	}
+}
+
+// WithNoProbe runs the manager without any probe endpoints, for applications
+// that serve health checks over their own transport: Start still handles
+// signals, runs hooks, and shuts down, and SetReady, ShutdownCh, and Events
+// work as usual, but nothing listens. It cannot be combined with the
+// existing-server options, a mechanism, a port, or options that only
+// configure a probe server (bind address, timeouts, pprof, gRPC server
+// settings, response formats). Checkers must be listed in
+// WithStartupCheckers, the only place they still run.
+func WithNoProbe() Option {
+	return func(c *Config) { c.NoProbe = true }
 }
 
 // WithExistingHTTPMux registers the /live, /ready, and /startup HTTP handlers
-// on m instead of starting a separate probe server.
+// on m instead of starting a separate probe server. Combining it with
+// WithHTTPPort, WithGRPCPort, or CheckGRPC is an error.
 func WithExistingHTTPMux(m *http.ServeMux) Option {
 	return func(c *Config) { c.ExistingHTTPMux = m }
 }
@@ -118,27 +1068,466 @@ func ApplyOptions(opts []Option) (Config, error) {
 	for _, o := range opts {
 		o(&cfg)
 	}
+	return validate(cfg)
+}
+
+// DefaultConfig returns the Config that ApplyOptions starts from, for
+// callers that fill in a Config themselves; see FromConfig.
+func DefaultConfig() Config {
+	return defaultConfig()
+}
+
+// FromConfig validates a Config built directly rather than through options,
+// typically starting from DefaultConfig. A port or mechanism that differs
+// from the default counts as set, as if by its option, for the conflict
+// checks. The Checkers map is copied.
+func FromConfig(cfg Config) (Config, error) {
+	def := defaultConfig()
+	cfg.mechanismSet = cfg.CheckMechanism != def.CheckMechanism
+	cfg.httpPortSet = cfg.HTTPPort != def.HTTPPort
+	cfg.grpcPortSet = cfg.GRPCPort != def.GRPCPort
+	cfg.Checkers = maps.Clone(cfg.Checkers)
+	cfg.CheckerDependencies = maps.Clone(cfg.CheckerDependencies)
+	if cfg.Checkers == nil {
+		cfg.Checkers = make(map[string]check.Checker)
+	}
+	if len(cfg.SignalActions) == 0 {
+		return Config{}, fmt.Errorf("%w: SignalActions must map at least one signal", ErrInvalidOption)
+	}
+	return validate(cfg)
+}
+
+// validate checks cfg once every option has been applied.
+func validate(cfg Config) (Config, error) {
 	if cfg.HTTPPort < 1 || cfg.HTTPPort > 65535 {
-		return Config{}, fmt.Errorf("invalid HTTPPort %d: must be in [1, 65535]", cfg.HTTPPort)
+		return Config{}, fmt.Errorf("%w: HTTPPort %d must be in [1, 65535]", ErrInvalidPort, cfg.HTTPPort)
 	}
 	if cfg.GRPCPort < 1 || cfg.GRPCPort > 65535 {
-		return Config{}, fmt.Errorf("invalid GRPCPort %d: must be in [1, 65535]", cfg.GRPCPort)
+		return Config{}, fmt.Errorf("%w: GRPCPort %d must be in [1, 65535]", ErrInvalidPort, cfg.GRPCPort)
+	}
+	if cfg.ReadyPort != 0 && (cfg.ReadyPort < 1 || cfg.ReadyPort > 65535) {
+		return Config{}, fmt.Errorf("%w: ReadyPort %d must be in [1, 65535]", ErrInvalidPort, cfg.ReadyPort)
+	}
+	if cfg.LivePort != 0 && (cfg.LivePort < 1 || cfg.LivePort > 65535) {
+		return Config{}, fmt.Errorf("%w: LivePort %d must be in [1, 65535]", ErrInvalidPort, cfg.LivePort)
+	}
+	if cfg.InternalProbePort != 0 && (cfg.InternalProbePort < 1 || cfg.InternalProbePort > 65535) {
+		return Config{}, fmt.Errorf("%w: InternalProbePort %d must be in [1, 65535]", ErrInvalidPort, cfg.InternalProbePort)
+	}
+	if cfg.MinUptime < 0 {
+		return Config{}, fmt.Errorf("%w: MinUptime %v must not be negative", ErrInvalidOption, cfg.MinUptime)
+	}
+	if cfg.ReadHeaderTimeout < 0 {
+		return Config{}, fmt.Errorf("%w: ReadHeaderTimeout %v must not be negative", ErrInvalidOption, cfg.ReadHeaderTimeout)
+	}
+	if (cfg.versionHeaderSet || cfg.VersionHeaderName != "") && !validHeaderName(cfg.VersionHeaderName) {
+		return Config{}, fmt.Errorf("%w: version header name %q is not a valid header field name", ErrInvalidOption, cfg.VersionHeaderName)
+	}
+	if strings.ContainsAny(cfg.VersionHeaderValue, "\r\n\x00") {
+		return Config{}, fmt.Errorf("%w: version header value %q must not contain CR, LF, or NUL", ErrInvalidOption, cfg.VersionHeaderValue)
+	}
+	if cfg.BindAttempts < 0 || cfg.BindBackoff < 0 {
+		return Config{}, fmt.Errorf("%w: bind retry (%d, %v) must not be negative", ErrInvalidOption, cfg.BindAttempts, cfg.BindBackoff)
+	}
+	for _, c := range cfg.DeferredClosers {
+		if c == nil {
+			return Config{}, fmt.Errorf("%w: WithDeferredClose closer must not be nil", ErrInvalidOption)
+		}
+	}
+	for _, mw := range cfg.HTTPMiddleware {
+		if mw == nil {
+			return Config{}, fmt.Errorf("%w: WithHTTPMiddleware middleware must not be nil", ErrInvalidOption)
+		}
+	}
+	if strings.ContainsAny(cfg.BindAddress, "[]/ ") || (strings.Contains(cfg.BindAddress, ":") && net.ParseIP(cfg.BindAddress) == nil) {
+		return Config{}, fmt.Errorf("%w: bind address %q must be a host or IP without port or brackets", ErrInvalidOption, cfg.BindAddress)
+	}
+	if cfg.ProbeFailureLog != nil && cfg.ProbeFailureLogEvery < 1 {
+		return Config{}, fmt.Errorf("%w: WithProbeFailureLogging sampleEvery %d must be at least 1", ErrInvalidOption, cfg.ProbeFailureLogEvery)
+	}
+	if cfg.MaxCheckerErrorLen < 0 {
+		return Config{}, fmt.Errorf("%w: MaxCheckerErrorLen %d must not be negative", ErrInvalidOption, cfg.MaxCheckerErrorLen)
+	}
+	if cfg.PingPath != "" && (!strings.HasPrefix(cfg.PingPath, "/") || cfg.PingPath == "/ready" || cfg.PingPath == "/live" || cfg.PingPath == "/startup") {
+		return Config{}, fmt.Errorf("%w: ping path %q must start with / and not be a probe path", ErrInvalidOption, cfg.PingPath)
+	}
+	if cfg.UnhealthyStatusCode < 400 || cfg.UnhealthyStatusCode > 599 {
+		return Config{}, fmt.Errorf("%w: UnhealthyStatusCode %d must be in [400, 599]", ErrInvalidOption, cfg.UnhealthyStatusCode)
+	}
+	if cfg.ConfirmNotReady < 0 {
+		return Config{}, fmt.Errorf("%w: ConfirmNotReady %d must not be negative", ErrInvalidOption, cfg.ConfirmNotReady)
+	}
+	if cfg.GRPCMaxRecvMsgSize < 0 {
+		return Config{}, fmt.Errorf("%w: GRPCMaxRecvMsgSize %d must not be negative", ErrInvalidOption, cfg.GRPCMaxRecvMsgSize)
+	}
+	for _, gate := range cfg.ReadinessGates {
+		if gate == nil {
+			return Config{}, fmt.Errorf("%w: WithReadinessGate requires a non-nil func", ErrInvalidOption)
+		}
+	}
+	if cfg.GRPCStartupShutdownGrace < 0 {
+		return Config{}, fmt.Errorf("%w: GRPCStartupShutdownGrace %v must not be negative", ErrInvalidOption, cfg.GRPCStartupShutdownGrace)
+	}
+	if cfg.Clock == nil {
+		return Config{}, fmt.Errorf("%w: Clock must not be nil", ErrInvalidOption)
+	}
+	if !(cfg.ReadinessFailureTolerance >= 0 && cfg.ReadinessFailureTolerance <= 1) {
+		return Config{}, fmt.Errorf("%w: ReadinessFailureTolerance %v must be between 0 and 1", ErrInvalidOption, cfg.ReadinessFailureTolerance)
+	}
+	if cfg.StatusPath != "" && (!strings.HasPrefix(cfg.StatusPath, "/") || cfg.StatusPath == "/ready" || cfg.StatusPath == "/live" || cfg.StatusPath == "/startup" || cfg.StatusPath == cfg.PingPath) {
+		return Config{}, fmt.Errorf("%w: status path %q must start with / and not be a probe or ping path", ErrInvalidOption, cfg.StatusPath)
+	}
+	if cfg.MetricsPath != "" && (!strings.HasPrefix(cfg.MetricsPath, "/") || cfg.MetricsPath == "/ready" || cfg.MetricsPath == "/live" || cfg.MetricsPath == "/startup" || cfg.MetricsPath == cfg.PingPath || cfg.MetricsPath == cfg.StatusPath) {
+		return Config{}, fmt.Errorf("%w: metrics path %q must start with / and not be a probe, ping, or status path", ErrInvalidOption, cfg.MetricsPath)
+	}
+	if err := validateSignalActions(cfg.SignalActions); err != nil {
+		return Config{}, err
+	}
+	if cfg.ProbePathPrefix != "" && (!strings.HasPrefix(cfg.ProbePathPrefix, "/") || strings.HasSuffix(cfg.ProbePathPrefix, "/")) {
+		return Config{}, fmt.Errorf("%w: probe path prefix %q must start with / and not end with /", ErrInvalidOption, cfg.ProbePathPrefix)
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Checkers)) {
+		if err := ValidateChecker(name, cfg.Checkers[name]); err != nil {
+			return Config{}, err
+		}
+	}
+	for _, name := range cfg.StartupCheckers {
+		if _, ok := cfg.Checkers[name]; !ok {
+			return Config{}, fmt.Errorf("%w: startup checker %q is not registered with WithChecker", ErrInvalidOption, name)
+		}
+	}
+	if cfg.RecoveryHoldDown < 0 {
+		return Config{}, fmt.Errorf("%w: RecoveryHoldDown %v must not be negative", ErrInvalidOption, cfg.RecoveryHoldDown)
+	}
+	if cfg.LivenessDrainDelay < 0 {
+		return Config{}, fmt.Errorf("%w: LivenessDrainDelay %v must not be negative", ErrInvalidOption, cfg.LivenessDrainDelay)
+	}
+	if cfg.ReadyRateLimit < 0 {
+		return Config{}, fmt.Errorf("%w: WithReadyRateLimit rps %d must not be negative", ErrInvalidOption, cfg.ReadyRateLimit)
+	}
+	if err := checkDependencies(cfg); err != nil {
+		return Config{}, err
+	}
+	if err := checkWebhookURL(cfg.ReadinessWebhook); err != nil {
+		return Config{}, err
+	}
+	if err := checkVerboseFieldNames(cfg.VerboseFieldNames); err != nil {
+		return Config{}, err
+	}
+	if err := checkConflicts(cfg); err != nil {
+		return Config{}, err
+	}
+	if cfg.RequireCheckers && len(cfg.Checkers) == 0 {
+		return Config{}, fmt.Errorf("%w: WithRequireCheckers requires at least one WithChecker", ErrInvalidOption)
 	}
 	return cfg, nil
 }
 
-// NewProbe returns a check.Server for the given config.
-func NewProbe(cfg Config) check.Server {
+// ProbeShutdownTimeout returns the drain budget for the configured probe.
+// GRPCShutdownTimeout takes precedence over ShutdownTimeout when it is set and
+// the library stops a gRPC server: the standalone probe or a managed server.
+func (c Config) ProbeShutdownTimeout() time.Duration {
+	standalone := c.CheckMechanism == CheckGRPC && c.ExistingGRPCServer == nil && c.ExistingHTTPMux == nil
+	if (standalone || c.ManageGRPCServer) && c.GRPCShutdownTimeout > 0 {
+		return c.GRPCShutdownTimeout
+	}
+	return c.ShutdownTimeout
+}
+
+// checkConflicts rejects options that would be silently ignored because an
+// existing server or mux replaces the standalone probe server, or
+// WithNoProbe removes it.
+func checkConflicts(cfg Config) error {
+	// Exactly one probe strategy may be selected; NewProbe would otherwise
+	// silently prefer the existing gRPC server, then the existing mux.
+	if cfg.ExistingGRPCServer != nil && cfg.ExistingHTTPMux != nil {
+		return fmt.Errorf("%w: WithExistingGRPCServer and WithExistingHTTPMux select different probe strategies; use one", ErrConflictingOptions)
+	}
+	if cfg.NoProbe {
+		if cfg.ExistingGRPCServer != nil || cfg.ExistingHTTPMux != nil {
+			return fmt.Errorf("%w: WithNoProbe cannot be combined with WithExistingGRPCServer or WithExistingHTTPMux", ErrConflictingOptions)
+		}
+		if cfg.mechanismSet || cfg.httpPortSet || cfg.grpcPortSet {
+			return fmt.Errorf("%w: WithCheckMechanism/WithHTTPPort/WithGRPCPort have no effect with WithNoProbe", ErrConflictingOptions)
+		}
+		// Without /ready, only WithStartupCheckers checkers ever run.
+		for _, name := range slices.Sorted(maps.Keys(cfg.Checkers)) {
+			if !slices.Contains(cfg.StartupCheckers, name) {
+				return fmt.Errorf("%w: checker %q never runs with WithNoProbe unless listed in WithStartupCheckers", ErrConflictingOptions, name)
+			}
+		}
+		for _, o := range []struct {
+			set  bool
+			name string
+		}{
+			{cfg.BindAddress != "", "WithBindAddress"},
+			{cfg.ReadHeaderTimeout != 0, "WithReadHeaderTimeout"},
+			{cfg.GRPCShutdownTimeout != 0, "WithGRPCShutdownTimeout"},
+			{cfg.BindAttempts != 0, "WithBindRetry"},
+			{cfg.ListenConfig != nil, "WithListenConfig"},
+			{cfg.Pprof, "WithPprof"},
+			{cfg.PingPath != "", "WithPingEndpoint"},
+			{cfg.UniformJSONBodies, "WithUniformJSONBodies"},
+			{cfg.ReadyResponseWriter != nil, "WithReadyResponseWriter"},
+			{cfg.versionHeaderSet || cfg.VersionHeaderName != "", "WithVersionHeader"},
+			{cfg.DrainProgressInterval != 0, "WithDrainProgress"},
+			{cfg.GRPCReflection, "WithGRPCReflection"},
+			{cfg.GRPCChannelz, "WithGRPCChannelz"},
+			{cfg.GRPCMaxConcurrentStreams != 0, "WithGRPCMaxConcurrentStreams"},
+			{cfg.GRPCMaxRecvMsgSize != 0, "WithGRPCMaxRecvMsgSize"},
+			{cfg.GRPCStartupShutdownGrace != 0, "WithGRPCStartupShutdownGrace"},
+		} {
+			if o.set {
+				return fmt.Errorf("%w: %s has no effect with WithNoProbe", ErrConflictingOptions, o.name)
+			}
+		}
+	}
+	if cfg.GRPCListener != nil && (cfg.CheckMechanism != CheckGRPC || cfg.ExistingGRPCServer != nil || cfg.ExistingHTTPMux != nil || cfg.NoProbe) {
+		return fmt.Errorf("%w: WithGRPCListener requires the standalone gRPC probe (CheckGRPC)", ErrConflictingOptions)
+	}
+	httpProbes := !cfg.NoProbe && (cfg.ExistingHTTPMux != nil || (cfg.CheckMechanism == CheckHTTP && cfg.ExistingGRPCServer == nil))
+	standaloneHTTP := !cfg.NoProbe && cfg.CheckMechanism == CheckHTTP && cfg.ExistingGRPCServer == nil && cfg.ExistingHTTPMux == nil
+	if cfg.ConfirmNotReady > 0 && !httpProbes {
+		return fmt.Errorf("%w: WithConfirmNotReady requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.MetricsPath != "" && !httpProbes {
+		return fmt.Errorf("%w: WithTextMetricsEndpoint requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.StatusPath != "" && !httpProbes {
+		return fmt.Errorf("%w: WithStatusEndpoint requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.ProbePathPrefix != "" && cfg.ExistingHTTPMux == nil {
+		return fmt.Errorf("%w: WithProbePathPrefix requires WithExistingHTTPMux", ErrConflictingOptions)
+	}
+	if cfg.LoadGate != nil && !httpProbes {
+		return fmt.Errorf("%w: WithLoadGate requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.ReadinessFailureHandler != nil && !httpProbes {
+		return fmt.Errorf("%w: WithReadinessFailureHandler requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.ReadyRateLimit > 0 && !httpProbes {
+		return fmt.Errorf("%w: WithReadyRateLimit requires HTTP probes", ErrConflictingOptions)
+	}
+	if len(cfg.VerboseFieldNames) > 0 && !cfg.HealthJSONFormat {
+		return fmt.Errorf("%w: WithVerboseFieldNames requires WithHealthJSONFormat", ErrConflictingOptions)
+	}
+	if cfg.ProbeCompression && !httpProbes {
+		return fmt.Errorf("%w: WithProbeCompression requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.ProbeAccessLog != nil && !httpProbes {
+		return fmt.Errorf("%w: WithProbeAccessLog requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.FailureBody != nil && !httpProbes {
+		return fmt.Errorf("%w: WithFailureBody requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.ReadyBodyOnFailureOnly && !httpProbes {
+		return fmt.Errorf("%w: WithReadyBodyOnFailureOnly requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.ResultMarshaler != nil && !httpProbes {
+		return fmt.Errorf("%w: WithResultMarshaler requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.RunCheckersDuringShutdown && !httpProbes {
+		return fmt.Errorf("%w: WithRunCheckersDuringShutdown requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.ForceCloseOnTimeout && !httpProbes {
+		return fmt.Errorf("%w: WithForceCloseOnTimeout requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.ProbeFailureLog != nil && !httpProbes {
+		return fmt.Errorf("%w: WithProbeFailureLogging requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.CheckerDeadline == CheckerDeadlineFixed && !httpProbes {
+		return fmt.Errorf("%w: WithCheckerDeadlineStrategy(CheckerDeadlineFixed) requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.DetachedCheckerContext && !httpProbes {
+		return fmt.Errorf("%w: WithDetachedCheckerContext requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.FailOnWarn && !httpProbes {
+		return fmt.Errorf("%w: WithFailOnWarn requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.ReadinessDecider != nil && !httpProbes {
+		return fmt.Errorf("%w: WithReadinessDecider requires HTTP probes", ErrConflictingOptions)
+	}
+	if cfg.LivenessDrainDelay > 0 && cfg.LivenessIgnoresShutdown {
+		return fmt.Errorf("%w: WithLivenessDrainDelay has no effect with WithLivenessIgnoresShutdown", ErrConflictingOptions)
+	}
+	splitPorts := cfg.ReadyPort != 0 || cfg.LivePort != 0
+	if splitPorts && !standaloneHTTP {
+		return fmt.Errorf("%w: WithReadyPort/WithLivePort require the standalone HTTP probe", ErrConflictingOptions)
+	}
+	if cfg.InternalProbePort != 0 {
+		if !standaloneHTTP {
+			return fmt.Errorf("%w: WithInternalProbe requires the standalone HTTP probe", ErrConflictingOptions)
+		}
+		if p := cfg.InternalProbePort; (p == cfg.HTTPPort && cfg.HTTPListener == nil) || p == cfg.ReadyPort || p == cfg.LivePort {
+			return fmt.Errorf("%w: WithInternalProbe port %d is already used by another probe listener", ErrConflictingOptions, p)
+		}
+	}
+	if len(cfg.HTTPMiddleware) > 0 && !standaloneHTTP {
+		return fmt.Errorf("%w: WithHTTPMiddleware requires the standalone HTTP probe", ErrConflictingOptions)
+	}
+	if cfg.HTTP2Cleartext && !standaloneHTTP {
+		return fmt.Errorf("%w: WithHTTP2Cleartext requires the standalone HTTP probe", ErrConflictingOptions)
+	}
+	if cfg.RootHandler && !standaloneHTTP {
+		return fmt.Errorf("%w: WithRootHandler requires the standalone HTTP probe", ErrConflictingOptions)
+	}
+	if cfg.HTTPListener != nil {
+		if cfg.httpPortSet || splitPorts {
+			return fmt.Errorf("%w: WithHTTPListener cannot be combined with WithHTTPPort/WithReadyPort/WithLivePort", ErrConflictingOptions)
+		}
+		if !standaloneHTTP {
+			return fmt.Errorf("%w: WithHTTPListener requires the standalone HTTP probe", ErrConflictingOptions)
+		}
+	}
+	switch {
+	case cfg.ExistingGRPCServer != nil:
+		if cfg.httpPortSet || cfg.grpcPortSet {
+			return fmt.Errorf("%w: WithHTTPPort/WithGRPCPort have no effect with WithExistingGRPCServer", ErrConflictingOptions)
+		}
+		if cfg.mechanismSet && cfg.CheckMechanism != CheckGRPC {
+			return fmt.Errorf("%w: WithExistingGRPCServer requires the CheckGRPC mechanism", ErrConflictingOptions)
+		}
+	case cfg.ExistingHTTPMux != nil:
+		if cfg.httpPortSet || cfg.grpcPortSet {
+			return fmt.Errorf("%w: WithHTTPPort/WithGRPCPort have no effect with WithExistingHTTPMux", ErrConflictingOptions)
+		}
+		if cfg.mechanismSet && cfg.CheckMechanism != CheckHTTP {
+			return fmt.Errorf("%w: WithExistingHTTPMux requires the CheckHTTP mechanism", ErrConflictingOptions)
+		}
+	}
+	return nil
+}
+
+// Hooks carries the callbacks and context the PodManager wires into its
+// probe. They are kept out of Config, which users can fill in directly.
+type Hooks struct {
+	// OnReadyServed is called with each /ready verdict served.
+	OnReadyServed func(ok bool)
+	// Status backs the WithStatusEndpoint handler.
+	Status func() any
+	// Metrics backs the WithTextMetricsEndpoint handler.
+	Metrics func() []byte
+	// OnForcedStop is called when a probe server is stopped forcibly.
+	OnForcedStop func()
+	// ListenContext abandons a bind still retrying once it is done.
+	ListenContext context.Context
+}
+
+// NewProbe returns a check.Server for the given config. HTTP probes run the
+// checkers held by reg and record their results there.
+func NewProbe(cfg Config, reg *check.Registry, hooks Hooks) check.Server {
+	if cfg.NoProbe {
+		return check.NewNopProbe()
+	}
 	if cfg.ExistingGRPCServer != nil {
-		return check.NewExistingGRPCProbe(cfg.ExistingGRPCServer)
+		if cfg.ManageGRPCServer {
+			return check.NewManagedGRPCProbe(cfg.ExistingGRPCServer, grpcOptions(cfg, hooks))
+		}
+		return check.NewExistingGRPCProbeWithOptions(cfg.ExistingGRPCServer, grpcOptions(cfg, hooks))
 	}
 	if cfg.ExistingHTTPMux != nil {
-		return check.NewExistingHTTPProbe(cfg.ExistingHTTPMux, cfg.CheckerTimeout, cfg.Checkers)
+		return check.NewExistingHTTPProbe(cfg.ExistingHTTPMux, httpOptions(cfg, reg, hooks))
 	}
 	switch cfg.CheckMechanism {
 	case CheckGRPC:
-		return check.NewGRPCProbe(cfg.GRPCPort, cfg.ShutdownTimeout)
+		return check.NewGRPCProbe(grpcOptions(cfg, hooks))
 	default:
-		return check.NewHTTPProbe(cfg.HTTPPort, cfg.ShutdownTimeout, cfg.CheckerTimeout, cfg.Checkers, cfg.ErrorHandler)
+		return check.NewHTTPProbe(httpOptions(cfg, reg, hooks))
+	}
+}
+
+func grpcOptions(cfg Config, hooks Hooks) check.GRPCOptions {
+	return check.GRPCOptions{
+		Port:                  cfg.GRPCPort,
+		Listener:              cfg.GRPCListener,
+		ShutdownTimeout:       cfg.ProbeShutdownTimeout(),
+		DrainProgressInterval: cfg.DrainProgressInterval,
+		OnDrainProgress:       cfg.OnDrainProgress,
+		Listen:                listenOptions(cfg, hooks),
+		Reflection:            cfg.GRPCReflection,
+		Channelz:              cfg.GRPCChannelz,
+		MaxConcurrentStreams:  cfg.GRPCMaxConcurrentStreams,
+		MaxRecvMsgSize:        cfg.GRPCMaxRecvMsgSize,
+		StartupShutdownGrace:  cfg.GRPCStartupShutdownGrace,
+		Clock:                 cfg.Clock,
+		OnForcedStop:          forcedStopHook(cfg, hooks),
+		LiveIgnoresShutdown:   liveIgnoresShutdown(cfg),
+	}
+}
+
+// liveIgnoresShutdown reports whether the probes should leave liveness to the
+// StateReader during shutdown. With a LivenessDrainDelay the manager fails
+// liveness itself once the delay has passed.
+func liveIgnoresShutdown(cfg Config) bool {
+	return cfg.LivenessIgnoresShutdown || cfg.LivenessDrainDelay > 0
+}
+
+func httpOptions(cfg Config, reg *check.Registry, hooks Hooks) check.HTTPOptions {
+	return check.HTTPOptions{
+		Port:                      cfg.HTTPPort,
+		ShutdownTimeout:           cfg.ShutdownTimeout,
+		CheckerTimeout:            cfg.CheckerTimeout,
+		Checkers:                  reg,
+		ErrorHandler:              cfg.ErrorHandler,
+		UniformJSONBodies:         cfg.UniformJSONBodies,
+		Pprof:                     cfg.Pprof,
+		Listen:                    listenOptions(cfg, hooks),
+		ReadHeaderTimeout:         cfg.ReadHeaderTimeout,
+		ReadyResponseWriter:       cfg.ReadyResponseWriter,
+		VersionHeaderName:         cfg.VersionHeaderName,
+		VersionHeaderValue:        cfg.VersionHeaderValue,
+		ReadyPort:                 cfg.ReadyPort,
+		LivePort:                  cfg.LivePort,
+		InternalPort:              cfg.InternalProbePort,
+		InternalVerbose:           cfg.InternalProbeVerbose,
+		MaxCheckerErrorLen:        cfg.MaxCheckerErrorLen,
+		PingPath:                  cfg.PingPath,
+		UnhealthyStatusCode:       cfg.UnhealthyStatusCode,
+		OnReadyServed:             hooks.OnReadyServed,
+		Listener:                  cfg.HTTPListener,
+		Clock:                     cfg.Clock,
+		FailureTolerance:          cfg.ReadinessFailureTolerance,
+		StatusPath:                cfg.StatusPath,
+		Status:                    hooks.Status,
+		MetricsPath:               cfg.MetricsPath,
+		Metrics:                   hooks.Metrics,
+		OnForcedStop:              forcedStopHook(cfg, hooks),
+		PathPrefix:                cfg.ProbePathPrefix,
+		HealthJSON:                cfg.HealthJSONFormat,
+		LiveIgnoresShutdown:       liveIgnoresShutdown(cfg),
+		OnCheckerFailure:          cfg.CheckerFailureHandler,
+		OnCheckerRecovery:         cfg.CheckerRecoveryHandler,
+		LoadGate:                  cfg.LoadGate,
+		RootHandler:               cfg.RootHandler,
+		OnReadinessFailure:        cfg.ReadinessFailureHandler,
+		AccessLog:                 cfg.ProbeAccessLog,
+		FailureBody:               cfg.FailureBody,
+		ReadyBodyOnFailureOnly:    cfg.ReadyBodyOnFailureOnly,
+		Middleware:                cfg.HTTPMiddleware,
+		ResultMarshaler:           cfg.ResultMarshaler,
+		RunCheckersDuringShutdown: cfg.RunCheckersDuringShutdown,
+		FailureLog:                cfg.ProbeFailureLog,
+		FailureLogEvery:           cfg.ProbeFailureLogEvery,
+		DetachedCheckerContext:    cfg.DetachedCheckerContext || cfg.CheckerDeadline == CheckerDeadlineFixed,
+		FailOnWarn:                cfg.FailOnWarn,
+		ReadinessDecider:          cfg.ReadinessDecider,
+		Compress:                  cfg.ProbeCompression,
+		ForceCloseOnTimeout:       cfg.ForceCloseOnTimeout,
+		HTTP2Cleartext:            cfg.HTTP2Cleartext,
+		ReadyRateLimit:            cfg.ReadyRateLimit,
+		CheckerDependencies:       cfg.CheckerDependencies,
+		HealthJSONFieldNames:      cfg.VerboseFieldNames,
+	}
+}
+
+func listenOptions(cfg Config, hooks Hooks) check.ListenOptions {
+	return check.ListenOptions{
+		Host:         cfg.BindAddress,
+		BindAttempts: cfg.BindAttempts,
+		BindBackoff:  cfg.BindBackoff,
+		ReuseAddr:    cfg.ReuseAddr,
+		Config:       cfg.ListenConfig,
+		Context:      hooks.ListenContext,
+		Clock:        cfg.Clock,
 	}
 }
@@ -2,10 +2,16 @@ package config_test
 
 import (
 	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/kroderdev/pod-lifecycle-go/internal/config"
+	"google.golang.org/grpc"
 )
 
 // stubChecker satisfies check.Checker without importing the internal package directly.
@@ -30,6 +36,12 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.CheckerTimeout != 2*time.Second {
 		t.Errorf("CheckerTimeout: got %v, want 2s", cfg.CheckerTimeout)
 	}
+	if !cfg.ReuseAddr {
+		t.Error("ReuseAddr: got false, want true")
+	}
+	if cfg.MaxCheckerErrorLen != 256 {
+		t.Errorf("MaxCheckerErrorLen: got %d, want 256", cfg.MaxCheckerErrorLen)
+	}
 	if cfg.CheckMechanism != config.CheckHTTP {
 		t.Errorf("CheckMechanism: got %v, want CheckHTTP", cfg.CheckMechanism)
 	}
@@ -39,12 +51,12 @@ func TestPortValidation(t *testing.T) {
 	invalid := []int{0, -1, 65536, -100}
 	for _, p := range invalid {
 		_, err := config.ApplyOptions([]config.Option{config.WithHTTPPort(p)})
-		if err == nil {
-			t.Errorf("HTTPPort %d: expected error, got nil", p)
+		if !errors.Is(err, config.ErrInvalidPort) {
+			t.Errorf("HTTPPort %d: got %v, want ErrInvalidPort", p, err)
 		}
 		_, err = config.ApplyOptions([]config.Option{config.WithGRPCPort(p)})
-		if err == nil {
-			t.Errorf("GRPCPort %d: expected error, got nil", p)
+		if !errors.Is(err, config.ErrInvalidPort) {
+			t.Errorf("GRPCPort %d: got %v, want ErrInvalidPort", p, err)
 		}
 	}
 	valid := []int{1, 80, 8080, 65535}
@@ -60,6 +72,328 @@ func TestPortValidation(t *testing.T) {
 	}
 }
 
+func TestSplitPortValidation(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{config.WithReadyPort(70000)}); err == nil {
+		t.Error("ReadyPort 70000: expected error, got nil")
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithLivePort(-1)}); err == nil {
+		t.Error("LivePort -1: expected error, got nil")
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithReadyPort(9001),
+	}); err == nil {
+		t.Error("ReadyPort with CheckGRPC: expected error, got nil")
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithReadyPort(9001), config.WithLivePort(9002)}); err != nil {
+		t.Errorf("split ports: unexpected error: %v", err)
+	}
+}
+
+func TestInternalProbeValidation(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{config.WithInternalProbe(70000, true)}); !errors.Is(err, config.ErrInvalidPort) {
+		t.Errorf("port 70000: got %v, want ErrInvalidPort", err)
+	}
+	conflicting := map[string][]config.Option{
+		"same as http port":  {config.WithHTTPPort(9000), config.WithInternalProbe(9000, true)},
+		"same as ready port": {config.WithReadyPort(9001), config.WithInternalProbe(9001, true)},
+		"CheckGRPC":          {config.WithCheckMechanism(config.CheckGRPC), config.WithInternalProbe(9002, true)},
+		"existing mux":       {config.WithExistingHTTPMux(http.NewServeMux()), config.WithInternalProbe(9002, true)},
+	}
+	for name, opts := range conflicting {
+		if _, err := config.ApplyOptions(opts); !errors.Is(err, config.ErrConflictingOptions) {
+			t.Errorf("%s: got %v, want ErrConflictingOptions", name, err)
+		}
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithInternalProbe(9002, true)}); err != nil {
+		t.Errorf("internal probe: unexpected error: %v", err)
+	}
+}
+
+func TestHTTPListenerConflicts(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = lis.Close() }()
+	conflicting := map[string][]config.Option{
+		"listener + http port":  {config.WithHTTPListener(lis), config.WithHTTPPort(9000)},
+		"listener + ready port": {config.WithHTTPListener(lis), config.WithReadyPort(9001)},
+		"listener + CheckGRPC":  {config.WithHTTPListener(lis), config.WithCheckMechanism(config.CheckGRPC)},
+		"listener + http mux":   {config.WithHTTPListener(lis), config.WithExistingHTTPMux(http.NewServeMux())},
+	}
+	for name, opts := range conflicting {
+		if _, err := config.ApplyOptions(opts); !errors.Is(err, config.ErrConflictingOptions) {
+			t.Errorf("%s: got %v, want ErrConflictingOptions", name, err)
+		}
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithHTTPListener(lis)}); err != nil {
+		t.Errorf("listener alone: unexpected error: %v", err)
+	}
+}
+
+func TestPingPathValidation(t *testing.T) {
+	for _, p := range []string{"ping", "/ready", "/live", "/startup"} {
+		if _, err := config.ApplyOptions([]config.Option{config.WithPingEndpoint(p)}); err == nil {
+			t.Errorf("ping path %q: expected error, got nil", p)
+		}
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithPingEndpoint("/ping")}); err != nil {
+		t.Errorf("ping path /ping: unexpected error: %v", err)
+	}
+}
+
+func TestStatusPathValidation(t *testing.T) {
+	for _, p := range []string{"status", "/ready", "/ping"} {
+		if _, err := config.ApplyOptions([]config.Option{config.WithPingEndpoint("/ping"), config.WithStatusEndpoint(p)}); !errors.Is(err, config.ErrInvalidOption) {
+			t.Errorf("status path %q: got %v, want ErrInvalidOption", p, err)
+		}
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithStatusEndpoint("/status"),
+	}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("with CheckGRPC: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithStatusEndpoint("/status")}); err != nil {
+		t.Errorf("status path /status: unexpected error: %v", err)
+	}
+}
+
+func TestProbePathPrefixValidation(t *testing.T) {
+	mux := http.NewServeMux()
+	for _, p := range []string{"internal", "/internal/"} {
+		if _, err := config.ApplyOptions([]config.Option{config.WithExistingHTTPMux(mux), config.WithProbePathPrefix(p)}); !errors.Is(err, config.ErrInvalidOption) {
+			t.Errorf("prefix %q: got %v, want ErrInvalidOption", p, err)
+		}
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithProbePathPrefix("/internal")}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("without existing mux: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithExistingHTTPMux(mux), config.WithProbePathPrefix("/internal")}); err != nil {
+		t.Errorf("valid prefix: unexpected error: %v", err)
+	}
+}
+
+func TestProbeCompressionRequiresHTTP(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithProbeCompression(true),
+	}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("with CheckGRPC: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithProbeCompression(true)}); err != nil {
+		t.Errorf("with HTTP: unexpected error: %v", err)
+	}
+}
+
+func TestHTTP2CleartextRequiresStandaloneHTTP(t *testing.T) {
+	for name, opts := range map[string][]config.Option{
+		"CheckGRPC":    {config.WithCheckMechanism(config.CheckGRPC), config.WithHTTP2Cleartext(true)},
+		"existing mux": {config.WithExistingHTTPMux(http.NewServeMux()), config.WithHTTP2Cleartext(true)},
+	} {
+		if _, err := config.ApplyOptions(opts); !errors.Is(err, config.ErrConflictingOptions) {
+			t.Errorf("%s: got %v, want ErrConflictingOptions", name, err)
+		}
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithHTTP2Cleartext(true)}); err != nil {
+		t.Errorf("standalone HTTP: unexpected error: %v", err)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ExistingGRPCServer = grpc.NewServer()
+	if _, err := config.FromConfig(cfg); err != nil {
+		t.Errorf("existing gRPC server with default ports: unexpected error: %v", err)
+	}
+	cfg.HTTPPort = 9090
+	if _, err := config.FromConfig(cfg); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("existing gRPC server with HTTPPort changed: got %v, want ErrConflictingOptions", err)
+	}
+
+	cfg = config.DefaultConfig()
+	cfg.Checkers["db"] = stubChecker{}
+	checkers := cfg.Checkers
+	got, err := config.FromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkers["cache"] = stubChecker{}
+	if len(got.Checkers) != 1 {
+		t.Errorf("Checkers: got %d entries, want the map copied at validation", len(got.Checkers))
+	}
+
+	cfg = config.DefaultConfig()
+	cfg.SignalActions = nil
+	if _, err := config.FromConfig(cfg); !errors.Is(err, config.ErrInvalidOption) {
+		t.Errorf("no signal actions: got %v, want ErrInvalidOption", err)
+	}
+}
+
+func TestLoadGateRequiresHTTP(t *testing.T) {
+	gate := func() (int, int) { return 0, 1 }
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithLoadGate(gate),
+	}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("with CheckGRPC: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithLoadGate(gate)}); err != nil {
+		t.Errorf("with HTTP: unexpected error: %v", err)
+	}
+}
+
+func TestRootHandlerRequiresStandaloneHTTP(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithExistingHTTPMux(http.NewServeMux()),
+		config.WithRootHandler(true),
+	}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("with existing mux: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithRootHandler(true)}); err != nil {
+		t.Errorf("standalone: unexpected error: %v", err)
+	}
+}
+
+func TestHTTPMiddlewareValidation(t *testing.T) {
+	mw := func(h http.Handler) http.Handler { return h }
+	if _, err := config.ApplyOptions([]config.Option{config.WithHTTPMiddleware(nil)}); !errors.Is(err, config.ErrInvalidOption) {
+		t.Errorf("nil middleware: got %v, want ErrInvalidOption", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithExistingHTTPMux(http.NewServeMux()),
+		config.WithHTTPMiddleware(mw),
+	}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("with existing mux: got %v, want ErrConflictingOptions", err)
+	}
+	cfg, err := config.ApplyOptions([]config.Option{config.WithHTTPMiddleware(mw), config.WithHTTPMiddleware(mw)})
+	if err != nil {
+		t.Fatalf("standalone: unexpected error: %v", err)
+	}
+	if len(cfg.HTTPMiddleware) != 2 {
+		t.Errorf("HTTPMiddleware: got %d entries, want 2", len(cfg.HTTPMiddleware))
+	}
+}
+
+func TestProbeFailureLoggingValidation(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if _, err := config.ApplyOptions([]config.Option{config.WithProbeFailureLogging(log, 0)}); !errors.Is(err, config.ErrInvalidOption) {
+		t.Errorf("sampleEvery 0: got %v, want ErrInvalidOption", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithProbeFailureLogging(log, 1),
+	}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("gRPC probes: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithProbeFailureLogging(log, 5)}); err != nil {
+		t.Errorf("standalone: unexpected error: %v", err)
+	}
+}
+
+func TestBindAddressValidation(t *testing.T) {
+	for _, host := range []string{"", "127.0.0.1", "::1", "fe80::1", "localhost"} {
+		cfg, err := config.ApplyOptions([]config.Option{config.WithBindAddress(host)})
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", host, err)
+			continue
+		}
+		if cfg.BindAddress != host {
+			t.Errorf("%q: BindAddress = %q", host, cfg.BindAddress)
+		}
+	}
+	for _, host := range []string{"[::1]", "127.0.0.1:8080", "::1:8080:x", "a b"} {
+		if _, err := config.ApplyOptions([]config.Option{config.WithBindAddress(host)}); !errors.Is(err, config.ErrInvalidOption) {
+			t.Errorf("%q: got %v, want ErrInvalidOption", host, err)
+		}
+	}
+}
+
+func TestUnhealthyStatusCodeValidation(t *testing.T) {
+	for _, code := range []int{200, 302, 600} {
+		if _, err := config.ApplyOptions([]config.Option{config.WithUnhealthyStatusCode(code)}); err == nil {
+			t.Errorf("code %d: expected error, got nil", code)
+		}
+	}
+	for _, code := range []int{429, 500, 503} {
+		if _, err := config.ApplyOptions([]config.Option{config.WithUnhealthyStatusCode(code)}); err != nil {
+			t.Errorf("code %d: unexpected error: %v", code, err)
+		}
+	}
+}
+
+func TestConfirmNotReadyValidation(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{config.WithConfirmNotReady(-1)}); err == nil {
+		t.Error("negative: expected error, got nil")
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithConfirmNotReady(2),
+	}); err == nil {
+		t.Error("with CheckGRPC: expected error, got nil")
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithConfirmNotReady(2)}); err != nil {
+		t.Errorf("with HTTP: unexpected error: %v", err)
+	}
+}
+
+func TestGRPCStartupShutdownGraceValidation(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{config.WithGRPCStartupShutdownGrace(-time.Second)}); !errors.Is(err, config.ErrInvalidOption) {
+		t.Errorf("negative grace: got %v, want ErrInvalidOption", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithGRPCStartupShutdownGrace(time.Second),
+	}); err != nil {
+		t.Errorf("positive grace: unexpected error: %v", err)
+	}
+}
+
+func TestLivenessDrainDelayValidation(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{config.WithLivenessDrainDelay(-time.Second)}); !errors.Is(err, config.ErrInvalidOption) {
+		t.Errorf("negative delay: got %v, want ErrInvalidOption", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithLivenessDrainDelay(time.Second),
+		config.WithLivenessIgnoresShutdown(true),
+	}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("with WithLivenessIgnoresShutdown: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithLivenessDrainDelay(time.Second)}); err != nil {
+		t.Errorf("positive delay: unexpected error: %v", err)
+	}
+}
+
+func TestReadinessFailureToleranceValidation(t *testing.T) {
+	for _, f := range []float64{-0.1, 1.5} {
+		if _, err := config.ApplyOptions([]config.Option{config.WithReadinessFailureTolerance(f)}); !errors.Is(err, config.ErrInvalidOption) {
+			t.Errorf("tolerance %v: got %v, want ErrInvalidOption", f, err)
+		}
+	}
+	for _, f := range []float64{0, 0.5, 1} {
+		if _, err := config.ApplyOptions([]config.Option{config.WithReadinessFailureTolerance(f)}); err != nil {
+			t.Errorf("tolerance %v: unexpected error: %v", f, err)
+		}
+	}
+}
+
+func TestBindRetryValidation(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{config.WithBindRetry(-1, 0)}); !errors.Is(err, config.ErrInvalidOption) {
+		t.Errorf("negative retries: got %v, want ErrInvalidOption", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithBindRetry(1, -time.Second)}); err == nil {
+		t.Error("negative backoff: expected error, got nil")
+	}
+	cfg, err := config.ApplyOptions([]config.Option{config.WithBindRetry(3, time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BindAttempts != 3 || cfg.BindBackoff != time.Second {
+		t.Errorf("got (%d, %v), want (3, 1s)", cfg.BindAttempts, cfg.BindBackoff)
+	}
+}
+
 func TestWithShutdownTimeout(t *testing.T) {
 	cfg, err := config.ApplyOptions([]config.Option{config.WithShutdownTimeout(10 * time.Second)})
 	if err != nil {
@@ -70,6 +404,31 @@ func TestWithShutdownTimeout(t *testing.T) {
 	}
 }
 
+func TestProbeShutdownTimeoutPrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []config.Option
+		want time.Duration
+	}{
+		{"default", nil, 5 * time.Second},
+		{"grpc unset falls back", []config.Option{config.WithCheckMechanism(config.CheckGRPC)}, 5 * time.Second},
+		{"grpc set", []config.Option{
+			config.WithCheckMechanism(config.CheckGRPC),
+			config.WithGRPCShutdownTimeout(30 * time.Second),
+		}, 30 * time.Second},
+		{"http ignores grpc value", []config.Option{config.WithGRPCShutdownTimeout(30 * time.Second)}, 5 * time.Second},
+	}
+	for _, tc := range tests {
+		cfg, err := config.ApplyOptions(tc.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := cfg.ProbeShutdownTimeout(); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
 func TestWithCheckerTimeout(t *testing.T) {
 	cfg, err := config.ApplyOptions([]config.Option{config.WithCheckerTimeout(500 * time.Millisecond)})
 	if err != nil {
@@ -94,6 +453,75 @@ func TestWithCheckerRegistration(t *testing.T) {
 	}
 }
 
+func TestCheckerNameValidation(t *testing.T) {
+	for _, name := range []string{"", " ", "my db", "db\t", "\ncache"} {
+		if _, err := config.ApplyOptions([]config.Option{config.WithChecker(name, stubChecker{})}); !errors.Is(err, config.ErrInvalidOption) {
+			t.Errorf("name %q: got %v, want ErrInvalidOption", name, err)
+		}
+	}
+	for _, name := range []string{"db", "redis-primary", "svc.v1/orders"} {
+		if _, err := config.ApplyOptions([]config.Option{config.WithChecker(name, stubChecker{})}); err != nil {
+			t.Errorf("name %q: unexpected error: %v", name, err)
+		}
+	}
+}
+
+func TestRequireCheckers(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{config.WithRequireCheckers(true)}); err == nil {
+		t.Error("no checkers: expected error, got nil")
+	}
+	_, err := config.ApplyOptions([]config.Option{
+		config.WithRequireCheckers(true),
+		config.WithChecker("db", stubChecker{}),
+	})
+	if err != nil {
+		t.Errorf("with checker: unexpected error: %v", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithRequireCheckers(false)}); err != nil {
+		t.Errorf("not required: unexpected error: %v", err)
+	}
+}
+
+func TestExistingServerConflicts(t *testing.T) {
+	srv := grpc.NewServer()
+	mux := http.NewServeMux()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	conflicting := map[string][]config.Option{
+		"grpc server + grpc port":     {config.WithExistingGRPCServer(srv), config.WithGRPCPort(9000)},
+		"grpc server + http port":     {config.WithExistingGRPCServer(srv), config.WithHTTPPort(9000)},
+		"grpc server + CheckHTTP":     {config.WithExistingGRPCServer(srv), config.WithCheckMechanism(config.CheckHTTP)},
+		"http mux + http port":        {config.WithExistingHTTPMux(mux), config.WithHTTPPort(9000)},
+		"http mux + grpc port":        {config.WithExistingHTTPMux(mux), config.WithGRPCPort(9000)},
+		"http mux + CheckGRPC":        {config.WithExistingHTTPMux(mux), config.WithCheckMechanism(config.CheckGRPC)},
+		"grpc server + http mux":      {config.WithExistingGRPCServer(srv), config.WithExistingHTTPMux(mux)},
+		"grpc listener + CheckHTTP":   {config.WithGRPCListener(ln), config.WithCheckMechanism(config.CheckHTTP)},
+		"grpc listener default":       {config.WithGRPCListener(ln)},
+		"grpc listener + grpc server": {config.WithGRPCListener(ln), config.WithExistingGRPCServer(srv)},
+		"grpc listener + http mux":    {config.WithGRPCListener(ln), config.WithExistingHTTPMux(mux)},
+	}
+	for name, opts := range conflicting {
+		if _, err := config.ApplyOptions(opts); !errors.Is(err, config.ErrConflictingOptions) {
+			t.Errorf("%s: got %v, want ErrConflictingOptions", name, err)
+		}
+	}
+	compatible := map[string][]config.Option{
+		"grpc server alone":         {config.WithExistingGRPCServer(srv)},
+		"grpc server + CheckGRPC":   {config.WithExistingGRPCServer(srv), config.WithCheckMechanism(config.CheckGRPC)},
+		"http mux alone":            {config.WithExistingHTTPMux(mux)},
+		"http mux + CheckHTTP":      {config.WithExistingHTTPMux(mux), config.WithCheckMechanism(config.CheckHTTP)},
+		"grpc listener + CheckGRPC": {config.WithGRPCListener(ln), config.WithCheckMechanism(config.CheckGRPC)},
+	}
+	for name, opts := range compatible {
+		if _, err := config.ApplyOptions(opts); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+}
+
 func TestWithCheckerDuplicateOverwrites(t *testing.T) {
 	c1, c2 := stubChecker{}, stubChecker{}
 	cfg, err := config.ApplyOptions([]config.Option{
@@ -110,7 +538,7 @@ func TestWithCheckerDuplicateOverwrites(t *testing.T) {
 
 func TestNewProbeHTTPNonNil(t *testing.T) {
 	cfg, _ := config.ApplyOptions(nil)
-	p := config.NewProbe(cfg)
+	p := config.NewProbe(cfg, nil, config.Hooks{})
 	if p == nil {
 		t.Error("NewProbe(CheckHTTP) returned nil")
 	}
@@ -118,8 +546,157 @@ func TestNewProbeHTTPNonNil(t *testing.T) {
 
 func TestNewProbeGRPCNonNil(t *testing.T) {
 	cfg, _ := config.ApplyOptions([]config.Option{config.WithCheckMechanism(config.CheckGRPC)})
-	p := config.NewProbe(cfg)
+	p := config.NewProbe(cfg, nil, config.Hooks{})
 	if p == nil {
 		t.Error("NewProbe(CheckGRPC) returned nil")
 	}
 }
+
+func TestForceCloseOnTimeoutRequiresHTTP(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithForceCloseOnTimeout(true),
+	}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("with CheckGRPC: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithForceCloseOnTimeout(true)}); err != nil {
+		t.Errorf("with HTTP: unexpected error: %v", err)
+	}
+}
+
+func TestReadyRateLimitValidation(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{config.WithReadyRateLimit(-1)}); !errors.Is(err, config.ErrInvalidOption) {
+		t.Errorf("negative rps: got %v, want ErrInvalidOption", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithReadyRateLimit(5),
+	}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("with CheckGRPC: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithReadyRateLimit(5)}); err != nil {
+		t.Errorf("with HTTP: unexpected error: %v", err)
+	}
+}
+
+func TestCheckerDependencyValidation(t *testing.T) {
+	ok := config.WithChecker("db", stubChecker{})
+	for name, opts := range map[string][]config.Option{
+		"unknown dependent":    {ok, config.WithCheckerDependency("migrations", "db")},
+		"unknown prerequisite": {ok, config.WithCheckerDependency("db", "network")},
+		"self":                 {ok, config.WithCheckerDependency("db", "db")},
+		"cycle": {
+			ok,
+			config.WithChecker("migrations", stubChecker{}),
+			config.WithChecker("schema", stubChecker{}),
+			config.WithCheckerDependency("migrations", "db"),
+			config.WithCheckerDependency("schema", "migrations"),
+			config.WithCheckerDependency("db", "schema"),
+		},
+	} {
+		if _, err := config.ApplyOptions(opts); !errors.Is(err, config.ErrInvalidOption) {
+			t.Errorf("%s: got %v, want ErrInvalidOption", name, err)
+		}
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		ok,
+		config.WithChecker("migrations", stubChecker{}),
+		config.WithCheckerDependency("migrations", "db"),
+	}); err != nil {
+		t.Errorf("acyclic: unexpected error: %v", err)
+	}
+}
+
+func TestCheckerDeadlineFixedRequiresHTTP(t *testing.T) {
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithCheckerDeadlineStrategy(config.CheckerDeadlineFixed),
+	}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("with CheckGRPC: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithCheckerDeadlineStrategy(config.CheckerDeadlineMin),
+	}); err != nil {
+		t.Errorf("CheckerDeadlineMin with CheckGRPC: unexpected error: %v", err)
+	}
+}
+
+func TestNoProbeConflicts(t *testing.T) {
+	for name, opts := range map[string][]config.Option{
+		"existing mux": {config.WithNoProbe(), config.WithExistingHTTPMux(http.NewServeMux())},
+		"port":         {config.WithNoProbe(), config.WithHTTPPort(9000)},
+		"mechanism":    {config.WithNoProbe(), config.WithCheckMechanism(config.CheckGRPC)},
+		"HTTP-only":    {config.WithNoProbe(), config.WithStatusEndpoint("/status")},
+		"standalone":   {config.WithNoProbe(), config.WithRootHandler(true)},
+		"checker":      {config.WithNoProbe(), config.WithChecker("db", stubChecker{})},
+		"bind address": {config.WithNoProbe(), config.WithBindAddress("127.0.0.1")},
+		"read header":  {config.WithNoProbe(), config.WithReadHeaderTimeout(time.Second)},
+		"gRPC timeout": {config.WithNoProbe(), config.WithGRPCShutdownTimeout(time.Second)},
+	} {
+		if _, err := config.ApplyOptions(opts); !errors.Is(err, config.ErrConflictingOptions) {
+			t.Errorf("%s: got %v, want ErrConflictingOptions", name, err)
+		}
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithNoProbe()}); err != nil {
+		t.Errorf("WithNoProbe alone: unexpected error: %v", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{
+		config.WithNoProbe(),
+		config.WithChecker("db", stubChecker{}),
+		config.WithStartupCheckers("db"),
+	}); err != nil {
+		t.Errorf("WithNoProbe with a startup checker: unexpected error: %v", err)
+	}
+}
+
+func TestVerboseFieldNamesValidation(t *testing.T) {
+	health := config.WithHealthJSONFormat(true)
+	for name, names := range map[string]map[string]string{
+		"unknown field": {"durationMs": "latency_ms"},
+		"empty name":    {"status": ""},
+		"collision":     {"output": "time"},
+	} {
+		if _, err := config.ApplyOptions([]config.Option{health, config.WithVerboseFieldNames(names)}); !errors.Is(err, config.ErrInvalidOption) {
+			t.Errorf("%s: got %v, want ErrInvalidOption", name, err)
+		}
+	}
+	renames := config.WithVerboseFieldNames(map[string]string{"status": "state", "output": "err"})
+	if _, err := config.ApplyOptions([]config.Option{renames}); !errors.Is(err, config.ErrConflictingOptions) {
+		t.Errorf("without WithHealthJSONFormat: got %v, want ErrConflictingOptions", err)
+	}
+	if _, err := config.ApplyOptions([]config.Option{health, renames}); err != nil {
+		t.Errorf("valid renames: unexpected error: %v", err)
+	}
+}
+
+func TestVersionHeaderValidation(t *testing.T) {
+	for name, opt := range map[string]config.Option{
+		"empty name":    config.WithVersionHeader("", "1.2.3"),
+		"space in name": config.WithVersionHeader("X App Version", "1.2.3"),
+		"CRLF in value": config.WithVersionHeader("X-App-Version", "1.2.3\r\nX-Injected: 1"),
+		"LF in value":   config.WithVersionHeader("X-App-Version", "1.2.3\n"),
+	} {
+		if _, err := config.ApplyOptions([]config.Option{opt}); !errors.Is(err, config.ErrInvalidOption) {
+			t.Errorf("%s: got %v, want ErrInvalidOption", name, err)
+		}
+	}
+	if _, err := config.ApplyOptions([]config.Option{config.WithVersionHeader("X-App-Version", "1.2.3 (abc)")}); err != nil {
+		t.Errorf("valid header: unexpected error: %v", err)
+	}
+}
+
+func TestExistingGRPCServerReplacesManaged(t *testing.T) {
+	a, b := grpc.NewServer(), grpc.NewServer()
+	cfg, err := config.ApplyOptions([]config.Option{
+		config.WithCheckMechanism(config.CheckGRPC),
+		config.WithManagedGRPCServer(a),
+		config.WithExistingGRPCServer(b),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ExistingGRPCServer != b || cfg.ManageGRPCServer {
+		t.Errorf("got server %p managed %v, want %p unmanaged", cfg.ExistingGRPCServer, cfg.ManageGRPCServer, b)
+	}
+}
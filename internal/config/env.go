@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by OptionsFromEnv.
+const (
+	EnvCheckMechanism      = "POD_LIFECYCLE_CHECK_MECHANISM" // "http" or "grpc"
+	EnvHTTPPort            = "POD_LIFECYCLE_HTTP_PORT"
+	EnvGRPCPort            = "POD_LIFECYCLE_GRPC_PORT"
+	EnvShutdownTimeout     = "POD_LIFECYCLE_SHUTDOWN_TIMEOUT" // time.ParseDuration syntax, e.g. "10s"
+	EnvGRPCShutdownTimeout = "POD_LIFECYCLE_GRPC_SHUTDOWN_TIMEOUT"
+	EnvCheckerTimeout      = "POD_LIFECYCLE_CHECKER_TIMEOUT"
+	EnvMinUptime           = "POD_LIFECYCLE_MIN_UPTIME"
+	EnvReadHeaderTimeout   = "POD_LIFECYCLE_READ_HEADER_TIMEOUT"
+)
+
+// OptionsFromEnv builds options from the POD_LIFECYCLE_* variables returned by
+// lookup (typically os.LookupEnv). Unset or empty variables are skipped so the
+// defaults apply. It returns an error naming the variable if a value does not
+// parse (wrapping ErrInvalidOption) or the resulting configuration is invalid.
+func OptionsFromEnv(lookup func(string) (string, bool)) ([]Option, error) {
+	var opts []Option
+	get := func(name string) (string, bool) {
+		v, ok := lookup(name)
+		v = strings.TrimSpace(v)
+		return v, ok && v != ""
+	}
+
+	if v, ok := get(EnvCheckMechanism); ok {
+		switch strings.ToLower(v) {
+		case "http":
+			opts = append(opts, WithCheckMechanism(CheckHTTP))
+		case "grpc":
+			opts = append(opts, WithCheckMechanism(CheckGRPC))
+		default:
+			return nil, fmt.Errorf("%w: %s %q must be http or grpc", ErrInvalidOption, EnvCheckMechanism, v)
+		}
+	}
+	for _, p := range []struct {
+		name string
+		opt  func(int) Option
+	}{
+		{EnvHTTPPort, WithHTTPPort},
+		{EnvGRPCPort, WithGRPCPort},
+	} {
+		if v, ok := get(p.name); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s %q: %w", ErrInvalidOption, p.name, v, err)
+			}
+			opts = append(opts, p.opt(n))
+		}
+	}
+	for _, d := range []struct {
+		name string
+		opt  func(time.Duration) Option
+	}{
+		{EnvShutdownTimeout, WithShutdownTimeout},
+		{EnvGRPCShutdownTimeout, WithGRPCShutdownTimeout},
+		{EnvCheckerTimeout, WithCheckerTimeout},
+		{EnvMinUptime, WithMinUptime},
+		{EnvReadHeaderTimeout, WithReadHeaderTimeout},
+	} {
+		if v, ok := get(d.name); ok {
+			dur, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s %q: %w", ErrInvalidOption, d.name, v, err)
+			}
+			opts = append(opts, d.opt(dur))
+		}
+	}
+
+	if _, err := ApplyOptions(opts); err != nil {
+		return nil, fmt.Errorf("environment: %w", err)
+	}
+	return opts, nil
+}
@@ -0,0 +1,62 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/config"
+)
+
+func lookupMap(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	opts, err := config.OptionsFromEnv(lookupMap(map[string]string{
+		config.EnvCheckMechanism:  "grpc",
+		config.EnvGRPCPort:        "9090",
+		config.EnvShutdownTimeout: "12s",
+		config.EnvCheckerTimeout:  "750ms",
+		config.EnvMinUptime:       "", // empty: default applies
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.ApplyOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CheckMechanism != config.CheckGRPC {
+		t.Errorf("CheckMechanism: got %v, want CheckGRPC", cfg.CheckMechanism)
+	}
+	if cfg.GRPCPort != 9090 {
+		t.Errorf("GRPCPort: got %d, want 9090", cfg.GRPCPort)
+	}
+	if cfg.ShutdownTimeout != 12*time.Second {
+		t.Errorf("ShutdownTimeout: got %v, want 12s", cfg.ShutdownTimeout)
+	}
+	if cfg.CheckerTimeout != 750*time.Millisecond {
+		t.Errorf("CheckerTimeout: got %v, want 750ms", cfg.CheckerTimeout)
+	}
+	if cfg.HTTPPort != 8080 || cfg.MinUptime != 0 {
+		t.Errorf("unset vars: got HTTPPort %d, MinUptime %v; want defaults", cfg.HTTPPort, cfg.MinUptime)
+	}
+}
+
+func TestOptionsFromEnvInvalid(t *testing.T) {
+	invalid := []map[string]string{
+		{config.EnvCheckMechanism: "tcp"},
+		{config.EnvHTTPPort: "eighty"},
+		{config.EnvHTTPPort: "70000"},
+		{config.EnvShutdownTimeout: "5"},
+		{config.EnvMinUptime: "-1s"},
+	}
+	for _, env := range invalid {
+		if _, err := config.OptionsFromEnv(lookupMap(env)); err == nil {
+			t.Errorf("%v: expected error, got nil", env)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import "log/slog"
+
+// String returns "http" or "grpc".
+func (m CheckMechanism) String() string {
+	if m == CheckGRPC {
+		return "grpc"
+	}
+	return "http"
+}
+
+// LogAttrs returns the main effective settings of c as log attributes: the
+// probe mechanism and ports, shared-server modes, and timeouts. Settings left
+// at their zero value are omitted. Checker names are not included because
+// checkers can change after construction.
+func (c Config) LogAttrs() []slog.Attr {
+	attrs := []slog.Attr{slog.String("mechanism", c.CheckMechanism.String())}
+	switch {
+	case c.ExistingHTTPMux != nil:
+		attrs = append(attrs, slog.Bool("existingHTTPMux", true))
+	case c.ExistingGRPCServer != nil:
+		attrs = append(attrs, slog.Bool("existingGRPCServer", true))
+	case c.CheckMechanism == CheckGRPC:
+		attrs = append(attrs, slog.Int("grpcPort", c.GRPCPort))
+	default:
+		attrs = append(attrs, slog.Int("httpPort", c.HTTPPort))
+	}
+	for _, p := range []struct {
+		key  string
+		port int
+	}{{"readyPort", c.ReadyPort}, {"livePort", c.LivePort}, {"internalPort", c.InternalProbePort}} {
+		if p.port != 0 {
+			attrs = append(attrs, slog.Int(p.key, p.port))
+		}
+	}
+	if c.BindAddress != "" {
+		attrs = append(attrs, slog.String("bindAddress", c.BindAddress))
+	}
+	attrs = append(attrs,
+		slog.Duration("shutdownTimeout", c.ProbeShutdownTimeout()),
+		slog.Duration("checkerTimeout", c.CheckerTimeout),
+	)
+	if c.MinUptime > 0 {
+		attrs = append(attrs, slog.Duration("minUptime", c.MinUptime))
+	}
+	return attrs
+}
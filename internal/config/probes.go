@@ -0,0 +1,79 @@
+package config
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+// Kubernetes probe defaults the recommendation starts from.
+const (
+	minPeriodSeconds      = 10
+	defaultFailures       = 3
+	terminationGraceExtra = 5 * time.Second
+)
+
+// ProbeConfig holds Kubernetes probe settings that agree with a Config's
+// timeouts, for rendering into manifests or templates.
+type ProbeConfig struct {
+	// PeriodSeconds is how often the kubelet probes.
+	PeriodSeconds int
+	// TimeoutSeconds outlasts CheckerTimeout, so a slow checker fails /ready
+	// with its own error instead of a kubelet timeout.
+	TimeoutSeconds int
+	// FailureThreshold is the number of consecutive failures before the
+	// kubelet acts.
+	FailureThreshold int
+	// TerminationGracePeriodSeconds outlasts the shutdown timeout, so the
+	// kubelet does not SIGKILL the pod while it is still draining.
+	TerminationGracePeriodSeconds int
+}
+
+// RecommendedProbeConfig derives Kubernetes probe settings from c:
+// TimeoutSeconds is CheckerTimeout plus a second, PeriodSeconds is at least
+// twice that (and at least the Kubernetes default of 10) so probes never
+// overlap, and TerminationGracePeriodSeconds is the probe shutdown timeout
+// plus 5s for the application's own cleanup.
+func (c Config) RecommendedProbeConfig() ProbeConfig {
+	timeout := ceilSeconds(c.CheckerTimeout) + 1
+	return ProbeConfig{
+		PeriodSeconds:                 max(minPeriodSeconds, 2*timeout),
+		TimeoutSeconds:                timeout,
+		FailureThreshold:              defaultFailures,
+		TerminationGracePeriodSeconds: ceilSeconds(c.ProbeShutdownTimeout() + terminationGraceExtra),
+	}
+}
+
+// ceilSeconds rounds d up to whole seconds.
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// ProbePaths returns the sorted HTTP paths the probe registers under c,
+// across all of its listeners: the probe endpoints (with the path prefix on
+// an existing mux), the optional ping, status, and metrics endpoints, pprof,
+// and the root handler. It returns nil for gRPC probes.
+func (c Config) ProbePaths() []string {
+	if c.CheckMechanism != CheckHTTP || c.ExistingGRPCServer != nil {
+		return nil
+	}
+	prefix := ""
+	if c.ExistingHTTPMux != nil {
+		prefix = c.ProbePathPrefix
+	}
+	var paths []string
+	for _, p := range []string{"/ready", "/live", "/startup", c.PingPath, c.StatusPath, c.MetricsPath} {
+		if p != "" {
+			paths = append(paths, prefix+p)
+		}
+	}
+	if c.Pprof {
+		paths = append(paths, check.PprofPaths()...)
+	}
+	if c.RootHandler && c.ExistingHTTPMux == nil {
+		paths = append(paths, "/", "/favicon.ico")
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// SignalActionKind identifies what a SignalAction does.
+type SignalActionKind int
+
+const (
+	// ActionShutdown begins graceful shutdown.
+	ActionShutdown SignalActionKind = iota
+	// ActionReload runs the registered reload hooks.
+	ActionReload
+	// ActionToggleReady flips readiness.
+	ActionToggleReady
+	// ActionCustom calls Func.
+	ActionCustom
+)
+
+// SignalAction is what the manager does when it receives a signal.
+type SignalAction struct {
+	Kind SignalActionKind
+	Func func() // set for ActionCustom
+}
+
+// defaultSignalActions returns the built-in mapping: SIGTERM and SIGINT shut
+// down.
+func defaultSignalActions() map[os.Signal]SignalAction {
+	return map[os.Signal]SignalAction{
+		syscall.SIGTERM: {Kind: ActionShutdown},
+		syscall.SIGINT:  {Kind: ActionShutdown},
+	}
+}
+
+// WithSignalAction makes the manager perform action when it receives sig,
+// replacing any earlier mapping for sig. SIGTERM and SIGINT map to
+// ActionShutdown by default.
+func WithSignalAction(sig os.Signal, action SignalAction) Option {
+	return func(c *Config) { c.SignalActions[sig] = action }
+}
+
+// validateSignalActions rejects nil signals and custom actions without a func.
+func validateSignalActions(actions map[os.Signal]SignalAction) error {
+	for sig, a := range actions {
+		if sig == nil {
+			return fmt.Errorf("%w: WithSignalAction signal must not be nil", ErrInvalidOption)
+		}
+		if a.Kind < ActionShutdown || a.Kind > ActionCustom {
+			return fmt.Errorf("%w: unknown signal action %d for %v", ErrInvalidOption, a.Kind, sig)
+		}
+		if a.Kind == ActionCustom && a.Func == nil {
+			return fmt.Errorf("%w: custom action for %v has a nil func", ErrInvalidOption, sig)
+		}
+	}
+	return nil
+}
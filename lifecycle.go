@@ -2,11 +2,16 @@ package podlifecycle
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
 	"sync/atomic"
-	"syscall"
 	"time"
 
 	"github.com/kroderdev/pod-lifecycle-go/internal/check"
@@ -15,26 +20,214 @@ import (
 
 // Re-export config types and options for consumers.
 type (
-	CheckMechanism = config.CheckMechanism
-	Option         = config.Option
+	CheckMechanism          = config.CheckMechanism
+	CheckerDeadlineStrategy = config.CheckerDeadlineStrategy
+	Option                  = config.Option
+	Config                  = config.Config
+	Checker                 = check.Checker
+	CheckerFunc             = check.Func
+	Clock                   = check.Clock
+	Ticker                  = check.Ticker
+	Timer                   = check.Timer
+	ShutdownMetricsRecorder = config.ShutdownMetricsRecorder
+	TransitionEvent         = config.TransitionEvent
+	ProbeConfig             = config.ProbeConfig
+	RedisPinger             = check.RedisPinger
+	RedisPingFunc           = check.RedisPingFunc
+	DetailedChecker         = check.DetailedChecker
+	CheckResult             = check.CheckResult
+	CheckStatus             = check.CheckStatus
 )
 
 const (
 	CheckHTTP = config.CheckHTTP
 	CheckGRPC = config.CheckGRPC
+
+	CheckerDeadlineMin   = config.CheckerDeadlineMin
+	CheckerDeadlineFixed = config.CheckerDeadlineFixed
+
+	// NotReadyReasonHeader names the header that explains a failing /ready.
+	NotReadyReasonHeader = check.NotReadyReasonHeader
+	// LoadHeader names the header that carries "load/limit" from WithLoadGate.
+	LoadHeader = check.LoadHeader
+	// StartupProgressHeader names the header that carries "done/total" from
+	// ReportStartupProgress on /startup.
+	StartupProgressHeader = check.StartupProgressHeader
+
+	// DetailedChecker outcomes.
+	StatusOK    = check.StatusOK
+	StatusWarn  = check.StatusWarn
+	StatusError = check.StatusError
+)
+
+var (
+	WithCheckMechanism                 = config.WithCheckMechanism
+	WithHTTPPort                       = config.WithHTTPPort
+	WithGRPCPort                       = config.WithGRPCPort
+	WithShutdownTimeout                = config.WithShutdownTimeout
+	WithGRPCShutdownTimeout            = config.WithGRPCShutdownTimeout
+	WithCheckerTimeout                 = config.WithCheckerTimeout
+	WithErrorHandler                   = config.WithErrorHandler
+	WithExistingGRPCServer             = config.WithExistingGRPCServer
+	WithExistingHTTPMux                = config.WithExistingHTTPMux
+	WithDrainProgress                  = config.WithDrainProgress
+	WithUniformJSONBodies              = config.WithUniformJSONBodies
+	WithPprof                          = config.WithPprof
+	WithBindRetry                      = config.WithBindRetry
+	WithReuseAddr                      = config.WithReuseAddr
+	WithReadHeaderTimeout              = config.WithReadHeaderTimeout
+	WithReadyResponseWriter            = config.WithReadyResponseWriter
+	WithGRPCListener                   = config.WithGRPCListener
+	WithMinUptime                      = config.WithMinUptime
+	WithVersionHeader                  = config.WithVersionHeader
+	WithRequireCheckers                = config.WithRequireCheckers
+	WithEarlySignalHandling            = config.WithEarlySignalHandling
+	WithManagedGRPCServer              = config.WithManagedGRPCServer
+	WithReadyPort                      = config.WithReadyPort
+	WithLivePort                       = config.WithLivePort
+	WithGRPCReflection                 = config.WithGRPCReflection
+	WithGRPCChannelz                   = config.WithGRPCChannelz
+	WithMaxCheckerErrorLen             = config.WithMaxCheckerErrorLen
+	WithReadyRequiresStarted           = config.WithReadyRequiresStarted
+	WithReadyRequiresAppListening      = config.WithReadyRequiresAppListening
+	WithPingEndpoint                   = config.WithPingEndpoint
+	WithUnhealthyStatusCode            = config.WithUnhealthyStatusCode
+	WithConfirmNotReady                = config.WithConfirmNotReady
+	WithGRPCMaxConcurrentStreams       = config.WithGRPCMaxConcurrentStreams
+	WithGRPCMaxRecvMsgSize             = config.WithGRPCMaxRecvMsgSize
+	WithReadinessGate                  = config.WithReadinessGate
+	WithGRPCStartupShutdownGrace       = config.WithGRPCStartupShutdownGrace
+	WithHTTPListener                   = config.WithHTTPListener
+	WithClock                          = config.WithClock
+	WithReadinessFailureTolerance      = config.WithReadinessFailureTolerance
+	WithStatusEndpoint                 = config.WithStatusEndpoint
+	WithSignalAction                   = config.WithSignalAction
+	WithShutdownMetrics                = config.WithShutdownMetrics
+	WithTransitionAudit                = config.WithTransitionAudit
+	WithProbePathPrefix                = config.WithProbePathPrefix
+	WithHealthJSONFormat               = config.WithHealthJSONFormat
+	WithLivenessIgnoresShutdown        = config.WithLivenessIgnoresShutdown
+	WithCheckerFailureHandler          = config.WithCheckerFailureHandler
+	WithCheckerRecoveryHandler         = config.WithCheckerRecoveryHandler
+	WithLoadGate                       = config.WithLoadGate
+	WithListenConfig                   = config.WithListenConfig
+	WithRootHandler                    = config.WithRootHandler
+	WithReadinessFailureHandler        = config.WithReadinessFailureHandler
+	WithProbeAccessLog                 = config.WithProbeAccessLog
+	WithFailureBody                    = config.WithFailureBody
+	WithFailureBodyFunc                = config.WithFailureBodyFunc
+	WithStartupCheckers                = config.WithStartupCheckers
+	WithTextMetricsEndpoint            = config.WithTextMetricsEndpoint
+	WithInternalProbe                  = config.WithInternalProbe
+	WithReadyBodyOnFailureOnly         = config.WithReadyBodyOnFailureOnly
+	WithStartupRequiresSetStarted      = config.WithStartupRequiresSetStarted
+	WithHTTPMiddleware                 = config.WithHTTPMiddleware
+	WithReadinessFile                  = config.WithReadinessFile
+	WithResultMarshaler                = config.WithResultMarshaler
+	WithSelfTerminateOnLivenessFailure = config.WithSelfTerminateOnLivenessFailure
+	WithRunCheckersDuringShutdown      = config.WithRunCheckersDuringShutdown
+	WithProbeFailureLogging            = config.WithProbeFailureLogging
+	WithBindAddress                    = config.WithBindAddress
+	WithDetachedCheckerContext         = config.WithDetachedCheckerContext
+	WithFailOnWarn                     = config.WithFailOnWarn
+	WithReadinessGateUpdater           = config.WithReadinessGateUpdater
+	WithReadinessDecider               = config.WithReadinessDecider
+	WithDeferredClose                  = config.WithDeferredClose
+	WithLogConfigAtStartup             = config.WithLogConfigAtStartup
+	WithRecoveryHoldDown               = config.WithRecoveryHoldDown
+	WithLivenessDrainDelay             = config.WithLivenessDrainDelay
+	WithProbeCompression               = config.WithProbeCompression
+	WithForceCloseOnTimeout            = config.WithForceCloseOnTimeout
+	WithReservedCheckerNameWarnings    = config.WithReservedCheckerNameWarnings
+	WithHTTP2Cleartext                 = config.WithHTTP2Cleartext
+	DefaultConfig                      = config.DefaultConfig
+	WithReadyRateLimit                 = config.WithReadyRateLimit
+	WithCheckerDependency              = config.WithCheckerDependency
+	WithCheckerDeadlineStrategy        = config.WithCheckerDeadlineStrategy
+	WithReadinessWebhook               = config.WithReadinessWebhook
+	WithNoProbe                        = config.WithNoProbe
+	WithVerboseFieldNames              = config.WithVerboseFieldNames
+	WithStartupCheckerSelfTest         = config.WithStartupCheckerSelfTest
 )
 
+// Built-in checkers.
+var (
+	NewMemoryChecker         = check.NewMemoryChecker
+	Quorum                   = check.Quorum
+	NewCommandChecker        = check.NewCommandChecker
+	Cached                   = check.Cached
+	NewGRPCHealthChecker     = check.NewGRPCHealthChecker
+	NewHealthServerChecker   = check.NewHealthServerChecker
+	NewFileContentChecker    = check.NewFileContentChecker
+	NewCgroupMemoryChecker   = check.NewCgroupMemoryChecker
+	NewFDUsageChecker        = check.NewFDUsageChecker
+	WithHardTimeout          = check.WithHardTimeout
+	AfterConsecutiveFailures = check.AfterConsecutiveFailures
+	NewRedisChecker          = check.NewRedisChecker
+	NewCertExpiryChecker     = check.NewCertExpiryChecker
+	NewClockSkewChecker      = check.NewClockSkewChecker
+)
+
+// ErrUnknownChecker is returned by CheckerStatus for unregistered names.
+var ErrUnknownChecker = check.ErrUnknownChecker
+
+// ErrCheckerWarning is matched (via errors.Is) by the error CheckerStatus
+// returns for a passing checker whose last run reported StatusWarn.
+var ErrCheckerWarning = errors.New("checker warning")
+
+// ErrPatternConflict is returned by Start when a probe path cannot be
+// registered on the WithExistingHTTPMux mux. None of the probe paths are
+// registered then.
+var ErrPatternConflict = check.ErrPatternConflict
+
+// ErrAlreadyStarted is returned by Start and StartContext when the
+// PodManager has already been started.
+var ErrAlreadyStarted = errors.New("pod manager already started")
+
+// ErrNotGRPCProbe is returned by SetGRPCServiceStatus when the probes are
+// served over HTTP.
+var ErrNotGRPCProbe = errors.New("probes are not served over gRPC")
+
+// ErrReservedService is returned by SetGRPCServiceStatus for the built-in
+// "ready", "live", and "startup" services.
+var ErrReservedService = check.ErrReservedService
+
+// ErrLivenessFailure is matched (via errors.Is) by the error Start and
+// StartContext return after WithSelfTerminateOnLivenessFailure shut the
+// manager down because a supervised worker exited.
+var ErrLivenessFailure = errors.New("liveness failure")
+
+// ErrForcedShutdown is matched (via errors.Is) by the *ShutdownError that
+// StartContext returns when the shutdown timeout expired with probe requests
+// or RPCs still in flight.
+var ErrForcedShutdown = errors.New("probe server force-stopped after shutdown timeout")
+
+// ShutdownError reports a shutdown that did not complete cleanly. Report
+// holds the details; use errors.As to retrieve it.
+type ShutdownError struct {
+	Report ShutdownReport
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("%v (after %v)", ErrForcedShutdown, e.Report.Duration)
+}
+
+func (e *ShutdownError) Unwrap() error { return ErrForcedShutdown }
+
+// Configuration errors returned (wrapped) by NewPodManager; match them with
+// errors.Is.
 var (
-	WithCheckMechanism     = config.WithCheckMechanism
-	WithHTTPPort           = config.WithHTTPPort
-	WithGRPCPort           = config.WithGRPCPort
-	WithShutdownTimeout    = config.WithShutdownTimeout
-	WithCheckerTimeout     = config.WithCheckerTimeout
-	WithErrorHandler       = config.WithErrorHandler
-	WithExistingGRPCServer = config.WithExistingGRPCServer
-	WithExistingHTTPMux    = config.WithExistingHTTPMux
+	ErrInvalidPort        = config.ErrInvalidPort
+	ErrConflictingOptions = config.ErrConflictingOptions
+	ErrInvalidOption      = config.ErrInvalidOption
 )
 
+// OptionsFromEnv returns options read from the POD_LIFECYCLE_* environment
+// variables (see the README for the list). Unset variables are skipped.
+func OptionsFromEnv() ([]Option, error) {
+	return config.OptionsFromEnv(os.LookupEnv)
+}
+
 // WithChecker registers a named dependency checker run on every /ready request.
 func WithChecker(name string, c check.Checker) Option {
 	return config.WithChecker(name, c)
@@ -42,18 +235,111 @@ func WithChecker(name string, c check.Checker) Option {
 
 // PodManager coordinates pod lifecycle: signals, readiness, liveness, and startup probes.
 type PodManager struct {
-	ready           atomic.Bool
-	shuttingDown    atomic.Bool
-	started         atomic.Bool
-	probe           check.Server
-	shutdownTimeout time.Duration
-	shutdownOnce    sync.Once
+	ready                atomic.Bool
+	shuttingDown         atomic.Bool
+	draining             atomic.Bool // between BeginDrain and CancelDrain; /ready fails, probes stay up
+	paused               atomic.Bool // between Pause and Resume; /ready fails, the ready flag is kept
+	started              atomic.Bool
+	appListening         atomic.Bool // SetAppListening called
+	appStarted           atomic.Bool // SetStarted called
+	serving              atomic.Bool
+	runState             atomic.Int32 // runIdle → runStarting → runServing → runStopped
+	workerFailed         atomic.Bool  // a supervised worker exited before shutdown
+	livenessDrained      atomic.Bool  // WithLivenessDrainDelay has passed during shutdown
+	livenessDrainDelay   time.Duration
+	selfTerminate        bool
+	livenessErr          atomic.Pointer[error]  // set when a liveness failure began shutdown
+	startedAt            atomic.Int64           // UnixNano; zero until the probe starts
+	readySince           atomic.Int64           // UnixNano; zero while not ready
+	startupProgress      atomic.Pointer[[2]int] // done, total from ReportStartupProgress
+	startupChecksPassed  atomic.Bool            // every WithStartupCheckers checker has passed once
+	startupCheckers      []string
+	checkerTimeout       time.Duration
+	probe                check.Server
+	checkers             *check.Registry
+	shutdownTimeout      time.Duration
+	minUptime            time.Duration
+	readyRequiresStarted bool
+	readyRequiresListen  bool
+	startupRequiresSet   bool
+	readinessGates       []func() bool
+	clock                check.Clock
+	signalActions        map[os.Signal]SignalAction
+	shutdownMetrics      ShutdownMetricsRecorder
+	transitionAudit      func(TransitionEvent)
+	probePaths           map[string]bool // HTTP probe endpoints DrainMiddleware passes through
+	hooksMu              sync.Mutex
+	reloadHooks          []func()
+	drainHooks           []func()
+	drainMu              sync.Mutex
+	drainStarting        bool         // BeginDrain is running pre-drain hooks; guarded by drainMu
+	drainEndedAt         atomic.Int64 // UnixNano of the last CancelDrain
+	recoveryHoldDown     time.Duration
+	preDrainHooks        []func()
+	preDrainMu           sync.Mutex // held while pre-drain hooks run
+	preDrainRan          bool       // guarded by preDrainMu; cleared by CancelDrain
+	errorHandler         func(error)
+	closersMu            sync.Mutex
+	connClosers          []func()
+	deferredClosers      []io.Closer
+	closeErr             error // from the deferred closers; set before doneCh closes
+	configLog            *slog.Logger
+	reservedNameLog      *slog.Logger
+	selfTestLog          *slog.Logger
+	configAttrs          []slog.Attr
+	shutdownOnce         sync.Once
+	shutdownCh           chan struct{} // closed when shutdown begins
+	doneCh               chan struct{} // closed when shutdown has completed
+	shutdownReport       atomic.Pointer[ShutdownReport]
+	forcedStop           atomic.Bool // a probe server was stopped forcibly
+	budgetMu             sync.Mutex
+	budgetTimer          check.Timer // running only while shutdown is in progress
+	budgetDeadline       time.Time
+	budgetForced         bool // a second shutdown signal arrived; the budget expires at once
+	readyOnce            sync.Once
+	readyCh              chan struct{} // closed by the first SetReady
+	startErrOnce         sync.Once
+	events               chan LifecycleEvent
+	readinessFile        string
+	readyFileMu          sync.Mutex
+	readyFileContent     string // last content written to readinessFile
+	readyFileRemoved     bool
+	readinessUpdater     func(ready bool) error
+	updaterMu            sync.Mutex
+	updaterPublished     bool // updaterReady has been delivered
+	updaterReady         bool
+	updaterFailures      int         // failed updates since the last success
+	updaterRetry         check.Timer // pending retry of a failed update
+	webhook              *webhook    // nil without WithReadinessWebhook
+	eventsMu             sync.Mutex
+	eventsClosed         bool
+	droppedEvents        atomic.Uint64
+	errCh                chan error // receives the first start error, then closed
+
+	// Shutdown waits for confirmNotReady not-ready /ready responses, counted
+	// in notReadyServed, before stopping the probe.
+	confirmNotReady   int64
+	notReadyServed    atomic.Int64
+	notReadyOnce      sync.Once
+	notReadyConfirmed chan struct{}
+
+	// Background loops derive from bgCtx, which shutdown cancels before
+	// waiting (bounded by the shutdown timeout) on bgWG.
+	bgMu     sync.Mutex
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
+	bgWG     sync.WaitGroup
 }
 
 func (pm *PodManager) Ready() bool        { return pm.ready.Load() }
 func (pm *PodManager) ShuttingDown() bool { return pm.shuttingDown.Load() }
 func (pm *PodManager) Started() bool      { return pm.started.Load() }
 
+// IsServing reports whether the probe has bound its listener and has not yet
+// been shut down. Unlike Started, it describes the probe server only, not
+// application startup.
+func (pm *PodManager) IsServing() bool { return pm.serving.Load() }
+
 // NewPodManager creates a PodManager with the given options.
 // Returns an error if configuration is invalid (e.g. port out of range).
 func NewPodManager(opts ...Option) (*PodManager, error) {
@@ -61,16 +347,195 @@ func NewPodManager(opts ...Option) (*PodManager, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &PodManager{
-		probe:           config.NewProbe(cfg),
-		shutdownTimeout: cfg.ShutdownTimeout,
-	}, nil
+	return newPodManager(cfg), nil
+}
+
+// NewPodManagerFromConfig creates a PodManager from a Config built directly,
+// e.g. by the application's own configuration layer, instead of options.
+// Start from DefaultConfig: the zero Config is invalid (port 0, no clock).
+// cfg is validated as NewPodManager validates options, and a port or
+// mechanism that differs from the default counts as explicitly set.
+func NewPodManagerFromConfig(cfg Config) (*PodManager, error) {
+	cfg, err := config.FromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newPodManager(cfg), nil
+}
+
+// newPodManager builds a PodManager from a validated Config.
+func newPodManager(cfg config.Config) *PodManager {
+	checkers := check.NewRegistry(cfg.Checkers)
+	pm := &PodManager{
+		checkers:             checkers,
+		shutdownTimeout:      cfg.ProbeShutdownTimeout(),
+		minUptime:            cfg.MinUptime,
+		recoveryHoldDown:     cfg.RecoveryHoldDown,
+		livenessDrainDelay:   cfg.LivenessDrainDelay,
+		reservedNameLog:      cfg.ReservedCheckerNameLog,
+		selfTestLog:          cfg.CheckerSelfTestLog,
+		readyRequiresStarted: cfg.ReadyRequiresStarted,
+		readyRequiresListen:  cfg.ReadyRequiresAppListening,
+		startupRequiresSet:   cfg.StartupRequiresSetStarted,
+		startupCheckers:      cfg.StartupCheckers,
+		checkerTimeout:       cfg.CheckerTimeout,
+		errorHandler:         cfg.ErrorHandler,
+		readinessGates:       cfg.ReadinessGates,
+		clock:                cfg.Clock,
+		signalActions:        cfg.SignalActions,
+		shutdownMetrics:      cfg.ShutdownMetrics,
+		transitionAudit:      cfg.TransitionAudit,
+		probePaths:           probePaths(cfg),
+		shutdownCh:           make(chan struct{}),
+		doneCh:               make(chan struct{}),
+		readyCh:              make(chan struct{}),
+		errCh:                make(chan error, 1),
+		events:               make(chan LifecycleEvent, eventBuffer),
+		readinessFile:        cfg.ReadinessFile,
+		deferredClosers:      cfg.DeferredClosers,
+		configLog:            cfg.ConfigLog,
+		readinessUpdater:     cfg.ReadinessGateUpdater,
+		webhook:              newWebhook(cfg.ReadinessWebhook),
+		selfTerminate:        cfg.SelfTerminateOnLivenessFailure,
+		confirmNotReady:      int64(cfg.ConfirmNotReady),
+		notReadyConfirmed:    make(chan struct{}),
+	}
+	pm.startupChecksPassed.Store(len(pm.startupCheckers) == 0)
+	hooks := config.Hooks{OnForcedStop: func() { pm.forcedStop.Store(true) }}
+	if pm.confirmNotReady > 0 {
+		hooks.OnReadyServed = pm.readyServed
+	}
+	if cfg.StatusPath != "" {
+		hooks.Status = func() any { return pm.Status() }
+	}
+	if cfg.MetricsPath != "" {
+		hooks.Metrics = pm.textMetrics
+	}
+	pm.bgCtx, pm.bgCancel = context.WithCancel(context.Background())
+	// Shutdown cancels bgCtx, which also abandons a bind still retrying.
+	hooks.ListenContext = pm.bgCtx
+	pm.probe = config.NewProbe(cfg, checkers, hooks)
+	if cfg.ConfigLog != nil {
+		pm.configAttrs = cfg.LogAttrs()
+	}
+	if n := checkers.Len(); n > manyCheckers {
+		pm.warnManyCheckers(n)
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Checkers)) {
+		pm.warnReservedCheckerName(name)
+	}
+	if cfg.EarlySignalHandling {
+		pm.handleEarlySignals()
+	}
+	return pm
 }
 
 // SetReady marks the pod as ready. Call once your app has finished startup.
+// Calling it after Shutdown is safe; the probes keep reporting not ready.
 func (pm *PodManager) SetReady() {
-	pm.ready.Store(true)
-	pm.probe.SetState(true, pm.shuttingDown.Load())
+	pm.transition("ready", &pm.ready, true)
+	pm.readyOnce.Do(func() { close(pm.readyCh) })
+	pm.syncProbe()
+}
+
+// SetAppListening records that the application's own server is accepting
+// connections. With WithReadyRequiresAppListening, readiness waits for it.
+func (pm *PodManager) SetAppListening() {
+	pm.transition("appListening", &pm.appListening, true)
+	pm.syncProbe()
+}
+
+// IsAppListening reports whether SetAppListening has been called.
+func (pm *PodManager) IsAppListening() bool { return pm.appListening.Load() }
+
+// SetStarted records that the application finished initializing. With
+// WithStartupRequiresSetStarted, the startup probe waits for it.
+func (pm *PodManager) SetStarted() {
+	pm.transition("appStarted", &pm.appStarted, true)
+	pm.syncProbe()
+}
+
+// SetGRPCServiceStatus reports serving for an application-defined service
+// (e.g. "myapp.v1.Orders") on the probe's gRPC health server, next to ready,
+// live, and startup. It may be called before Start. It returns
+// ErrNotGRPCProbe for HTTP probes and ErrReservedService for the built-in
+// service names.
+func (pm *PodManager) SetGRPCServiceStatus(service string, serving bool) error {
+	s, ok := pm.probe.(check.ServiceStatusSetter)
+	if !ok {
+		return ErrNotGRPCProbe
+	}
+	return s.SetServiceStatus(service, serving)
+}
+
+// GRPCStatuses returns the status last applied to each service of the gRPC
+// probe's health server, "SERVING" or "NOT_SERVING", keyed by service name:
+// ready, live, startup, and those set with SetGRPCServiceStatus. It reads
+// the probe's own record, without an RPC, for debug endpoints and tests. It
+// returns nil for HTTP probes and before a standalone gRPC probe starts.
+func (pm *PodManager) GRPCStatuses() map[string]string {
+	r, ok := pm.probe.(check.ServiceStatusReader)
+	if !ok {
+		return nil
+	}
+	return r.ServiceStatuses()
+}
+
+// SetNotReady marks the pod as not ready again, e.g. while a dependency is
+// being reconfigured. ReadyCh stays closed.
+func (pm *PodManager) SetNotReady() {
+	pm.transition("ready", &pm.ready, false)
+	pm.syncProbe()
+}
+
+// Pause makes readiness fail (/ready 503, gRPC ready NOT_SERVING) until
+// Resume, e.g. for a maintenance window, while liveness and startup are
+// unaffected and the probe keeps serving. Unlike SetNotReady it leaves the
+// ready flag alone, so Resume returns to whatever SetReady and SetNotReady
+// last set, including calls made while paused.
+func (pm *PodManager) Pause() {
+	pm.transition("paused", &pm.paused, true)
+	pm.syncProbe()
+}
+
+// Resume ends a Pause.
+func (pm *PodManager) Resume() {
+	pm.transition("paused", &pm.paused, false)
+	pm.syncProbe()
+}
+
+// IsPaused reports whether the manager is paused.
+func (pm *PodManager) IsPaused() bool { return pm.paused.Load() }
+
+// ReadyCh returns a channel that is closed once SetReady has been called.
+func (pm *PodManager) ReadyCh() <-chan struct{} { return pm.readyCh }
+
+// ErrorCh reports the outcome of the first start attempt, for callers that
+// run Start or StartContext in a goroutine: it receives the fatal start error
+// (e.g. the probe port is in use, or StartContext's ctx was already done) and
+// is then closed, or is closed without a value once the probe is serving or a
+// pending shutdown skipped binding.
+func (pm *PodManager) ErrorCh() <-chan error { return pm.errCh }
+
+// reportStart delivers the first start outcome to ErrorCh.
+func (pm *PodManager) reportStart(err error) {
+	pm.startErrOnce.Do(func() {
+		if err != nil {
+			pm.errCh <- err
+		}
+		close(pm.errCh)
+	})
+}
+
+// WaitUntilReady blocks until SetReady has been called or ctx is done, in
+// which case it returns ctx.Err().
+func (pm *PodManager) WaitUntilReady(ctx context.Context) error {
+	select {
+	case <-pm.readyCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // IsShuttingDown returns true after a termination signal has been received.
@@ -78,43 +543,622 @@ func (pm *PodManager) IsShuttingDown() bool {
 	return pm.shuttingDown.Load()
 }
 
+// AddChecker registers c under name at runtime, replacing any checker with
+// that name. It is safe to call while the probe is serving; in-flight /ready
+// requests keep using the checker set they started with. Like WithChecker it
+// rejects a nil checker and names that are empty or contain whitespace, with
+// an error wrapping ErrInvalidOption.
+func (pm *PodManager) AddChecker(name string, c Checker) error {
+	if err := config.ValidateChecker(name, c); err != nil {
+		return err
+	}
+	pm.checkers.Add(name, c)
+	if n := pm.checkers.Len(); n == manyCheckers+1 {
+		pm.warnManyCheckers(n)
+	}
+	pm.warnReservedCheckerName(name)
+	return nil
+}
+
+// warnReservedCheckerName logs to the WithReservedCheckerNameWarnings logger
+// when name collides with a key used in probe bodies.
+func (pm *PodManager) warnReservedCheckerName(name string) {
+	if pm.reservedNameLog != nil && check.ReservedResultKey(name) {
+		pm.reservedNameLog.Warn("checker name collides with a probe body key", "checker", name)
+	}
+}
+
+// manyCheckers is the checker count above which the error handler is warned:
+// every /ready request runs each checker in its own goroutine.
+const manyCheckers = 256
+
+// warnManyCheckers reports to the error handler that n checkers are registered.
+func (pm *PodManager) warnManyCheckers(n int) {
+	if pm.errorHandler != nil {
+		pm.errorHandler(fmt.Errorf("%d checkers registered (more than %d); each /ready request runs all of them concurrently", n, manyCheckers))
+	}
+}
+
+// RemoveChecker unregisters the named checker. Unknown names are ignored.
+func (pm *PodManager) RemoveChecker(name string) {
+	pm.checkers.Remove(name)
+}
+
+// DisableChecker mutes the named checker, e.g. during planned maintenance of
+// its dependency: it stays registered but is not run, /ready reports it as
+// "disabled", and it no longer fails readiness or blocks WithStartupCheckers.
+// Unknown names are ignored. EnableChecker reverts it.
+func (pm *PodManager) DisableChecker(name string) {
+	pm.checkers.SetDisabled(name, true)
+}
+
+// EnableChecker runs the named checker again after DisableChecker.
+func (pm *PodManager) EnableChecker(name string) {
+	pm.checkers.SetDisabled(name, false)
+}
+
+// Supervise fails liveness if done is closed before shutdown begins, so a
+// crashed critical worker gets the container restarted. The error handler,
+// if any, is told which worker exited. With
+// WithSelfTerminateOnLivenessFailure it also begins shutdown. Call it before
+// or after Start; it is a no-op once shutdown has begun.
+func (pm *PodManager) Supervise(name string, done <-chan struct{}) {
+	pm.goBackground(func(ctx context.Context) {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return
+		}
+		if pm.shuttingDown.Load() {
+			return
+		}
+		pm.workerFailed.Store(true)
+		pm.syncProbe()
+		if pm.errorHandler != nil {
+			pm.errorHandler(fmt.Errorf("supervised worker %q exited", name))
+		}
+		if pm.selfTerminate {
+			err := fmt.Errorf("%w: supervised worker %q exited", ErrLivenessFailure, name)
+			pm.livenessErr.CompareAndSwap(nil, &err)
+			// shutdown waits on background goroutines, so run it outside this one.
+			go pm.shutdown()
+		}
+	})
+}
+
+// exitError returns the liveness failure that began shutdown, if any.
+func (pm *PodManager) exitError() error {
+	if err := pm.livenessErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// LastCheckResults returns the latest result of each checker that has run,
+// formatted as in the /ready body ("ok", "warn: ..." or "error: ...").
+func (pm *PodManager) LastCheckResults() map[string]string {
+	results := pm.checkers.Results()
+	out := make(map[string]string, len(results))
+	for name, r := range results {
+		switch {
+		case r.Err != nil:
+			out[name] = "error: " + r.Err.Error()
+		case r.Warning != "":
+			out[name] = "warn: " + r.Warning
+		default:
+			out[name] = "ok"
+		}
+	}
+	return out
+}
+
+// CheckerStatus returns the latest known result of the named checker: whether
+// it passed, the error it returned, and when it ran. A DetailedChecker warning
+// passes, with an err matching ErrCheckerWarning that carries its message. A
+// registered checker that has not run yet reports ok=false with a zero ts.
+// Unknown names return ErrUnknownChecker.
+func (pm *PodManager) CheckerStatus(name string) (ok bool, err error, ts time.Time) {
+	r, found := pm.checkers.Result(name)
+	if !found {
+		return false, ErrUnknownChecker, time.Time{}
+	}
+	if r.Time.IsZero() {
+		return false, nil, time.Time{}
+	}
+	if r.Err == nil && r.Warning != "" {
+		return true, fmt.Errorf("%w: %s", ErrCheckerWarning, r.Warning), r.Time
+	}
+	return r.Err == nil, r.Err, r.Time
+}
+
+// ShutdownReport summarizes a completed shutdown.
+type ShutdownReport struct {
+	Started  time.Time
+	Duration time.Duration
+	// Closers holds how long each RegisterConnCloser func ran, in order.
+	Closers []time.Duration
+	// NotReadyConfirmed is true when WithConfirmNotReady is set and enough
+	// not-ready responses were served before the timeout.
+	NotReadyConfirmed bool
+	// ForcedStop is true when the shutdown timeout expired with probe
+	// requests or RPCs still in flight; see PodManager.ForcedStop.
+	ForcedStop bool
+}
+
+// LastShutdownReport returns the report of the completed shutdown. The bool
+// is false until shutdown has finished.
+func (pm *PodManager) LastShutdownReport() (ShutdownReport, bool) {
+	r := pm.shutdownReport.Load()
+	if r == nil {
+		return ShutdownReport{}, false
+	}
+	return *r, true
+}
+
+// ForcedStop reports whether the shutdown timeout expired with probe requests
+// or RPCs still in flight: the gRPC server was stopped, dropping them, and the
+// HTTP server stopped waiting for them (or closed them, with
+// WithForceCloseOnTimeout). An expired timeout alone does not count when
+// nothing was left to drain. It is false until shutdown has run.
+func (pm *PodManager) ForcedStop() bool { return pm.forcedStop.Load() }
+
 // shutdown performs a graceful shutdown of the probe server with the configured timeout.
 // It is idempotent: the body executes exactly once regardless of how many goroutines call it.
 func (pm *PodManager) shutdown() {
 	pm.shutdownOnce.Do(func() {
-		pm.shuttingDown.Store(true)
-		pm.probe.SetState(pm.ready.Load(), true)
-		ctx, cancel := context.WithTimeout(context.Background(), pm.shutdownTimeout)
-		defer cancel()
+		report := ShutdownReport{Started: pm.clock.Now()}
+		pm.runPreDrainHooks()
+		pm.transition("shuttingDown", &pm.shuttingDown, true)
+		close(pm.shutdownCh)
+		pm.bgMu.Lock()
+		pm.bgCancel()
+		pm.bgMu.Unlock()
+		pm.syncProbe()
+		ctx, stop := pm.startShutdownBudget()
+		defer stop()
+		// A probe that never served (e.g. Close after a failed bind) has
+		// nobody scraping it, so there is nothing to wait for.
+		if pm.runState.Load() == runServing {
+			report.NotReadyConfirmed = pm.awaitNotReadyConfirmed(ctx)
+			pm.drainLiveness(ctx, report.Started)
+		}
+		report.Closers = pm.runConnClosers()
 		pm.probe.Shutdown(ctx)
+		report.ForcedStop = pm.forcedStop.Load()
+		pm.serving.Store(false)
+		pm.waitBackground(ctx)
+		report.Duration = pm.clock.Now().Sub(report.Started)
+		pm.shutdownReport.Store(&report)
+		if pm.shutdownMetrics != nil {
+			pm.shutdownMetrics.ObserveShutdownDuration(report.Duration)
+		}
+		pm.emit(EventShutdownComplete, pm.clock.Now())
+		pm.runDeferredClosers()
+		close(pm.doneCh)
 	})
 }
 
+// drainLiveness waits until WithLivenessDrainDelay has passed since readiness
+// failed at since, or ctx is done, then fails liveness.
+func (pm *PodManager) drainLiveness(ctx context.Context, since time.Time) {
+	if pm.livenessDrainDelay <= 0 {
+		return
+	}
+	if wait := pm.livenessDrainDelay - pm.clock.Now().Sub(since); wait > 0 {
+		select {
+		case <-pm.clock.After(wait):
+		case <-ctx.Done():
+		}
+	}
+	pm.livenessDrained.Store(true)
+	pm.syncProbe()
+}
+
+// Done returns a channel that is closed once shutdown has fully completed:
+// the probe server has stopped and connection closers and background loops
+// have finished (or the shutdown timeout expired). It works however shutdown
+// was triggered: a signal, a cancelled context, or Shutdown.
+func (pm *PodManager) Done() <-chan struct{} { return pm.doneCh }
+
+// Wait blocks until shutdown has fully completed; see Done.
+func (pm *PodManager) Wait() { <-pm.doneCh }
+
+// startShutdownBudget returns a context that expires after the shutdown
+// timeout, as extended by ExtendShutdown, and a func that releases it.
+func (pm *PodManager) startShutdownBudget() (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	pm.budgetMu.Lock()
+	timeout := pm.shutdownTimeout
+	if pm.budgetForced {
+		timeout = 0
+	}
+	pm.budgetDeadline = pm.clock.Now().Add(timeout)
+	pm.budgetTimer = pm.clock.AfterFunc(timeout, func() { cancel(context.DeadlineExceeded) })
+	pm.budgetMu.Unlock()
+	return ctx, func() {
+		pm.budgetMu.Lock()
+		pm.budgetTimer.Stop()
+		pm.budgetTimer = nil
+		pm.budgetMu.Unlock()
+		cancel(nil)
+	}
+}
+
+// ExtendShutdown pushes the deadline of the shutdown in progress out by d,
+// e.g. while a large queue is still draining, so the probe server is not
+// force-stopped yet. It reports whether the deadline was extended: it returns
+// false before shutdown has begun, once it has finished, and once its
+// deadline has already passed.
+func (pm *PodManager) ExtendShutdown(d time.Duration) bool {
+	pm.budgetMu.Lock()
+	defer pm.budgetMu.Unlock()
+	if pm.budgetTimer == nil || !pm.budgetTimer.Stop() {
+		return false
+	}
+	pm.budgetDeadline = pm.budgetDeadline.Add(d)
+	pm.budgetTimer.Reset(pm.budgetDeadline.Sub(pm.clock.Now()))
+	return true
+}
+
+// budgetRemaining returns how much of the shutdown budget is left, as
+// extended by ExtendShutdown; it is negative once the deadline has passed.
+func (pm *PodManager) budgetRemaining() time.Duration {
+	pm.budgetMu.Lock()
+	defer pm.budgetMu.Unlock()
+	return pm.budgetDeadline.Sub(pm.clock.Now())
+}
+
+// forceShutdown expires the shutdown budget now, so the drain in progress
+// stops waiting and the probe server is force-stopped. Called before the
+// budget starts, it makes the budget expire as soon as it does.
+func (pm *PodManager) forceShutdown() {
+	pm.budgetMu.Lock()
+	defer pm.budgetMu.Unlock()
+	pm.budgetForced = true
+	if pm.budgetTimer != nil {
+		pm.budgetTimer.Reset(0)
+	}
+}
+
+// RegisterConnCloser registers fn to run during shutdown, after the pod has
+// been marked not-ready and before the probe server is stopped. Use it to
+// close idle long-lived connections (e.g. WebSockets) on a shared server that
+// would otherwise hold up the app's own http.Server.Shutdown. Closers run in
+// registration order and should return promptly.
+func (pm *PodManager) RegisterConnCloser(fn func()) {
+	pm.closersMu.Lock()
+	pm.connClosers = append(pm.connClosers, fn)
+	pm.closersMu.Unlock()
+}
+
+// runConnClosers runs the registered closers and returns how long each took.
+func (pm *PodManager) runConnClosers() []time.Duration {
+	pm.closersMu.Lock()
+	closers := pm.connClosers
+	pm.closersMu.Unlock()
+	durations := make([]time.Duration, len(closers))
+	for i, fn := range closers {
+		start := pm.clock.Now()
+		fn()
+		durations[i] = pm.clock.Now().Sub(start)
+	}
+	return durations
+}
+
+// runDeferredClosers closes the WithDeferredClose closers in reverse order
+// and records their errors for Close.
+func (pm *PodManager) runDeferredClosers() {
+	var errs []error
+	for i := len(pm.deferredClosers) - 1; i >= 0; i-- {
+		if err := pm.deferredClosers[i].Close(); err != nil {
+			err = fmt.Errorf("deferred close: %w", err)
+			errs = append(errs, err)
+			if pm.errorHandler != nil {
+				pm.errorHandler(err)
+			}
+		}
+	}
+	pm.closeErr = errors.Join(errs...)
+}
+
+// Close shuts the manager down, unless that has already happened, and waits
+// for shutdown to complete. A failed Start leaves the manager idle so Start
+// can be retried, without running shutdown hooks or WithDeferredClose
+// closers and with background goroutines still running; defer Close right
+// after NewPodManager so they are released whichever way Start ends. When
+// the probe never served, Close skips the WithConfirmNotReady and
+// WithLivenessDrainDelay waits. Start returns without binding once Close has
+// run. Close returns the WithDeferredClose errors.
+func (pm *PodManager) Close() error {
+	pm.shutdown()
+	<-pm.doneCh
+	return pm.closeErr
+}
+
+// readyServed counts not-ready /ready responses served during shutdown.
+func (pm *PodManager) readyServed(ok bool) {
+	if ok || !pm.shuttingDown.Load() {
+		return
+	}
+	if pm.notReadyServed.Add(1) >= pm.confirmNotReady {
+		pm.notReadyOnce.Do(func() { close(pm.notReadyConfirmed) })
+	}
+}
+
+// awaitNotReadyConfirmed blocks until the configured number of not-ready
+// responses has been served, half the remaining shutdown budget has passed,
+// or ctx is done, and reports whether they were served. The other half is
+// left for the probe server to drain. It returns false at once when
+// WithConfirmNotReady is not set.
+func (pm *PodManager) awaitNotReadyConfirmed(ctx context.Context) bool {
+	if pm.confirmNotReady <= 0 {
+		return false
+	}
+	select {
+	case <-pm.notReadyConfirmed:
+		return true
+	case <-pm.clock.After(pm.budgetRemaining() / 2):
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// goBackground runs fn in a goroutine tracked by shutdown. fn must return
+// promptly once ctx is done. It is a no-op once shutdown has begun.
+func (pm *PodManager) goBackground(fn func(ctx context.Context)) {
+	pm.bgMu.Lock()
+	defer pm.bgMu.Unlock()
+	if pm.bgCtx.Err() != nil {
+		return
+	}
+	pm.bgWG.Add(1)
+	go func() {
+		defer pm.bgWG.Done()
+		fn(pm.bgCtx)
+	}()
+}
+
+// waitBackground waits for background goroutines to exit or ctx to expire.
+func (pm *PodManager) waitBackground(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		pm.bgWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
 // Shutdown triggers a graceful shutdown of the probe server. It is safe to call
 // concurrently and from multiple goroutines; the shutdown logic executes exactly once.
 func (pm *PodManager) Shutdown() { pm.shutdown() }
 
-// Start starts the probe server and blocks until SIGTERM or SIGINT.
+// onStarted is called by the probe once its listener is up.
+func (pm *PodManager) onStarted() {
+	pm.startedAt.Store(pm.clock.Now().UnixNano())
+	pm.serving.Store(true)
+	// The transition audit callback is user code running inside the probe's
+	// Start; a panic there must not unwind Start with the server half up.
+	pm.recoverToErrorHandler("started transition", func() {
+		pm.transition("started", &pm.started, true)
+	})
+	pm.trackReadySince()
+	pm.writeReadinessFile()
+	pm.publishReadiness()
+	pm.publishWebhook()
+	if !pm.startupChecksPassed.Load() {
+		pm.goBackground(pm.runStartupCheckers)
+	}
+	if pm.selfTestLog != nil {
+		pm.goBackground(pm.selfTestCheckers)
+	}
+	if pm.minUptime > 0 {
+		// Push-based probes (gRPC) need a nudge once the uptime gate opens.
+		pm.goBackground(func(ctx context.Context) {
+			select {
+			case <-pm.clock.After(pm.minUptime):
+				pm.syncProbe()
+			case <-ctx.Done():
+			}
+		})
+	}
+}
+
+// Run states of a PodManager, advanced by Start/StartContext.
+const (
+	runIdle int32 = iota
+	runStarting
+	runServing
+	runStopped
+)
+
+// recoverToErrorHandler runs fn and reports a panic in it to the error
+// handler, if any, instead of propagating it.
+func (pm *PodManager) recoverToErrorHandler(what string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil && pm.errorHandler != nil {
+			pm.errorHandler(fmt.Errorf("%s panicked: %v", what, r))
+		}
+	}()
+	fn()
+}
+
+// startProbe moves the manager from idle to serving. It returns false with a
+// nil error when shutdown was requested before Start, after completing it.
+// A failed bind returns the manager to idle so Start can be retried.
+func (pm *PodManager) startProbe() (bool, error) {
+	if !pm.runState.CompareAndSwap(runIdle, runStarting) {
+		return false, ErrAlreadyStarted
+	}
+	if pm.shuttingDown.Load() {
+		pm.shutdown()
+		pm.runState.Store(runStopped)
+		pm.reportStart(nil)
+		return false, nil
+	}
+	if err := pm.probe.Start(probeState{pm}, pm.onStarted); err != nil {
+		pm.runState.Store(runIdle)
+		pm.reportStart(err)
+		return false, err
+	}
+	pm.runState.Store(runServing)
+	pm.logConfig()
+	pm.reportStart(nil)
+	return true, nil
+}
+
+// logConfig writes the WithLogConfigAtStartup line, with the checker names
+// registered at this point.
+func (pm *PodManager) logConfig() {
+	if pm.configLog == nil {
+		return
+	}
+	names := slices.Sorted(maps.Keys(pm.checkers.Snapshot()))
+	attrs := append(slices.Clip(pm.configAttrs), slog.Any("checkers", names))
+	pm.configLog.LogAttrs(context.Background(), slog.LevelInfo, "pod lifecycle config", attrs...)
+}
+
+// Start starts the probe server and blocks until a shutdown signal (SIGTERM
+// or SIGINT unless remapped with WithSignalAction) arrives, or until Shutdown
+// is called. Signals mapped to other actions are handled while it waits. A
+// second shutdown signal during the drain force-stops the probe server
+// instead of waiting out the shutdown timeout. A failed bind returns the
+// error without shutting down; see Close. If shutdown was already requested
+// (e.g. by an early signal), Start waits for it to finish and returns
+// without binding. A PodManager can be started once; later calls return
+// ErrAlreadyStarted. When WithSelfTerminateOnLivenessFailure began the
+// shutdown, Start returns an error matching ErrLivenessFailure.
 func (pm *PodManager) Start() error {
-	if err := pm.probe.Start(pm, func() { pm.started.Store(true) }); err != nil {
+	if ok, err := pm.startProbe(); !ok {
+		return err
+	}
+	pm.serveUntilShutdown(pm.notifySignals())
+	return pm.exitError()
+}
+
+// StartAsync is like Start but returns as soon as the probe listeners are
+// bound, which is enough for requests to succeed: connections queue on the
+// bound socket until the server accepts them. Signals and Shutdown then shut
+// the manager down in the background; use Done or Wait to observe the end.
+func (pm *PodManager) StartAsync() error {
+	if ok, err := pm.startProbe(); !ok {
 		return err
 	}
+	go pm.serveUntilShutdown(pm.notifySignals())
+	return nil
+}
+
+// notifySignals subscribes to every mapped signal. signalActions always holds
+// at least the SIGTERM/SIGINT entries, so Notify never sees an empty list
+// (which would relay every signal).
+func (pm *PodManager) notifySignals() chan os.Signal {
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
-	<-sigCh
-	signal.Stop(sigCh)
+	signal.Notify(sigCh, pm.signalsFor()...)
+	return sigCh
+}
+
+// serveUntilShutdown dispatches signals from sigCh until one of them, or
+// Shutdown, begins shutdown, then completes it. Shutdown signals keep being
+// received meanwhile: a second one cuts the drain short, as when an operator
+// presses Ctrl-C twice.
+func (pm *PodManager) serveUntilShutdown(sigCh chan os.Signal) {
+	signals := 0
+wait:
+	for {
+		select {
+		case sig := <-sigCh:
+			if pm.dispatchSignal(sig) {
+				signals++
+				break wait
+			}
+		case <-pm.shutdownCh:
+			break wait
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case sig := <-sigCh:
+				if a, ok := pm.signalActions[sig]; ok && a.Kind == config.ActionShutdown {
+					if signals++; signals >= 2 {
+						pm.forceShutdown()
+						return
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
 	pm.shutdown()
-	return nil
+	close(done)
+	pm.runState.Store(runStopped)
 }
 
-// StartContext is like Start but returns when ctx is cancelled.
+// StartContext is like Start but returns when ctx is cancelled instead of on
+// a signal. It returns nil once shutdown completes within the shutdown
+// timeout, and a *ShutdownError when the timeout expired with probe requests
+// still in flight (see ForcedStop). A shutdown begun by
+// WithSelfTerminateOnLivenessFailure yields an error matching
+// ErrLivenessFailure. If ctx is already done before the probe binds, it
+// returns ctx.Err() without binding.
 func (pm *PodManager) StartContext(ctx context.Context) error {
-	if err := pm.probe.Start(pm, func() { pm.started.Store(true) }); err != nil {
+	if err := ctx.Err(); err != nil {
+		pm.reportStart(err)
+		return err
+	}
+	if ok, err := pm.startProbe(); !ok {
 		return err
 	}
-	<-ctx.Done()
+	select {
+	case <-ctx.Done():
+	case <-pm.shutdownCh:
+	}
 	pm.shutdown()
-	return ctx.Err()
+	pm.runState.Store(runStopped)
+	err := pm.exitError()
+	if report, ok := pm.LastShutdownReport(); ok && report.ForcedStop {
+		if err != nil {
+			return errors.Join(err, &ShutdownError{Report: report})
+		}
+		return &ShutdownError{Report: report}
+	}
+	return err
+}
+
+// RunFunc adapts the manager to run groups such as oklog/run and errgroup.
+// execute behaves like StartContext(ctx); interrupt shuts the manager down so
+// execute returns, whatever error stopped the group. interrupt may be called
+// before execute starts, in which case execute returns without binding.
+func (pm *PodManager) RunFunc(ctx context.Context) (execute func() error, interrupt func(error)) {
+	execute = func() error { return pm.StartContext(ctx) }
+	interrupt = func(error) { pm.Shutdown() }
+	return execute, interrupt
+}
+
+// handleEarlySignals triggers shutdown on shutdown signals from construction
+// onwards, so a signal that arrives before Start still drains cleanly.
+func (pm *PodManager) handleEarlySignals() {
+	sigs := pm.signalsFor(config.ActionShutdown)
+	if len(sigs) == 0 {
+		return
+	}
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, sigs...)
+	pm.goBackground(func(ctx context.Context) {
+		defer signal.Stop(sigCh)
+		select {
+		case <-sigCh:
+			// shutdown waits on background goroutines, so run it outside this one.
+			go pm.shutdown()
+		case <-ctx.Done():
+		}
+	})
 }
 
 // Start runs a default HTTP PodManager and blocks until SIGTERM/SIGINT.
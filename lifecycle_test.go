@@ -1,11 +1,21 @@
 package podlifecycle_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net"
 	"net/http"
+	"os"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
@@ -55,6 +65,7 @@ func doGET(t *testing.T, url string) int {
 	if err != nil {
 		t.Fatalf("GET %s: %v", url, err)
 	}
+	_, _ = io.Copy(io.Discard, resp.Body)
 	_ = resp.Body.Close()
 	return resp.StatusCode
 }
@@ -63,8 +74,8 @@ func doGET(t *testing.T, url string) int {
 
 func TestNewPodManagerInvalidPort(t *testing.T) {
 	_, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(0))
-	if err == nil {
-		t.Error("expected error for port 0, got nil")
+	if !errors.Is(err, podlifecycle.ErrInvalidPort) {
+		t.Errorf("port 0: got %v, want ErrInvalidPort", err)
 	}
 }
 
@@ -186,6 +197,87 @@ func TestWithCheckerSpyCalled(t *testing.T) {
 	}
 }
 
+func TestNewPodManagerFromConfigMatchesOptions(t *testing.T) {
+	failing := podlifecycle.CheckerFunc(func(context.Context) error { return errors.New("db down") })
+
+	optsPort := freePort(t)
+	fromOpts, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(optsPort),
+		podlifecycle.WithChecker("db", failing),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := podlifecycle.DefaultConfig()
+	cfg.HTTPPort = freePort(t)
+	cfg.Checkers = map[string]podlifecycle.Checker{"db": failing}
+	fromCfg, err := podlifecycle.NewPodManagerFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		pm   *podlifecycle.PodManager
+		port int
+	}{
+		{"options", fromOpts, optsPort},
+		{"config", fromCfg, cfg.HTTPPort},
+	} {
+		if err := tc.pm.StartAsync(); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		defer tc.pm.Shutdown()
+		tc.pm.SetReady()
+		base := fmt.Sprintf("http://127.0.0.1:%d", tc.port)
+		if got := doGET(t, base+"/ready"); got != http.StatusServiceUnavailable {
+			t.Errorf("%s /ready with a failing checker: got %d, want 503", tc.name, got)
+		}
+		if got := doGET(t, base+"/live"); got != http.StatusOK {
+			t.Errorf("%s /live: got %d, want 200", tc.name, got)
+		}
+	}
+}
+
+func TestNewPodManagerFromConfigValidates(t *testing.T) {
+	if _, err := podlifecycle.NewPodManagerFromConfig(podlifecycle.Config{}); !errors.Is(err, podlifecycle.ErrInvalidOption) && !errors.Is(err, podlifecycle.ErrInvalidPort) {
+		t.Errorf("zero Config: got %v, want a validation error", err)
+	}
+	cfg := podlifecycle.DefaultConfig()
+	cfg.CheckMechanism = podlifecycle.CheckGRPC
+	cfg.ExistingHTTPMux = http.NewServeMux()
+	if _, err := podlifecycle.NewPodManagerFromConfig(cfg); !errors.Is(err, podlifecycle.ErrConflictingOptions) {
+		t.Errorf("CheckGRPC with an existing mux: got %v, want ErrConflictingOptions", err)
+	}
+}
+
+func TestCheckerFuncAdapter(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithChecker("inline", podlifecycle.CheckerFunc(func(context.Context) error {
+			return errors.New("not yet")
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	if got := doGET(t, fmt.Sprintf("http://127.0.0.1:%d/ready", port)); got != http.StatusServiceUnavailable {
+		t.Errorf("/ready: want 503, got %d", got)
+	}
+	if ok, err, _ := pm.CheckerStatus("inline"); ok || err == nil || err.Error() != "not yet" {
+		t.Errorf("CheckerStatus: got ok=%v err=%v, want the func's error", ok, err)
+	}
+}
+
 func TestPortAlreadyInUseReturnsError(t *testing.T) {
 	port := freePort(t)
 	// Hold the port on all interfaces (same bind as the HTTP probe uses).
@@ -204,6 +296,50 @@ func TestPortAlreadyInUseReturnsError(t *testing.T) {
 	}
 }
 
+func TestErrorChReportsBackgroundStartError(t *testing.T) {
+	port := freePort(t)
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatalf("failed to hold port: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go pm.StartContext(context.Background()) //nolint:errcheck
+	select {
+	case err := <-pm.ErrorCh():
+		if err == nil {
+			t.Error("ErrorCh: got nil, want the bind error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ErrorCh: no error after a failed start")
+	}
+	if _, ok := <-pm.ErrorCh(); ok {
+		t.Error("ErrorCh should be closed after the start error")
+	}
+}
+
+func TestErrorChClosedOnCleanStart(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	select {
+	case err, ok := <-pm.ErrorCh():
+		if ok {
+			t.Errorf("ErrorCh: got %v, want closed without a value", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ErrorCh not closed after a clean start")
+	}
+}
+
 func TestConcurrentSetReadyAndIsShuttingDown(t *testing.T) {
 	port := freePort(t)
 	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
@@ -401,3 +537,1992 @@ func TestExplicitShutdownBeforeContextCancel(t *testing.T) {
 	}
 }
 
+// TestSetReadyBeforeStartGRPC verifies that a SetReady call made before the
+// standalone gRPC probe is started is reflected once Start runs, without a
+// second SetState.
+func TestSetReadyBeforeStartGRPC(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC),
+		podlifecycle.WithGRPCPort(port),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm.SetReady()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = pm.StartContext(ctx) }()
+	if err, ok := <-pm.ErrorCh(); ok {
+		t.Fatalf("StartContext: %v", err)
+	}
+
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	if got := grpcHealthCheck(t, addr, "ready"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("ready after pre-Start SetReady: want SERVING, got %v", got)
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(50 * time.Millisecond)
+	pm.SetReady()
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+
+	pm.Pause()
+	if code := doGET(t, base+"/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("/ready while paused: got %d, want 503", code)
+	}
+	if code := doGET(t, base+"/live"); code != http.StatusOK {
+		t.Errorf("/live while paused: got %d, want 200", code)
+	}
+	if !pm.Ready() || !pm.IsPaused() || !pm.Status().Paused {
+		t.Error("Pause must keep the ready flag and report paused")
+	}
+	pm.Resume()
+	if code := doGET(t, base+"/ready"); code != http.StatusOK {
+		t.Errorf("/ready after Resume: got %d, want 200", code)
+	}
+
+	// A SetNotReady made while paused is what Resume returns to.
+	pm.Pause()
+	pm.SetNotReady()
+	pm.Resume()
+	if code := doGET(t, base+"/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("/ready after SetNotReady while paused: got %d, want 503", code)
+	}
+}
+
+func TestPauseResumeGRPC(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC),
+		podlifecycle.WithGRPCPort(port),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(50 * time.Millisecond)
+	pm.SetReady()
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+
+	pm.Pause()
+	if got := grpcHealthCheck(t, addr, "ready"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("ready while paused: got %v, want NOT_SERVING", got)
+	}
+	if got := grpcHealthCheck(t, addr, "live"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("live while paused: got %v, want SERVING", got)
+	}
+	pm.Resume()
+	if got := grpcHealthCheck(t, addr, "ready"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("ready after Resume: got %v, want SERVING", got)
+	}
+}
+
+type failChecker struct{}
+
+func (failChecker) Check(_ context.Context) error { return fmt.Errorf("down") }
+
+func TestCheckerStatus(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithChecker("db", &spyChecker{}),
+		podlifecycle.WithChecker("cache", failChecker{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err, ts := pm.CheckerStatus("db"); ok || err != nil || !ts.IsZero() {
+		t.Errorf("before any run: got ok=%v err=%v ts=%v, want false/nil/zero", ok, err, ts)
+	}
+	if _, err, _ := pm.CheckerStatus("missing"); !errors.Is(err, podlifecycle.ErrUnknownChecker) {
+		t.Errorf("unknown name: want ErrUnknownChecker, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	before := time.Now()
+	doGET(t, fmt.Sprintf("http://127.0.0.1:%d/ready", port))
+
+	ok, err, ts := pm.CheckerStatus("db")
+	if !ok || err != nil {
+		t.Errorf("db: want ok, got ok=%v err=%v", ok, err)
+	}
+	if ts.Before(before) {
+		t.Errorf("db: timestamp %v predates request at %v", ts, before)
+	}
+	if ok, err, _ := pm.CheckerStatus("cache"); ok || err == nil {
+		t.Errorf("cache: want failure, got ok=%v err=%v", ok, err)
+	}
+	if got := pm.LastCheckResults(); got["db"] != "ok" || got["cache"] != "error: down" {
+		t.Errorf("LastCheckResults: got %v", got)
+	}
+}
+
+// warnChecker is a DetailedChecker that always warns with msg.
+type warnChecker struct{ msg string }
+
+func (warnChecker) Check(context.Context) error { return nil }
+
+func (w warnChecker) CheckDetailed(context.Context) podlifecycle.CheckResult {
+	return podlifecycle.CheckResult{Status: podlifecycle.StatusWarn, Message: w.msg}
+}
+
+func TestCheckerStatusWarning(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithChecker("replica", warnChecker{msg: "lag 5s"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	if code := doGET(t, fmt.Sprintf("http://127.0.0.1:%d/ready", port)); code != http.StatusOK {
+		t.Fatalf("/ready: got %d, want 200", code)
+	}
+	ok, err, _ := pm.CheckerStatus("replica")
+	if !ok || !errors.Is(err, podlifecycle.ErrCheckerWarning) || !strings.Contains(err.Error(), "lag 5s") {
+		t.Errorf("CheckerStatus: got ok=%v err=%v, want ok with the warning", ok, err)
+	}
+	if got := pm.LastCheckResults()["replica"]; got != "warn: lag 5s" {
+		t.Errorf("LastCheckResults: got %q, want %q", got, "warn: lag 5s")
+	}
+}
+
+// TestGRPCShutdownTimeoutHonored holds a health Watch stream open so GracefulStop
+// blocks, and verifies shutdown waits for the gRPC-specific timeout rather than
+// the shorter general one.
+func TestGRPCShutdownTimeoutHonored(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC),
+		podlifecycle.WithGRPCPort(port),
+		podlifecycle.WithShutdownTimeout(10*time.Millisecond),
+		podlifecycle.WithGRPCShutdownTimeout(300*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	stream, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "ready"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StartContext did not return")
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("shutdown returned after %v; gRPC timeout of 300ms was not honored", elapsed)
+	}
+}
+
+func TestGRPCStartupWaitsForSetStarted(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC),
+		podlifecycle.WithGRPCPort(port),
+		podlifecycle.WithStartupRequiresSetStarted(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+	pm.SetReady()
+
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	if got := grpcHealthCheck(t, addr, "startup"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("startup before SetStarted: want NOT_SERVING, got %v", got)
+	}
+	if got := grpcHealthCheck(t, addr, "ready"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("ready before SetStarted: want NOT_SERVING, got %v", got)
+	}
+
+	pm.SetStarted()
+	if got := grpcHealthCheck(t, addr, "startup"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("startup after SetStarted: want SERVING, got %v", got)
+	}
+	if got := grpcHealthCheck(t, addr, "ready"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("ready after SetStarted: want SERVING, got %v", got)
+	}
+}
+
+func TestIsServingTransitions(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pm.IsServing() {
+		t.Error("IsServing() should be false before Start")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	if !pm.IsServing() {
+		t.Error("IsServing() should be true after Start binds")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StartContext did not return after cancel")
+	}
+	if pm.IsServing() {
+		t.Error("IsServing() should be false after shutdown")
+	}
+	if !pm.Started() {
+		t.Error("Started() should remain true after shutdown")
+	}
+}
+
+func TestMinUptimeDelaysReadiness(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithMinUptime(400*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm.SetReady()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(50 * time.Millisecond)
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/ready", port)
+	if got := doGET(t, url); got != http.StatusServiceUnavailable {
+		t.Errorf("/ready before min uptime: want 503, got %d", got)
+	}
+	time.Sleep(450 * time.Millisecond)
+	if got := doGET(t, url); got != http.StatusOK {
+		t.Errorf("/ready after min uptime: want 200, got %d", got)
+	}
+}
+
+func TestMinUptimeGRPC(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC),
+		podlifecycle.WithGRPCPort(port),
+		podlifecycle.WithMinUptime(300*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm.SetReady()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(50 * time.Millisecond)
+
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	if got := grpcHealthCheck(t, addr, "ready"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("ready before min uptime: want NOT_SERVING, got %v", got)
+	}
+	time.Sleep(350 * time.Millisecond)
+	if got := grpcHealthCheck(t, addr, "ready"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("ready after min uptime: want SERVING, got %v", got)
+	}
+}
+
+// TestShutdownStopsBackgroundGoroutines checks for goroutine leaks around a
+// manager whose background loop (the min-uptime gate) is still pending when
+// shutdown begins.
+func TestShutdownStopsBackgroundGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithMinUptime(time.Hour),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StartContext did not return")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines leaked: before=%d after=%d", before, after)
+	}
+}
+
+// TestShutdownBeforeStartReturnsCleanly simulates an early termination request
+// via the manual shutdown trigger: Start must return without binding.
+func TestShutdownBeforeStartReturnsCleanly(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithEarlySignalHandling(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm.Shutdown()
+
+	done := make(chan error, 1)
+	go func() { done <- pm.Start() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start after early shutdown: want nil, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Start did not return after early shutdown")
+	}
+	if pm.IsServing() {
+		t.Error("IsServing() should be false: Start must not bind after early shutdown")
+	}
+}
+
+func TestEarlySignalBeforeStart(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithEarlySignalHandling(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !pm.IsShuttingDown() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !pm.IsShuttingDown() {
+		t.Fatal("IsShuttingDown() should be true after an early SIGTERM")
+	}
+	if err := pm.Start(); err != nil {
+		t.Errorf("Start after early signal: want nil, got %v", err)
+	}
+}
+
+func TestShutdownUnblocksStart(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- pm.Start() }()
+	time.Sleep(50 * time.Millisecond)
+	pm.Shutdown()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Start did not return after Shutdown")
+	}
+}
+
+func TestStartContextTwiceReturnsErrAlreadyStarted(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+
+	if err := pm.StartContext(context.Background()); !errors.Is(err, podlifecycle.ErrAlreadyStarted) {
+		t.Errorf("second StartContext: got %v, want ErrAlreadyStarted", err)
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StartContext did not return after cancel")
+	}
+	if err := pm.StartContext(context.Background()); !errors.Is(err, podlifecycle.ErrAlreadyStarted) {
+		t.Errorf("StartContext after shutdown: got %v, want ErrAlreadyStarted", err)
+	}
+}
+
+func TestWaitUntilReady(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pm.WaitUntilReady(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("before SetReady: got %v, want DeadlineExceeded", err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		pm.SetReady()
+	}()
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel2()
+	if err := pm.WaitUntilReady(ctx2); err != nil {
+		t.Fatalf("WaitUntilReady: %v", err)
+	}
+	select {
+	case <-pm.ReadyCh():
+	default:
+		t.Error("ReadyCh not closed after SetReady")
+	}
+}
+
+func TestAddRemoveCheckerConcurrentWithReady(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			name := fmt.Sprintf("c%d", i%4)
+			if i%2 == 0 {
+				pm.AddChecker(name, &spyChecker{})
+			} else {
+				pm.RemoveChecker(name)
+			}
+		}
+	}()
+	url := fmt.Sprintf("http://127.0.0.1:%d/ready", port)
+	for i := 0; i < 20; i++ {
+		if code := doGET(t, url); code != http.StatusOK {
+			t.Fatalf("request %d: got %d, want 200", i, code)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	pm.AddChecker("db", failChecker{})
+	if code := doGET(t, url); code != http.StatusServiceUnavailable {
+		t.Errorf("after AddChecker(failing): got %d, want 503", code)
+	}
+	pm.RemoveChecker("db")
+	if _, err, _ := pm.CheckerStatus("db"); !errors.Is(err, podlifecycle.ErrUnknownChecker) {
+		t.Errorf("after RemoveChecker: got %v, want ErrUnknownChecker", err)
+	}
+}
+
+func TestDisableEnableChecker(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithChecker("db", &spyChecker{}),
+		podlifecycle.WithChecker("cache", failChecker{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+	pm.SetReady()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/ready", port)
+	if code := doGET(t, url); code != http.StatusServiceUnavailable {
+		t.Fatalf("before DisableChecker: got %d, want 503", code)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			pm.DisableChecker("db")
+			pm.EnableChecker("db")
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		doGET(t, url)
+	}
+	close(stop)
+	wg.Wait()
+
+	pm.DisableChecker("missing") // unknown names are ignored
+	pm.DisableChecker("cache")
+
+	resp, err := http.Get(url) //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("after DisableChecker: got %d, want 200", resp.StatusCode)
+	}
+	if !strings.Contains(string(body), `"cache":"disabled"`) {
+		t.Errorf("after DisableChecker: body %q does not report cache as disabled", body)
+	}
+
+	pm.EnableChecker("cache")
+	if code := doGET(t, url); code != http.StatusServiceUnavailable {
+		t.Errorf("after EnableChecker: got %d, want 503", code)
+	}
+}
+
+func TestConfirmNotReadyWaitsForScrapes(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithConfirmNotReady(2),
+		podlifecycle.WithShutdownTimeout(5*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/ready", port)
+	if code := doGET(t, url); code != http.StatusOK {
+		t.Fatalf("before shutdown: got %d, want 200", code)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		pm.Shutdown()
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("shutdown finished before any not-ready scrape")
+	default:
+	}
+
+	for i := 0; i < 2; i++ {
+		if code := doGET(t, url); code != http.StatusServiceUnavailable {
+			t.Fatalf("scrape %d during shutdown: got %d, want 503", i, code)
+		}
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("shutdown did not finish after confirmed scrapes")
+	}
+}
+
+func TestConfirmNotReadyLeavesHalfTheBudget(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithConfirmNotReady(1),
+		podlifecycle.WithShutdownTimeout(2*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	pm.Shutdown()
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond || elapsed > 1500*time.Millisecond {
+		t.Errorf("unconfirmed shutdown took %v, want about half the 2s timeout", elapsed)
+	}
+	if report, ok := pm.LastShutdownReport(); !ok || report.NotReadyConfirmed || report.ForcedStop {
+		t.Errorf("report: got %+v, want unconfirmed and not forced", report)
+	}
+}
+
+func TestSuperviseFailsLiveness(t *testing.T) {
+	port := freePort(t)
+	reported := make(chan error, 1)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithErrorHandler(func(err error) { reported <- err }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+
+	worker := make(chan struct{})
+	pm.Supervise("consumer", worker)
+	url := fmt.Sprintf("http://127.0.0.1:%d/live", port)
+	if code := doGET(t, url); code != http.StatusOK {
+		t.Fatalf("before worker exit: got %d, want 200", code)
+	}
+
+	close(worker)
+	select {
+	case err := <-reported:
+		if !strings.Contains(err.Error(), "consumer") {
+			t.Errorf("error handler: got %v, want worker name", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("error handler not called")
+	}
+	if code := doGET(t, url); code != http.StatusServiceUnavailable {
+		t.Errorf("after worker exit: got %d, want 503", code)
+	}
+}
+
+func TestSelfTerminateOnLivenessFailure(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithSelfTerminateOnLivenessFailure(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- pm.Start() }()
+	if err, ok := <-pm.ErrorCh(); ok {
+		t.Fatalf("Start: %v", err)
+	}
+
+	worker := make(chan struct{})
+	pm.Supervise("consumer", worker)
+	close(worker)
+	select {
+	case err := <-done:
+		if !errors.Is(err, podlifecycle.ErrLivenessFailure) || !strings.Contains(err.Error(), "consumer") {
+			t.Errorf("Start: got %v, want ErrLivenessFailure naming the worker", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Start did not return after the watchdog tripped")
+	}
+	if !pm.IsShuttingDown() {
+		t.Error("IsShuttingDown() should be true after self-termination")
+	}
+}
+
+func TestConnClosersRunDuringShutdown(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	var notReadyFirst bool
+	pm.RegisterConnCloser(func() {
+		notReadyFirst = pm.IsShuttingDown()
+		order = append(order, "ws")
+	})
+	pm.RegisterConnCloser(func() { order = append(order, "sse") })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- pm.StartContext(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-errCh
+
+	if fmt.Sprint(order) != "[ws sse]" {
+		t.Errorf("closers: got %v, want [ws sse]", order)
+	}
+	if !notReadyFirst {
+		t.Error("closer ran before the pod was marked shutting down")
+	}
+}
+
+// closerFunc adapts a func to io.Closer.
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestDeferredCloseRunsOnceAtShutdownEnd(t *testing.T) {
+	var closed []string
+	var pm *podlifecycle.PodManager
+	var doneEarly bool
+	record := func(name string) closerFunc {
+		return func() error {
+			closed = append(closed, name)
+			select {
+			case <-pm.Done():
+				doneEarly = true
+			default:
+			}
+			return nil
+		}
+	}
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithDeferredClose(record("logger")),
+		podlifecycle.WithDeferredClose(record("tracer")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var connClosedFirst bool
+	pm.RegisterConnCloser(func() { connClosedFirst = len(closed) == 0 })
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	if len(closed) != 0 {
+		t.Fatalf("closed before shutdown: %v", closed)
+	}
+	pm.Shutdown()
+	pm.Shutdown()
+
+	if fmt.Sprint(closed) != "[tracer logger]" {
+		t.Errorf("deferred closers: got %v, want [tracer logger] once each", closed)
+	}
+	if !connClosedFirst {
+		t.Error("deferred closers ran before the conn closers")
+	}
+	if doneEarly {
+		t.Error("Done was closed before the deferred closers ran")
+	}
+}
+
+func TestDeferredCloseRejectsNil(t *testing.T) {
+	if _, err := podlifecycle.NewPodManager(podlifecycle.WithDeferredClose(nil)); !errors.Is(err, podlifecycle.ErrInvalidOption) {
+		t.Errorf("nil closer: got %v, want ErrInvalidOption", err)
+	}
+}
+
+func TestReadinessGate(t *testing.T) {
+	port := freePort(t)
+	var leader atomic.Bool
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithReadinessGate(leader.Load),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/ready", port)
+	if code := doGET(t, url); code != http.StatusServiceUnavailable {
+		t.Errorf("gate closed: got %d, want 503", code)
+	}
+	leader.Store(true)
+	if code := doGET(t, url); code != http.StatusOK {
+		t.Errorf("gate open: got %d, want 200", code)
+	}
+	leader.Store(false)
+	if code := doGET(t, url); code != http.StatusServiceUnavailable {
+		t.Errorf("gate closed again: got %d, want 503", code)
+	}
+}
+
+func TestShutdownReport(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithShutdownTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pm.LastShutdownReport(); ok {
+		t.Error("report available before shutdown")
+	}
+	pm.RegisterConnCloser(func() { time.Sleep(100 * time.Millisecond) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- pm.StartContext(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-errCh
+
+	report, ok := pm.LastShutdownReport()
+	if !ok {
+		t.Fatal("no report after shutdown")
+	}
+	if len(report.Closers) != 1 || report.Closers[0] < 100*time.Millisecond {
+		t.Errorf("Closers: got %v, want one entry >= 100ms", report.Closers)
+	}
+	if report.ForcedStop {
+		t.Error("ForcedStop: got true, want false with no probe request in flight")
+	}
+	if report.Duration < 100*time.Millisecond {
+		t.Errorf("Duration: got %v, want >= 100ms", report.Duration)
+	}
+}
+
+func TestExtendShutdownAvoidsForcedStop(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithShutdownTimeout(100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pm.ExtendShutdown(time.Second) {
+		t.Error("ExtendShutdown before shutdown: got true, want false")
+	}
+	extended := make(chan bool, 1)
+	pm.RegisterConnCloser(func() {
+		// A slow drain that asks for more time before the budget runs out.
+		extended <- pm.ExtendShutdown(time.Second)
+		time.Sleep(300 * time.Millisecond)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- pm.StartContext(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("StartContext: got %v, want nil after extending the deadline", err)
+	}
+	if !<-extended {
+		t.Error("ExtendShutdown during shutdown: got false, want true")
+	}
+	report, _ := pm.LastShutdownReport()
+	if report.ForcedStop {
+		t.Error("ForcedStop: got true, want false after ExtendShutdown")
+	}
+	if pm.ExtendShutdown(time.Second) {
+		t.Error("ExtendShutdown after shutdown: got true, want false")
+	}
+}
+
+func TestStartContextCleanShutdownReturnsNil(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- pm.StartContext(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("StartContext after clean shutdown: got %v, want nil", err)
+	}
+}
+
+func TestStartContextExpiredBudgetWithoutForcedStopReturnsNil(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithShutdownTimeout(50*time.Millisecond),
+		// Nothing scrapes /ready, so the confirmation waits out the budget.
+		podlifecycle.WithConfirmNotReady(1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- pm.StartContext(ctx) }()
+	if err, ok := <-pm.ErrorCh(); ok {
+		t.Fatalf("StartContext: %v", err)
+	}
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("StartContext: got %v, want nil with nothing in flight at the deadline", err)
+	}
+}
+
+func TestStartContextForcedShutdownReturnsShutdownError(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithShutdownTimeout(100*time.Millisecond),
+		podlifecycle.WithCheckerTimeout(5*time.Second),
+		podlifecycle.WithChecker("slow", blockingChecker{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- pm.StartContext(ctx) }()
+	if err, ok := <-pm.ErrorCh(); ok {
+		t.Fatalf("StartContext: %v", err)
+	}
+	pm.SetReady()
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/ready", port)) //nolint:noctx
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	err = <-errCh
+	if !errors.Is(err, podlifecycle.ErrForcedShutdown) {
+		t.Fatalf("StartContext: got %v, want ErrForcedShutdown", err)
+	}
+	var se *podlifecycle.ShutdownError
+	if !errors.As(err, &se) {
+		t.Fatalf("StartContext: got %T, want *ShutdownError", err)
+	}
+	if !se.Report.ForcedStop || se.Report.Duration < 100*time.Millisecond {
+		t.Errorf("Report: got %+v, want forced stop lasting the 100ms timeout", se.Report)
+	}
+}
+
+func TestStartContextCancelledBeforeStartDoesNotBind(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := pm.StartContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("StartContext: got %v, want context.Canceled", err)
+	}
+	if pm.Started() {
+		t.Error("Started() should be false when ctx was done before binding")
+	}
+	select {
+	case err, ok := <-pm.ErrorCh():
+		if !ok || !errors.Is(err, context.Canceled) {
+			t.Errorf("ErrorCh: got %v (open %v), want context.Canceled", err, ok)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ErrorCh: no start outcome reported")
+	}
+}
+
+func TestCachedCheckerNotRunBeforeSetReady(t *testing.T) {
+	port := freePort(t)
+	spy := &spyChecker{}
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithChecker("db", podlifecycle.Cached(spy, time.Minute)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/ready", port)
+	for i := 0; i < 3; i++ {
+		doGET(t, url)
+	}
+	if n := spy.Calls(); n != 0 {
+		t.Errorf("before SetReady: got %d checker calls, want 0", n)
+	}
+	pm.SetReady()
+	doGET(t, url)
+	if n := spy.Calls(); n != 1 {
+		t.Errorf("after SetReady: got %d checker calls, want 1", n)
+	}
+}
+
+func TestStatusEndpoint(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithStatusEndpoint("/status"),
+		podlifecycle.WithChecker("db", &spyChecker{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/status", port)
+	getStatus := func() podlifecycle.Status {
+		t.Helper()
+		resp, err := http.Get(url) //nolint:noctx
+		if err != nil {
+			t.Fatalf("GET /status: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET /status: want 200, got %d", resp.StatusCode)
+		}
+		var st podlifecycle.Status
+		if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return st
+	}
+
+	st := getStatus()
+	if st.Ready || !st.Started || !st.Serving || st.ShuttingDown || len(st.Checkers) != 0 {
+		t.Errorf("before SetReady: got %+v", st)
+	}
+
+	pm.SetReady()
+	doGET(t, fmt.Sprintf("http://127.0.0.1:%d/ready", port))
+	st = getStatus()
+	if !st.Ready || st.Checkers["db"] != "ok" || st.Uptime == "" {
+		t.Errorf("after SetReady: got %+v", st)
+	}
+
+	resp, err := http.Post(url, "application/json", nil) //nolint:noctx
+	if err != nil {
+		t.Fatalf("POST /status: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST /status: want 405, got %d", resp.StatusCode)
+	}
+}
+
+type shutdownRecorder struct {
+	mu        sync.Mutex
+	durations []time.Duration
+	forced    int
+}
+
+func (r *shutdownRecorder) ObserveShutdownDuration(d time.Duration) {
+	r.mu.Lock()
+	r.durations = append(r.durations, d)
+	r.mu.Unlock()
+}
+
+func (r *shutdownRecorder) IncForcedStop() {
+	r.mu.Lock()
+	r.forced++
+	r.mu.Unlock()
+}
+
+// blockingChecker holds /ready open until its context ends.
+type blockingChecker struct{}
+
+func (blockingChecker) Check(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestShutdownMetricsForcedStop(t *testing.T) {
+	port := freePort(t)
+	rec := &shutdownRecorder{}
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithShutdownTimeout(100*time.Millisecond),
+		podlifecycle.WithCheckerTimeout(5*time.Second),
+		podlifecycle.WithChecker("slow", blockingChecker{}),
+		podlifecycle.WithShutdownMetrics(rec),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(context.Background()) }()
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/ready", port)) //nolint:noctx
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+	pm.Shutdown()
+	<-done
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.forced != 1 {
+		t.Errorf("forced stops: got %d, want 1", rec.forced)
+	}
+	if len(rec.durations) != 1 || rec.durations[0] < 100*time.Millisecond {
+		t.Errorf("durations: got %v, want one of at least 100ms", rec.durations)
+	}
+}
+
+func TestForcedStopHTTP(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithShutdownTimeout(100*time.Millisecond),
+		podlifecycle.WithCheckerTimeout(5*time.Second),
+		podlifecycle.WithChecker("slow", blockingChecker{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(context.Background()) }()
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+	if pm.ForcedStop() {
+		t.Error("ForcedStop before shutdown: got true, want false")
+	}
+
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/ready", port)) //nolint:noctx
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+	pm.Shutdown()
+	<-done
+	if !pm.ForcedStop() {
+		t.Error("ForcedStop: got false, want true with a /ready request held past the deadline")
+	}
+}
+
+func TestForcedStopGRPC(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC),
+		podlifecycle.WithGRPCPort(port),
+		podlifecycle.WithShutdownTimeout(100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(context.Background()) }()
+	time.Sleep(50 * time.Millisecond)
+
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	// An open Watch stream keeps GracefulStop waiting past the deadline.
+	stream, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "live"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	pm.Shutdown()
+	<-done
+	if !pm.ForcedStop() {
+		t.Error("ForcedStop: got false, want true with a Watch stream held past the deadline")
+	}
+}
+
+func TestForcedStopFalseAfterCleanShutdown(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(context.Background()) }()
+	time.Sleep(50 * time.Millisecond)
+	pm.Shutdown()
+	<-done
+	if pm.ForcedStop() {
+		t.Error("ForcedStop: got true, want false with no connections open")
+	}
+}
+
+// sleepChecker passes after d unless its context ends first.
+type sleepChecker time.Duration
+
+func (s sleepChecker) Check(ctx context.Context) error {
+	select {
+	case <-time.After(time.Duration(s)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestCheckerDeadlineStrategy(t *testing.T) {
+	// The middleware gives every request a deadline shorter than the check.
+	tight := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), 50*time.Millisecond)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+	for _, tc := range []struct {
+		strategy podlifecycle.CheckerDeadlineStrategy
+		want     int
+	}{
+		{podlifecycle.CheckerDeadlineMin, http.StatusServiceUnavailable},
+		{podlifecycle.CheckerDeadlineFixed, http.StatusOK},
+	} {
+		port := freePort(t)
+		pm, err := podlifecycle.NewPodManager(
+			podlifecycle.WithHTTPPort(port),
+			podlifecycle.WithHTTPMiddleware(tight),
+			podlifecycle.WithCheckerTimeout(2*time.Second),
+			podlifecycle.WithChecker("slow", sleepChecker(200*time.Millisecond)),
+			podlifecycle.WithCheckerDeadlineStrategy(tc.strategy),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		go pm.StartContext(ctx) //nolint:errcheck
+		time.Sleep(50 * time.Millisecond)
+		pm.SetReady()
+		if code := doGET(t, fmt.Sprintf("http://127.0.0.1:%d/ready", port)); code != tc.want {
+			t.Errorf("strategy %d: got %d, want %d", tc.strategy, code, tc.want)
+		}
+		cancel()
+		pm.Wait()
+	}
+}
+
+func TestTransitionAudit(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []podlifecycle.TransitionEvent
+	)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithTransitionAudit(func(e podlifecycle.TransitionEvent) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+	pm.SetReady() // no change, no event
+	pm.SetNotReady()
+	pm.SetReady()
+	cancel()
+	<-done
+
+	want := []struct {
+		field    string
+		old, new bool
+	}{
+		{"started", false, true},
+		{"ready", false, true},
+		{"ready", true, false},
+		{"ready", false, true},
+		{"shuttingDown", false, true},
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != len(want) {
+		t.Fatalf("got %d events %+v, want %d", len(events), events, len(want))
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Field != w.field || e.Old != w.old || e.New != w.new || e.Time.IsZero() {
+			t.Errorf("event %d: got %+v, want %s %v→%v", i, e, w.field, w.old, w.new)
+		}
+	}
+}
+
+// TestRunFuncInGroup wires RunFunc into a minimal oklog/run-style group: the
+// first actor to return interrupts the others.
+func TestRunFuncInGroup(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	execute, interrupt := pm.RunFunc(context.Background())
+	errWorker := errors.New("worker failed")
+	actors := []struct {
+		execute   func() error
+		interrupt func(error)
+	}{
+		{execute, interrupt},
+		{func() error { time.Sleep(100 * time.Millisecond); return errWorker }, func(error) {}},
+	}
+
+	errs := make(chan error, len(actors))
+	for _, a := range actors {
+		a := a
+		go func() { errs <- a.execute() }()
+	}
+	first := <-errs
+	for _, a := range actors {
+		a.interrupt(first)
+	}
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Errorf("execute after interrupt: got %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("execute did not return after interrupt")
+	}
+	if !errors.Is(first, errWorker) {
+		t.Errorf("first error: got %v, want errWorker", first)
+	}
+	if !pm.IsShuttingDown() {
+		t.Error("IsShuttingDown() should be true after interrupt")
+	}
+}
+
+func TestManyCheckersWarnsErrorHandler(t *testing.T) {
+	var warnings []error
+	opts := []podlifecycle.Option{
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithErrorHandler(func(err error) { warnings = append(warnings, err) }),
+	}
+	for i := 0; i < 256; i++ {
+		opts = append(opts, podlifecycle.WithChecker(fmt.Sprintf("c%d", i), &spyChecker{}))
+	}
+	pm, err := podlifecycle.NewPodManager(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("at the threshold: got warnings %v, want none", warnings)
+	}
+	pm.AddChecker("extra", &spyChecker{})
+	pm.AddChecker("extra2", &spyChecker{})
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "257 checkers") {
+		t.Errorf("warnings: got %v, want one about 257 checkers", warnings)
+	}
+}
+
+func TestReportStartupProgressGatesStartup(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm.ReportStartupProgress(0, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d", port)
+	startup := func() (int, string) {
+		resp, err := http.Get(url + "/startup") //nolint:noctx
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode, resp.Header.Get(podlifecycle.StartupProgressHeader)
+	}
+
+	for done := 0; done < 3; done++ {
+		pm.ReportStartupProgress(done, 3)
+		code, progress := startup()
+		if code != http.StatusServiceUnavailable {
+			t.Errorf("progress %d/3: /startup want 503, got %d", done, code)
+		}
+		if want := fmt.Sprintf("%d/3", done); progress != want {
+			t.Errorf("progress header: got %q, want %q", progress, want)
+		}
+		if got := doGET(t, url+"/ready"); got != http.StatusServiceUnavailable {
+			t.Errorf("progress %d/3: /ready want 503, got %d", done, got)
+		}
+	}
+
+	pm.ReportStartupProgress(3, 3)
+	if code, progress := startup(); code != http.StatusOK || progress != "3/3" {
+		t.Errorf("complete: /startup got %d %q, want 200 \"3/3\"", code, progress)
+	}
+	if got := doGET(t, url+"/ready"); got != http.StatusOK {
+		t.Errorf("complete: /ready want 200, got %d", got)
+	}
+}
+
+func TestPanickingStartedHookDoesNotBreakStart(t *testing.T) {
+	port := freePort(t)
+	reported := make(chan error, 1)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithTransitionAudit(func(ev podlifecycle.TransitionEvent) {
+			if ev.Field == "started" {
+				panic("audit sink down")
+			}
+		}),
+		podlifecycle.WithErrorHandler(func(err error) { reported <- err }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pm.StartContext(ctx) }()
+
+	select {
+	case err := <-reported:
+		if !strings.Contains(err.Error(), "audit sink down") {
+			t.Errorf("reported error: got %v, want the panic value", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("panic was not reported to the error handler")
+	}
+	if !pm.Started() || !pm.IsServing() {
+		t.Errorf("Started()=%v IsServing()=%v, want both true", pm.Started(), pm.IsServing())
+	}
+	if got := doGET(t, fmt.Sprintf("http://127.0.0.1:%d/live", port)); got != http.StatusOK {
+		t.Errorf("/live: want 200, got %d", got)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StartContext did not return after cancel")
+	}
+}
+
+func TestDoneClosesAfterShutdownCompletes(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	pm.RegisterConnCloser(func() { <-release })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+
+	go pm.Shutdown()
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-pm.Done():
+		t.Fatal("Done closed while a connection closer was still running")
+	default:
+	}
+	if !pm.IsShuttingDown() {
+		t.Error("IsShuttingDown() should be true once shutdown began")
+	}
+
+	close(release)
+	waited := make(chan struct{})
+	go func() {
+		pm.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Wait did not return after shutdown completed")
+	}
+	if _, ok := pm.LastShutdownReport(); !ok {
+		t.Error("shutdown report missing after Done closed")
+	}
+}
+
+func TestReadyRequiresAppListening(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithReadyRequiresAppListening(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pm.StartContext(ctx) //nolint:errcheck
+	time.Sleep(100 * time.Millisecond)
+	pm.SetReady()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/ready", port)
+	if got := doGET(t, url); got != http.StatusServiceUnavailable {
+		t.Errorf("before SetAppListening: want 503, got %d", got)
+	}
+	if pm.IsAppListening() {
+		t.Error("IsAppListening() should be false before SetAppListening")
+	}
+	pm.SetAppListening()
+	if got := doGET(t, url); got != http.StatusOK {
+		t.Errorf("after SetAppListening: want 200, got %d", got)
+	}
+	if !pm.IsAppListening() {
+		t.Error("IsAppListening() should be true after SetAppListening")
+	}
+}
+
+func TestStartAsyncServesImmediately(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatalf("StartAsync: %v", err)
+	}
+	pm.SetReady()
+
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+	for path, want := range map[string]int{"/live": http.StatusOK, "/startup": http.StatusOK, "/ready": http.StatusOK} {
+		if got := doGET(t, base+path); got != want {
+			t.Errorf("%s: want %d, got %d", path, want, got)
+		}
+	}
+	if err := pm.StartAsync(); !errors.Is(err, podlifecycle.ErrAlreadyStarted) {
+		t.Errorf("second StartAsync: got %v, want ErrAlreadyStarted", err)
+	}
+
+	pm.Shutdown()
+	select {
+	case <-pm.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("shutdown did not complete")
+	}
+	if pm.IsServing() {
+		t.Error("IsServing() should be false after shutdown")
+	}
+}
+
+func TestStartAsyncPortInUseReturnsError(t *testing.T) {
+	port := freePort(t)
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err == nil {
+		t.Error("StartAsync: expected bind error, got nil")
+	}
+}
+
+func TestCloseAfterFailedStartRunsCleanup(t *testing.T) {
+	port := freePort(t)
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	var closed, hooked atomic.Bool
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithDeferredClose(closerFunc(func() error {
+			closed.Store(true)
+			return errors.New("flush failed")
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm.RegisterPreDrainHook(func() { hooked.Store(true) })
+	if err := pm.StartContext(context.Background()); err == nil {
+		t.Fatal("StartContext: expected bind error, got nil")
+	}
+	if closed.Load() || hooked.Load() {
+		t.Fatal("failed start ran cleanup; Start must stay retryable")
+	}
+
+	if err := pm.Close(); err == nil || !strings.Contains(err.Error(), "flush failed") {
+		t.Errorf("Close: got %v, want the deferred close error", err)
+	}
+	if !closed.Load() || !hooked.Load() {
+		t.Errorf("after Close: closer ran %v, pre-drain hook ran %v; want both", closed.Load(), hooked.Load())
+	}
+	select {
+	case <-pm.Done():
+	default:
+		t.Error("Done not closed after Close")
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Errorf("StartAsync after Close: got %v, want nil without binding", err)
+	}
+	if err := pm.Close(); err == nil {
+		t.Error("second Close: want the same deferred close error")
+	}
+}
+
+func TestCloseAfterFailedStartSkipsDrainWaits(t *testing.T) {
+	port := freePort(t)
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithConfirmNotReady(3),
+		podlifecycle.WithLivenessDrainDelay(3*time.Second),
+		podlifecycle.WithShutdownTimeout(3*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartContext(context.Background()); err == nil {
+		t.Fatal("StartContext: expected bind error, got nil")
+	}
+	start := time.Now()
+	if err := pm.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close took %v after a failed bind, want no drain wait", elapsed)
+	}
+}
+
+func TestSetGRPCServiceStatus(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC),
+		podlifecycle.WithGRPCPort(port),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Set before Start: applied once the health server exists.
+	if err := pm.SetGRPCServiceStatus("myapp.v1.Orders", true); err != nil {
+		t.Fatalf("SetGRPCServiceStatus before Start: %v", err)
+	}
+	if err := pm.SetGRPCServiceStatus("ready", true); !errors.Is(err, podlifecycle.ErrReservedService) {
+		t.Errorf("built-in service: got %v, want ErrReservedService", err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	if got := grpcHealthCheck(t, addr, "myapp.v1.Orders"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("custom service: want SERVING, got %v", got)
+	}
+	if err := pm.SetGRPCServiceStatus("myapp.v1.Orders", false); err != nil {
+		t.Fatal(err)
+	}
+	if got := grpcHealthCheck(t, addr, "myapp.v1.Orders"); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("custom service after false: want NOT_SERVING, got %v", got)
+	}
+}
+
+func TestGRPCStatuses(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC),
+		podlifecycle.WithGRPCPort(freePort(t)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pm.GRPCStatuses(); got != nil {
+		t.Errorf("before Start: got %v, want nil", got)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+
+	if got := pm.GRPCStatuses()["ready"]; got != "NOT_SERVING" {
+		t.Errorf("before SetReady: ready = %q, want NOT_SERVING", got)
+	}
+	pm.SetReady()
+	if got := pm.GRPCStatuses()["ready"]; got != "SERVING" {
+		t.Errorf("after SetReady: ready = %q, want SERVING", got)
+	}
+	pm.SetNotReady()
+	if got := pm.GRPCStatuses()["ready"]; got != "NOT_SERVING" {
+		t.Errorf("after SetNotReady: ready = %q, want NOT_SERVING", got)
+	}
+}
+
+func TestGRPCStatusesHTTP(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pm.GRPCStatuses(); got != nil {
+		t.Errorf("HTTP probes: got %v, want nil", got)
+	}
+}
+
+func TestSetGRPCServiceStatusHTTP(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.SetGRPCServiceStatus("myapp.v1.Orders", true); !errors.Is(err, podlifecycle.ErrNotGRPCProbe) {
+		t.Errorf("HTTP probes: got %v, want ErrNotGRPCProbe", err)
+	}
+}
+
+func TestAddCheckerRejectsInvalidName(t *testing.T) {
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"", "my db"} {
+		if err := pm.AddChecker(name, &spyChecker{}); !errors.Is(err, podlifecycle.ErrInvalidOption) {
+			t.Errorf("AddChecker(%q): got %v, want ErrInvalidOption", name, err)
+		}
+	}
+	if got := pm.LastCheckResults(); len(got) != 0 {
+		t.Errorf("LastCheckResults after rejected adds: got %v, want empty", got)
+	}
+	if err := pm.AddChecker("db", &spyChecker{}); err != nil {
+		t.Errorf("AddChecker(db): %v", err)
+	}
+}
+
+// flipChecker fails until pass is set.
+type flipChecker struct{ pass atomic.Bool }
+
+func (c *flipChecker) Check(context.Context) error {
+	if !c.pass.Load() {
+		return errors.New("unreachable")
+	}
+	return nil
+}
+
+func TestStartupCheckersGateStartup(t *testing.T) {
+	port := freePort(t)
+	dep := &flipChecker{}
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithChecker("db", dep),
+		podlifecycle.WithStartupCheckers("db"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/startup", port)
+	if got := doGET(t, url); got != http.StatusServiceUnavailable {
+		t.Errorf("before checker passes: want 503, got %d", got)
+	}
+	dep.pass.Store(true)
+	deadline := time.Now().Add(3 * time.Second)
+	for doGET(t, url) != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("/startup did not flip to 200 after the checker passed")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	// Once satisfied, startup stays complete even if the checker fails again.
+	dep.pass.Store(false)
+	if got := doGET(t, url); got != http.StatusOK {
+		t.Errorf("after checker fails again: want 200, got %d", got)
+	}
+}
+
+func TestStartupCheckersMustBeRegistered(t *testing.T) {
+	_, err := podlifecycle.NewPodManager(podlifecycle.WithStartupCheckers("db"))
+	if !errors.Is(err, podlifecycle.ErrInvalidOption) {
+		t.Errorf("unregistered startup checker: got %v, want ErrInvalidOption", err)
+	}
+}
+
+func TestReservedCheckerNameWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithChecker("status", &spyChecker{}),
+		podlifecycle.WithChecker("db", &spyChecker{}),
+		podlifecycle.WithReservedCheckerNameWarnings(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.AddChecker("checks", &spyChecker{}); err != nil {
+		t.Fatalf("AddChecker with a reserved name must not fail: %v", err)
+	}
+	if err := pm.AddChecker("cache", &spyChecker{}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if n := strings.Count(out, "\n"); n != 2 {
+		t.Errorf("warnings: got %d lines, want 2:\n%s", n, out)
+	}
+	for _, want := range []string{"level=WARN", "checker=status", "checker=checks"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q does not contain %q", out, want)
+		}
+	}
+}
+
+// unwiredChecker stands for a checker built before its client was set.
+type unwiredChecker struct{ client *http.Client }
+
+func (c unwiredChecker) Check(context.Context) error {
+	_ = c.client.Timeout // nil client: panics
+	return nil
+}
+
+func TestNilCheckerRejected(t *testing.T) {
+	for name, c := range map[string]podlifecycle.Checker{
+		"nil interface": nil,
+		"nil func":      podlifecycle.CheckerFunc(nil),
+	} {
+		_, err := podlifecycle.NewPodManager(podlifecycle.WithChecker("db", c))
+		if !errors.Is(err, podlifecycle.ErrInvalidOption) || !strings.Contains(err.Error(), `"db"`) {
+			t.Errorf("WithChecker %s: got %v, want ErrInvalidOption naming db", name, err)
+		}
+	}
+
+	pm, err := podlifecycle.NewPodManager(podlifecycle.WithHTTPPort(freePort(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.AddChecker("cache", nil); !errors.Is(err, podlifecycle.ErrInvalidOption) {
+		t.Errorf("AddChecker nil: got %v, want ErrInvalidOption", err)
+	}
+	if _, err, _ := pm.CheckerStatus("cache"); !errors.Is(err, podlifecycle.ErrUnknownChecker) {
+		t.Errorf("after a rejected AddChecker: got %v, want ErrUnknownChecker", err)
+	}
+}
+
+func TestStartupCheckerSelfTest(t *testing.T) {
+	var buf bytes.Buffer
+	ok := &spyChecker{}
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithChecker("db", ok),
+		podlifecycle.WithChecker("cache", failChecker{}),
+		podlifecycle.WithChecker("api", unwiredChecker{}),
+		podlifecycle.WithStartupCheckerSelfTest(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for ok.Calls() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	pm.Shutdown() // waits for the self-test goroutines
+
+	out := buf.String()
+	if n := strings.Count(out, "\n"); n != 2 {
+		t.Errorf("warnings: got %d lines, want 2:\n%s", n, out)
+	}
+	for _, want := range []string{"level=WARN", "checker=cache", "error=down", "checker=api", "panic:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q does not contain %q", out, want)
+		}
+	}
+	if !pm.Started() {
+		t.Error("a failing self-test must not stop the manager from starting")
+	}
+}
+
+func TestLogConfigAtStartup(t *testing.T) {
+	var buf bytes.Buffer
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithShutdownTimeout(7*time.Second),
+		podlifecycle.WithChecker("db", &spyChecker{}),
+		podlifecycle.WithLogConfigAtStartup(slog.New(slog.NewJSONHandler(&buf, nil))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("logged before Start: %s", buf.String())
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("want one JSON log line, got %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"level":           "INFO",
+		"msg":             "pod lifecycle config",
+		"mechanism":       "http",
+		"httpPort":        float64(port),
+		"shutdownTimeout": float64(7 * time.Second),
+	}
+	for k, v := range want {
+		if line[k] != v {
+			t.Errorf("%s: got %v, want %v", k, line[k], v)
+		}
+	}
+	if fmt.Sprint(line["checkers"]) != "[db]" {
+		t.Errorf("checkers: got %v, want [db]", line["checkers"])
+	}
+}
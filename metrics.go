@@ -0,0 +1,55 @@
+package podlifecycle
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// textMetrics renders the WithTextMetricsEndpoint metrics in the Prometheus
+// text exposition format.
+func (pm *PodManager) textMetrics() []byte {
+	var b bytes.Buffer
+	gauge := func(name, help string, v float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", name, help, name, name, v)
+	}
+	gauge("podlifecycle_ready", "Whether the manager reports ready (1) or not (0), before checkers; see podlifecycle_checker_up.", boolMetric(pm.probeReady()))
+	gauge("podlifecycle_started", "Whether the probe server has started (1) or not (0).", boolMetric(pm.started.Load()))
+	gauge("podlifecycle_shutting_down", "Whether graceful shutdown has begun (1) or not (0).", boolMetric(pm.shuttingDown.Load()))
+	gauge("podlifecycle_uptime_seconds", "Seconds since the probe server started.", pm.Uptime().Seconds())
+	gauge("podlifecycle_ready_duration_seconds", "Seconds since the manager last became ready; 0 while not ready.", pm.ReadyDuration().Seconds())
+
+	results := pm.checkers.Results()
+	b.WriteString("# HELP podlifecycle_checker_up Result of the last run of each checker (1 passing, 0 failing).\n")
+	b.WriteString("# TYPE podlifecycle_checker_up gauge\n")
+	for _, name := range slices.Sorted(maps.Keys(results)) {
+		r := results[name]
+		if r.Time.IsZero() {
+			continue
+		}
+		fmt.Fprintf(&b, "podlifecycle_checker_up{checker=\"%s\"} %g\n", escapeLabel(name), boolMetric(r.Err == nil))
+	}
+
+	var shutdowns int
+	if pm.shuttingDown.Load() {
+		shutdowns = 1
+	}
+	b.WriteString("# HELP podlifecycle_shutdowns_total Graceful shutdowns started.\n")
+	b.WriteString("# TYPE podlifecycle_shutdowns_total counter\n")
+	fmt.Fprintf(&b, "podlifecycle_shutdowns_total %d\n", shutdowns)
+	return b.Bytes()
+}
+
+func boolMetric(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// labelEscaper escapes a label value for the text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string { return labelEscaper.Replace(v) }
@@ -0,0 +1,67 @@
+package podlifecycle_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	podlifecycle "github.com/kroderdev/pod-lifecycle-go"
+)
+
+func TestTextMetricsEndpoint(t *testing.T) {
+	port := freePort(t)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(port),
+		podlifecycle.WithChecker("db", &spyChecker{}),
+		podlifecycle.WithChecker("cache", failChecker{}),
+		podlifecycle.WithTextMetricsEndpoint("/metrics"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+	pm.SetReady()
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+	doGET(t, base+"/ready") // runs the checkers so their results are exported
+
+	resp, err := http.Get(base + "/metrics") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("/metrics: want 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type: got %q", ct)
+	}
+	for _, want := range []string{
+		"# TYPE podlifecycle_ready gauge\npodlifecycle_ready 1\n",
+		"podlifecycle_started 1\n",
+		"podlifecycle_shutting_down 0\n",
+		"podlifecycle_uptime_seconds ",
+		`podlifecycle_checker_up{checker="cache"} 0` + "\n",
+		`podlifecycle_checker_up{checker="db"} 1` + "\n",
+		"# TYPE podlifecycle_shutdowns_total counter\npodlifecycle_shutdowns_total 0\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("/metrics body does not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestTextMetricsEndpointRequiresHTTP(t *testing.T) {
+	_, err := podlifecycle.NewPodManager(
+		podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC),
+		podlifecycle.WithTextMetricsEndpoint("/metrics"),
+	)
+	if err == nil {
+		t.Error("expected error for gRPC probes, got nil")
+	}
+}
@@ -0,0 +1,29 @@
+package podlifecycle
+
+import "github.com/kroderdev/pod-lifecycle-go/internal/config"
+
+// RecommendedProbeConfig returns Kubernetes probe settings (periodSeconds,
+// timeoutSeconds, failureThreshold, terminationGracePeriodSeconds) that agree
+// with the checker and shutdown timeouts of opts, for rendering into
+// manifests. opts are validated as by NewPodManager.
+func RecommendedProbeConfig(opts ...Option) (ProbeConfig, error) {
+	cfg, err := config.ApplyOptions(opts)
+	if err != nil {
+		return ProbeConfig{}, err
+	}
+	return cfg.RecommendedProbeConfig(), nil
+}
+
+// ProbePaths returns the sorted HTTP paths the probe registers under opts,
+// e.g. to document them or to check for collisions with application routes
+// on a shared mux. It includes /ready, /live, /startup (prefixed by
+// WithProbePathPrefix), and any enabled ping, status, metrics, pprof, and
+// root endpoints. gRPC probes register no HTTP paths. opts are validated as
+// by NewPodManager.
+func ProbePaths(opts ...Option) ([]string, error) {
+	cfg, err := config.ApplyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.ProbePaths(), nil
+}
@@ -0,0 +1,83 @@
+package podlifecycle_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	podlifecycle "github.com/kroderdev/pod-lifecycle-go"
+)
+
+func TestRecommendedProbeConfig(t *testing.T) {
+	for _, tc := range []struct {
+		checkerTimeout, shutdownTimeout time.Duration
+	}{
+		{2 * time.Second, 5 * time.Second}, // defaults
+		{1500 * time.Millisecond, 30 * time.Second},
+		{8 * time.Second, 45 * time.Second},
+	} {
+		pc, err := podlifecycle.RecommendedProbeConfig(
+			podlifecycle.WithCheckerTimeout(tc.checkerTimeout),
+			podlifecycle.WithShutdownTimeout(tc.shutdownTimeout),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := time.Duration(pc.TimeoutSeconds) * time.Second; got <= tc.checkerTimeout {
+			t.Errorf("%v: timeoutSeconds %d must exceed the checker timeout", tc.checkerTimeout, pc.TimeoutSeconds)
+		}
+		if pc.PeriodSeconds < 2*pc.TimeoutSeconds || pc.PeriodSeconds < 10 {
+			t.Errorf("%v: periodSeconds %d too short for timeoutSeconds %d", tc.checkerTimeout, pc.PeriodSeconds, pc.TimeoutSeconds)
+		}
+		if got := time.Duration(pc.TerminationGracePeriodSeconds) * time.Second; got <= tc.shutdownTimeout {
+			t.Errorf("%v: terminationGracePeriodSeconds %d must exceed the shutdown timeout", tc.shutdownTimeout, pc.TerminationGracePeriodSeconds)
+		}
+		if pc.FailureThreshold < 1 {
+			t.Errorf("failureThreshold %d must be positive", pc.FailureThreshold)
+		}
+	}
+
+	if _, err := podlifecycle.RecommendedProbeConfig(podlifecycle.WithHTTPPort(0)); err == nil {
+		t.Error("invalid options: expected error, got nil")
+	}
+}
+
+func TestProbePaths(t *testing.T) {
+	paths, err := podlifecycle.ProbePaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/live", "/ready", "/startup"}; !slices.Equal(paths, want) {
+		t.Errorf("defaults: got %v, want %v", paths, want)
+	}
+
+	paths, err = podlifecycle.ProbePaths(
+		podlifecycle.WithExistingHTTPMux(http.NewServeMux()),
+		podlifecycle.WithProbePathPrefix("/internal"),
+		podlifecycle.WithPingEndpoint("/ping"),
+		podlifecycle.WithStatusEndpoint("/status"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/internal/live", "/internal/ping", "/internal/ready", "/internal/startup", "/internal/status"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("prefixed: got %v, want %v", paths, want)
+	}
+
+	paths, err = podlifecycle.ProbePaths(podlifecycle.WithPprof(true), podlifecycle.WithRootHandler(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"/", "/favicon.ico", "/debug/pprof/", "/ready"} {
+		if !slices.Contains(paths, p) {
+			t.Errorf("pprof and root: %v does not contain %q", paths, p)
+		}
+	}
+
+	paths, err = podlifecycle.ProbePaths(podlifecycle.WithCheckMechanism(podlifecycle.CheckGRPC))
+	if err != nil || paths != nil {
+		t.Errorf("gRPC: got %v, %v; want nil, nil", paths, err)
+	}
+}
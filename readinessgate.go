@@ -0,0 +1,61 @@
+package podlifecycle
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// readinessUpdaterRetries bounds the timed retries of a failed updater
+	// call; the first waits readinessUpdaterBackoff and each next one twice
+	// as long.
+	readinessUpdaterRetries = 5
+	readinessUpdaterBackoff = time.Second
+)
+
+// publishReadiness calls the WithReadinessGateUpdater updater once the probe
+// has started and whenever the effective readiness changes. A failed update
+// is reported to the error handler and retried with backoff, and again on
+// the next state change.
+func (pm *PodManager) publishReadiness() {
+	if pm.readinessUpdater == nil || !pm.started.Load() {
+		return
+	}
+	pm.updaterMu.Lock()
+	defer pm.updaterMu.Unlock()
+	ready := pm.probeReady() && !pm.shuttingDown.Load()
+	if pm.updaterPublished && ready == pm.updaterReady {
+		return
+	}
+	if err := pm.readinessUpdater(ready); err != nil {
+		pm.updaterPublished = false
+		if pm.errorHandler != nil {
+			pm.errorHandler(fmt.Errorf("readiness gate updater: %w", err))
+		}
+		pm.retryReadinessUpdate()
+		return
+	}
+	pm.updaterPublished, pm.updaterReady = true, ready
+	pm.updaterFailures = 0
+	if pm.updaterRetry != nil {
+		pm.updaterRetry.Stop()
+		pm.updaterRetry = nil
+	}
+}
+
+// retryReadinessUpdate schedules another publishReadiness after a failed
+// update, unless one is pending or the retries since the last success are
+// used up. Called with updaterMu held.
+func (pm *PodManager) retryReadinessUpdate() {
+	if pm.updaterRetry != nil || pm.updaterFailures >= readinessUpdaterRetries {
+		return
+	}
+	backoff := readinessUpdaterBackoff << pm.updaterFailures
+	pm.updaterFailures++
+	pm.updaterRetry = pm.clock.AfterFunc(backoff, func() {
+		pm.updaterMu.Lock()
+		pm.updaterRetry = nil
+		pm.updaterMu.Unlock()
+		pm.publishReadiness()
+	})
+}
@@ -0,0 +1,135 @@
+package podlifecycle_test
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	podlifecycle "github.com/kroderdev/pod-lifecycle-go"
+)
+
+func TestReadinessGateUpdaterTransitions(t *testing.T) {
+	var mu sync.Mutex
+	var calls []bool
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithReadinessGateUpdater(func(ready bool) error {
+			mu.Lock()
+			calls = append(calls, ready)
+			mu.Unlock()
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := func() []bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(calls)
+	}
+
+	pm.SetReady() // before Start: not published
+	pm.SetNotReady()
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	pm.SetReady()
+	pm.SetReady() // no transition
+	pm.SetNotReady()
+	pm.SetReady()
+	pm.Shutdown()
+	pm.SetReady() // after shutdown: stays not ready
+
+	if want := []bool{false, true, false, true, false}; !slices.Equal(got(), want) {
+		t.Errorf("updater calls: got %v, want %v", got(), want)
+	}
+}
+
+func TestReadinessGateUpdaterErrorRetried(t *testing.T) {
+	var mu sync.Mutex
+	var calls []bool
+	var reported []error
+	fail := true
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithErrorHandler(func(err error) {
+			mu.Lock()
+			reported = append(reported, err)
+			mu.Unlock()
+		}),
+		podlifecycle.WithReadinessGateUpdater(func(ready bool) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, ready)
+			if fail {
+				fail = false
+				return errors.New("api unavailable")
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+	pm.SetNotReady() // no change, but the failed publish is retried
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []bool{false, false}; !slices.Equal(calls, want) {
+		t.Errorf("updater calls: got %v, want %v", calls, want)
+	}
+	if len(reported) != 1 {
+		t.Errorf("error handler: got %v, want the updater error once", reported)
+	}
+}
+
+func TestReadinessGateUpdaterRetriesWithBackoff(t *testing.T) {
+	clock := newFakeClock()
+	var mu sync.Mutex
+	var calls int
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithClock(clock),
+		podlifecycle.WithErrorHandler(func(error) {}),
+		podlifecycle.WithReadinessGateUpdater(func(bool) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			return errors.New("api unavailable")
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+	// Advance runs the retry callbacks before returning.
+	wantCalls := func(want int) {
+		t.Helper()
+		mu.Lock()
+		defer mu.Unlock()
+		if calls != want {
+			t.Fatalf("updater calls: got %d, want %d", calls, want)
+		}
+	}
+
+	wantCalls(1)
+	// Retries follow after 1s, 2s, 4s, 8s and 16s, then stop.
+	for i, backoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second} {
+		clock.Advance(backoff - time.Millisecond)
+		wantCalls(i + 1)
+		clock.Advance(time.Millisecond)
+		wantCalls(i + 2)
+	}
+	clock.Advance(time.Hour)
+	wantCalls(6)
+}
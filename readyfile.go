@@ -0,0 +1,69 @@
+package podlifecycle
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// writeReadinessFile updates the WithReadinessFile file once the probe has
+// started, skipping writes that would not change it. Once shutdown begins it
+// removes the file instead, and later calls do nothing.
+func (pm *PodManager) writeReadinessFile() {
+	if pm.readinessFile == "" || !pm.started.Load() {
+		return
+	}
+	pm.readyFileMu.Lock()
+	defer pm.readyFileMu.Unlock()
+	if pm.readyFileRemoved {
+		return
+	}
+	if pm.shuttingDown.Load() {
+		pm.readyFileRemoved = true
+		if err := os.Remove(pm.readinessFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			if pm.errorHandler != nil {
+				pm.errorHandler(fmt.Errorf("remove readiness file: %w", err))
+			}
+		}
+		return
+	}
+	content := "not-ready\n"
+	if pm.probeReady() {
+		content = "ready\n"
+	}
+	if content == pm.readyFileContent {
+		return
+	}
+	if err := writeFileAtomic(pm.readinessFile, content); err != nil {
+		if pm.errorHandler != nil {
+			pm.errorHandler(fmt.Errorf("write readiness file: %w", err))
+		}
+		return
+	}
+	pm.readyFileContent = content
+}
+
+// writeFileAtomic replaces path with content through a rename, so readers
+// never see a partial write.
+func writeFileAtomic(path, content string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
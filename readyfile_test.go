@@ -0,0 +1,57 @@
+package podlifecycle_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	podlifecycle "github.com/kroderdev/pod-lifecycle-go"
+)
+
+func TestReadinessFileTracksState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ready")
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithReadinessFile(path),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := func() string {
+		t.Helper()
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read readiness file: %v", err)
+		}
+		return string(b)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("before Start: got %v, want no file", err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	if got := content(); got != "not-ready\n" {
+		t.Errorf("after Start: got %q, want not-ready", got)
+	}
+	pm.SetReady()
+	if got := content(); got != "ready\n" {
+		t.Errorf("after SetReady: got %q, want ready", got)
+	}
+	pm.SetNotReady()
+	if got := content(); got != "not-ready\n" {
+		t.Errorf("after SetNotReady: got %q, want not-ready", got)
+	}
+	pm.SetReady()
+	pm.Shutdown()
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("after Shutdown: got %v, want the file removed", err)
+	}
+	pm.SetReady()
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("SetReady after Shutdown: got %v, want no file", err)
+	}
+}
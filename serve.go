@@ -0,0 +1,70 @@
+package podlifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// appReadHeaderTimeout bounds request header reads on the ServeWithProbes
+// server.
+const appReadHeaderTimeout = 10 * time.Second
+
+// ServeWithProbes serves app and the probe endpoints on one listener at addr,
+// for services too small to warrant a separate probe port. It is an
+// opinionated wrapper over WithExistingHTTPMux: the pod is marked ready as
+// soon as addr is bound (checkers registered with WithChecker still gate
+// /ready), and opts must not select another probe server.
+//
+// It blocks until ctx is cancelled, a shutdown signal arrives, or the server
+// fails. Shutdown then runs as usual, after which the app's in-flight
+// requests drain through http.Server.Shutdown within what is left of the
+// same shutdown timeout. It returns nil after a clean shutdown.
+func ServeWithProbes(ctx context.Context, addr string, app http.Handler, opts ...Option) error {
+	mux := http.NewServeMux()
+	pm, err := NewPodManager(append(slices.Clone(opts), WithExistingHTTPMux(mux))...)
+	if err != nil {
+		return err
+	}
+	mux.Handle("/", app)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	if err := pm.StartAsync(); err != nil {
+		_ = ln.Close()
+		return err
+	}
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: appReadHeaderTimeout}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(ln) }()
+	pm.SetAppListening()
+	pm.SetReady()
+
+	select {
+	case <-ctx.Done():
+	case <-pm.shutdownCh:
+	case err = <-serveErr:
+	}
+	pm.Shutdown()
+	// The app drain shares the manager's shutdown budget, so the whole
+	// shutdown stays within the shutdown timeout.
+	drainCtx, cancel := context.WithTimeout(context.Background(), pm.budgetRemaining())
+	defer cancel()
+	if serr := srv.Shutdown(drainCtx); serr != nil && err == nil {
+		err = serr
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	if err := pm.exitError(); err != nil {
+		return err
+	}
+	if report, ok := pm.LastShutdownReport(); ok && report.ForcedStop {
+		return &ShutdownError{Report: report}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package podlifecycle_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	podlifecycle "github.com/kroderdev/pod-lifecycle-go"
+)
+
+func TestServeWithProbes(t *testing.T) {
+	port := freePort(t)
+	app := http.NewServeMux()
+	app.HandleFunc("/hello", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "hi")
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- podlifecycle.ServeWithProbes(ctx, fmt.Sprintf("127.0.0.1:%d", port), app)
+	}()
+
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+	deadline := time.Now().Add(2 * time.Second)
+	for doGETOrZero(base+"/ready") != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("/ready never returned 200")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	resp, err := http.Get(base + "/hello") //nolint:noctx
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "hi" {
+		t.Errorf("/hello: got %d %q, want 200 \"hi\"", resp.StatusCode, body)
+	}
+	if code := doGET(t, base+"/live"); code != http.StatusOK {
+		t.Errorf("/live: got %d, want 200", code)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ServeWithProbes: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeWithProbes did not return after cancel")
+	}
+}
+
+func TestServeWithProbesRejectsProbePort(t *testing.T) {
+	err := podlifecycle.ServeWithProbes(context.Background(), "127.0.0.1:0", http.NotFoundHandler(), podlifecycle.WithHTTPPort(freePort(t)))
+	if err == nil {
+		t.Error("expected error for WithHTTPPort, got nil")
+	}
+}
+
+// doGETOrZero returns the status of a GET to url, or 0 if the request fails.
+func doGETOrZero(url string) int {
+	resp, err := http.Get(url) //nolint:noctx
+	if err != nil {
+		return 0
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestServeWithProbesShutdownWithinOneTimeout(t *testing.T) {
+	port := freePort(t)
+	entered, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	app := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		close(entered)
+		<-release
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- podlifecycle.ServeWithProbes(ctx, fmt.Sprintf("127.0.0.1:%d", port), app,
+			podlifecycle.WithConfirmNotReady(3), // nothing scrapes /ready, so the drain waits out the timeout
+			podlifecycle.WithShutdownTimeout(time.Second),
+		)
+	}()
+
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+	deadline := time.Now().Add(2 * time.Second)
+	for doGETOrZero(base+"/ready") != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("/ready never returned 200")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	go doGETOrZero(base + "/slow")
+	<-entered
+
+	start := time.Now()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeWithProbes did not return after cancel")
+	}
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Errorf("shutdown took %v, want it within the 1s shutdown timeout", elapsed)
+	}
+}
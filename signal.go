@@ -0,0 +1,82 @@
+package podlifecycle
+
+import (
+	"os"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/config"
+)
+
+// SignalAction is what the manager does when Start receives a signal; see
+// WithSignalAction.
+type SignalAction = config.SignalAction
+
+var (
+	// SignalShutdown begins graceful shutdown, like SIGTERM by default.
+	SignalShutdown = SignalAction{Kind: config.ActionShutdown}
+	// SignalReload runs the hooks registered with RegisterReloadHook.
+	SignalReload = SignalAction{Kind: config.ActionReload}
+	// SignalToggleReady flips readiness: SetReady if not ready, otherwise
+	// back to not ready.
+	SignalToggleReady = SignalAction{Kind: config.ActionToggleReady}
+)
+
+// SignalCustom returns a SignalAction that calls fn.
+func SignalCustom(fn func()) SignalAction {
+	return SignalAction{Kind: config.ActionCustom, Func: fn}
+}
+
+// RegisterReloadHook registers fn to run, in registration order, whenever a
+// signal mapped to SignalReload arrives.
+func (pm *PodManager) RegisterReloadHook(fn func()) {
+	pm.hooksMu.Lock()
+	pm.reloadHooks = append(pm.reloadHooks, fn)
+	pm.hooksMu.Unlock()
+}
+
+// signalsFor returns the signals mapped to one of kinds, or all mapped
+// signals when kinds is empty.
+func (pm *PodManager) signalsFor(kinds ...config.SignalActionKind) []os.Signal {
+	var sigs []os.Signal
+	for sig, a := range pm.signalActions {
+		if len(kinds) == 0 {
+			sigs = append(sigs, sig)
+			continue
+		}
+		for _, k := range kinds {
+			if a.Kind == k {
+				sigs = append(sigs, sig)
+				break
+			}
+		}
+	}
+	return sigs
+}
+
+// dispatchSignal performs the action mapped to sig and reports whether it
+// requested shutdown. Unmapped signals are ignored.
+func (pm *PodManager) dispatchSignal(sig os.Signal) bool {
+	a, ok := pm.signalActions[sig]
+	if !ok {
+		return false
+	}
+	switch a.Kind {
+	case config.ActionShutdown:
+		return true
+	case config.ActionReload:
+		pm.hooksMu.Lock()
+		hooks := pm.reloadHooks
+		pm.hooksMu.Unlock()
+		for _, fn := range hooks {
+			fn()
+		}
+	case config.ActionToggleReady:
+		if pm.ready.Load() {
+			pm.SetNotReady()
+		} else {
+			pm.SetReady()
+		}
+	case config.ActionCustom:
+		a.Func()
+	}
+	return false
+}
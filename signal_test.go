@@ -0,0 +1,120 @@
+//go:build unix
+
+package podlifecycle
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDispatchSignal(t *testing.T) {
+	var reloads, custom int
+	pm, err := NewPodManager(
+		WithSignalAction(syscall.SIGHUP, SignalReload),
+		WithSignalAction(syscall.SIGUSR1, SignalToggleReady),
+		WithSignalAction(syscall.SIGUSR2, SignalCustom(func() { custom++ })),
+		WithSignalAction(syscall.SIGINT, SignalReload),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm.RegisterReloadHook(func() { reloads++ })
+
+	if pm.dispatchSignal(syscall.SIGHUP) || reloads != 1 {
+		t.Errorf("SIGHUP: want one reload and no shutdown, got reloads=%d", reloads)
+	}
+	if pm.dispatchSignal(syscall.SIGINT) || reloads != 2 {
+		t.Errorf("remapped SIGINT: want a second reload and no shutdown, got reloads=%d", reloads)
+	}
+	if pm.dispatchSignal(syscall.SIGUSR1) || !pm.Ready() {
+		t.Error("first SIGUSR1: want ready")
+	}
+	if pm.dispatchSignal(syscall.SIGUSR1) || pm.Ready() {
+		t.Error("second SIGUSR1: want not ready")
+	}
+	if pm.dispatchSignal(syscall.SIGUSR2) || custom != 1 {
+		t.Errorf("SIGUSR2: want one custom call, got %d", custom)
+	}
+	if pm.dispatchSignal(syscall.SIGQUIT) {
+		t.Error("unmapped SIGQUIT: want no shutdown")
+	}
+	if !pm.dispatchSignal(syscall.SIGTERM) {
+		t.Error("SIGTERM: want shutdown by default")
+	}
+}
+
+func TestSignalCustomNilRejected(t *testing.T) {
+	if _, err := NewPodManager(WithSignalAction(syscall.SIGUSR1, SignalCustom(nil))); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("nil custom func: got %v, want ErrInvalidOption", err)
+	}
+}
+
+func TestSecondShutdownSignalForcesStop(t *testing.T) {
+	pm, err := NewPodManager(
+		WithExistingHTTPMux(http.NewServeMux()),
+		WithConfirmNotReady(3), // nothing scrapes /ready, so the drain waits out the timeout
+		WithShutdownTimeout(time.Minute),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := pm.startProbe(); !ok {
+		t.Fatal(err)
+	}
+	sigCh := make(chan os.Signal, 1)
+	go pm.serveUntilShutdown(sigCh)
+
+	sigCh <- syscall.SIGTERM
+	select {
+	case <-pm.Done():
+		t.Fatal("first SIGTERM: shutdown finished without waiting for the drain")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	sigCh <- syscall.SIGINT
+	select {
+	case <-pm.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal: shutdown still draining")
+	}
+	if report, ok := pm.LastShutdownReport(); !ok || report.NotReadyConfirmed {
+		t.Errorf("report: got %+v, want the drain cut short", report)
+	}
+}
+
+func TestNoProbeSignalShutdown(t *testing.T) {
+	var closed []string
+	pm, err := NewPodManager(WithNoProbe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm.RegisterPreDrainHook(func() { closed = append(closed, "pre-drain") })
+	pm.RegisterConnCloser(func() { closed = append(closed, "conn-closer") })
+	if ok, err := pm.startProbe(); !ok {
+		t.Fatal(err)
+	}
+	pm.SetReady()
+	if !pm.Ready() || !pm.Started() {
+		t.Fatalf("after Start and SetReady: ready=%v started=%v, want both", pm.Ready(), pm.Started())
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	go pm.serveUntilShutdown(sigCh)
+	sigCh <- syscall.SIGTERM
+	select {
+	case <-pm.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("SIGTERM did not shut the manager down")
+	}
+	if want := []string{"pre-drain", "conn-closer"}; !slices.Equal(closed, want) {
+		t.Errorf("hooks: got %v, want %v", closed, want)
+	}
+	if report, ok := pm.LastShutdownReport(); !ok || report.ForcedStop {
+		t.Errorf("report: got %+v ok=%v, want a clean shutdown", report, ok)
+	}
+}
@@ -0,0 +1,238 @@
+package podlifecycle
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+// probeState is the check.StateReader handed to the probe. Ready folds in
+// manager-level gates so probes report effective readiness, while
+// PodManager.Ready keeps returning the flag set by SetReady.
+type probeState struct{ pm *PodManager }
+
+func (s probeState) Ready() bool        { return s.pm.probeReady() }
+func (s probeState) ShuttingDown() bool { return s.pm.shuttingDown.Load() }
+func (s probeState) Started() bool      { return s.pm.startupComplete() }
+func (s probeState) Live() bool {
+	return !s.pm.workerFailed.Load() && !s.pm.livenessDrained.Load()
+}
+
+func (s probeState) StartupProgress() (done, total int) {
+	if p := s.pm.startupProgress.Load(); p != nil {
+		return p[0], p[1]
+	}
+	return 0, 0
+}
+
+// startupComplete reports whether the probe has started, any warmup
+// reported through ReportStartupProgress has finished, and, with
+// WithStartupRequiresSetStarted, SetStarted has been called.
+func (pm *PodManager) startupComplete() bool {
+	if !pm.started.Load() {
+		return false
+	}
+	if pm.startupRequiresSet && !pm.appStarted.Load() {
+		return false
+	}
+	if !pm.startupChecksPassed.Load() {
+		return false
+	}
+	p := pm.startupProgress.Load()
+	return p == nil || p[0] >= p[1]
+}
+
+// startupCheckInterval is how often WithStartupCheckers checkers that have
+// not passed yet are retried.
+const startupCheckInterval = 500 * time.Millisecond
+
+// runStartupCheckers polls the WithStartupCheckers checkers until each has
+// passed once, then marks startup checks as passed. A checker removed at
+// runtime counts as not passing; a disabled one as passed.
+func (pm *PodManager) runStartupCheckers(ctx context.Context) {
+	pending := slices.Clone(pm.startupCheckers)
+	for {
+		checkers := pm.checkers.Snapshot()
+		pending = slices.DeleteFunc(pending, func(name string) bool {
+			c, ok := checkers[name]
+			if !ok {
+				return false
+			}
+			if pm.checkers.Disabled(name) {
+				return true
+			}
+			checkCtx, cancel := context.WithTimeout(check.ContextWithClock(ctx, pm.clock), pm.checkerTimeout)
+			err := c.Check(checkCtx)
+			cancel()
+			pm.checkers.Record(name, err, pm.clock.Now())
+			return err == nil
+		})
+		if len(pending) == 0 {
+			pm.startupChecksPassed.Store(true)
+			pm.syncProbe()
+			return
+		}
+		select {
+		case <-pm.clock.After(startupCheckInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// selfTestCheckers runs each registered checker once, concurrently, and logs
+// a warning for every failure or panic. Results are not recorded.
+func (pm *PodManager) selfTestCheckers(ctx context.Context) {
+	var wg sync.WaitGroup
+	for name, c := range pm.checkers.Snapshot() {
+		if pm.checkers.Disabled(name) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := selfTest(check.ContextWithClock(ctx, pm.clock), c, pm.checkerTimeout); err != nil {
+				pm.selfTestLog.Warn("checker self-test failed", "checker", name, "error", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// selfTest runs c once under timeout, turning a panic into an error.
+func selfTest(ctx context.Context, c check.Checker, timeout time.Duration) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return c.Check(ctx)
+}
+
+// ReportStartupProgress records warmup progress: /startup (and gRPC startup)
+// only succeeds once done >= total, and with the default
+// WithReadyRequiresStarted readiness waits for it too. HTTP /startup responses
+// carry the progress in StartupProgressHeader. Call it as warmup advances;
+// before the first call startup depends on the probe alone.
+func (pm *PodManager) ReportStartupProgress(done, total int) {
+	pm.startupProgress.Store(&[2]int{done, total})
+	pm.syncProbe()
+}
+
+// probeReady reports whether the probe should currently report ready.
+func (pm *PodManager) probeReady() bool {
+	if !pm.ready.Load() || pm.draining.Load() || pm.paused.Load() {
+		return false
+	}
+	if pm.readyRequiresStarted && !pm.startupComplete() {
+		return false
+	}
+	if pm.readyRequiresListen && !pm.appListening.Load() {
+		return false
+	}
+	if pm.minUptime > 0 && pm.Uptime() < pm.minUptime {
+		return false
+	}
+	if pm.recoveryHoldDown > 0 && pm.inRecoveryHoldDown() {
+		return false
+	}
+	for _, gate := range pm.readinessGates {
+		if !gate() {
+			return false
+		}
+	}
+	return true
+}
+
+// transition stores v in flag and reports the change, if any, on Events and
+// to the transition audit callback.
+func (pm *PodManager) transition(field string, flag *atomic.Bool, v bool) {
+	old := flag.Swap(v)
+	if old == v {
+		return
+	}
+	now := pm.clock.Now()
+	if typ, ok := eventFor(field, v); ok {
+		pm.emit(typ, now)
+	}
+	if pm.transitionAudit != nil {
+		pm.transitionAudit(TransitionEvent{Field: field, Old: old, New: v, Time: now})
+	}
+}
+
+// syncProbe pushes the current effective state to the probe, the readiness
+// file, the readiness gate updater, and the readiness webhook. HTTP probes
+// read state per request and ignore it; gRPC probes update their health statuses.
+func (pm *PodManager) syncProbe() {
+	pm.probe.SetState(pm.probeReady(), pm.shuttingDown.Load())
+	pm.trackReadySince()
+	pm.writeReadinessFile()
+	pm.publishReadiness()
+	pm.publishWebhook()
+}
+
+// Uptime returns the time since the probe started listening, or zero before Start.
+func (pm *PodManager) Uptime() time.Duration {
+	ns := pm.startedAt.Load()
+	if ns == 0 {
+		return 0
+	}
+	return pm.clock.Now().Sub(time.Unix(0, ns))
+}
+
+// ReadyDuration returns the time since the pod last became ready while
+// serving, or zero while it is not ready. It resets on every transition to
+// not ready, including SetNotReady, draining, and shutdown.
+func (pm *PodManager) ReadyDuration() time.Duration {
+	ns := pm.readySince.Load()
+	if ns == 0 {
+		return 0
+	}
+	return pm.clock.Now().Sub(time.Unix(0, ns))
+}
+
+// trackReadySince records when the effective readiness last turned true.
+func (pm *PodManager) trackReadySince() {
+	if !pm.started.Load() || !pm.probeReady() || pm.shuttingDown.Load() {
+		pm.readySince.Store(0)
+		return
+	}
+	pm.readySince.CompareAndSwap(0, pm.clock.Now().UnixNano())
+}
+
+// Status is a point-in-time snapshot of a PodManager, as served by
+// WithStatusEndpoint.
+type Status struct {
+	Ready        bool              `json:"ready"`
+	ShuttingDown bool              `json:"shuttingDown"`
+	Draining     bool              `json:"draining"`
+	Paused       bool              `json:"paused"`
+	Started      bool              `json:"started"`
+	Serving      bool              `json:"serving"`
+	Uptime       string            `json:"uptime"`
+	ReadyFor     string            `json:"readyFor"`
+	Checkers     map[string]string `json:"checkers"`
+}
+
+// Status returns the manager's current state and the last checker results.
+// Ready is the effective readiness the probes report.
+func (pm *PodManager) Status() Status {
+	return Status{
+		Ready:        pm.probeReady(),
+		ShuttingDown: pm.shuttingDown.Load(),
+		Draining:     pm.draining.Load(),
+		Paused:       pm.paused.Load(),
+		Started:      pm.started.Load(),
+		Serving:      pm.serving.Load(),
+		Uptime:       pm.Uptime().String(),
+		ReadyFor:     pm.ReadyDuration().String(),
+		Checkers:     pm.LastCheckResults(),
+	}
+}
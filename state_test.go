@@ -0,0 +1,39 @@
+package podlifecycle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kroderdev/pod-lifecycle-go/internal/check"
+)
+
+// TestReadyRequiresStarted serves the probe handlers without marking startup
+// complete, so ready=true, started=false can be observed over HTTP.
+func TestReadyRequiresStarted(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{"default", nil, http.StatusServiceUnavailable},
+		{"disabled", []Option{WithReadyRequiresStarted(false)}, http.StatusOK},
+	} {
+		pm, err := NewPodManager(tc.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pm.SetReady()
+
+		mux := http.NewServeMux()
+		probe := check.NewExistingHTTPProbe(mux, check.HTTPOptions{})
+		if err := probe.Start(probeState{pm}, func() {}); err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		if rec.Code != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, rec.Code, tc.want)
+		}
+	}
+}
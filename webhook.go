@@ -0,0 +1,134 @@
+package podlifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// webhookTimeout bounds each WithReadinessWebhook delivery.
+	webhookTimeout = 5 * time.Second
+	// webhookBuffer is how many deliveries may wait for the worker before
+	// new ones are dropped.
+	webhookBuffer = 16
+)
+
+// webhookPayload is the body POSTed by WithReadinessWebhook.
+type webhookPayload struct {
+	Pod    string    `json:"pod"`
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+}
+
+// webhook delivers WithReadinessWebhook payloads from a single background
+// worker, in order.
+type webhook struct {
+	url    string
+	pod    string
+	client *http.Client
+	queue  chan webhookPayload
+	once   sync.Once // starts the worker
+
+	mu   sync.Mutex
+	last string // status most recently queued
+}
+
+func newWebhook(url string) *webhook {
+	if url == "" {
+		return nil
+	}
+	pod, _ := os.Hostname()
+	return &webhook{
+		url:    url,
+		pod:    pod,
+		client: &http.Client{Timeout: webhookTimeout},
+		queue:  make(chan webhookPayload, webhookBuffer),
+	}
+}
+
+// publishWebhook queues a WithReadinessWebhook delivery once the probe has
+// started and whenever the pod's status changes. It never blocks: when the
+// queue is full the payload is dropped and reported to the error handler.
+func (pm *PodManager) publishWebhook() {
+	w := pm.webhook
+	if w == nil || !pm.started.Load() {
+		return
+	}
+	status := "not-ready"
+	switch {
+	case pm.shuttingDown.Load():
+		status = "shutting-down"
+	case pm.probeReady():
+		status = "ready"
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if status == w.last {
+		return
+	}
+	w.last = status
+	w.once.Do(func() { go pm.runWebhook() })
+	select {
+	case w.queue <- webhookPayload{Pod: w.pod, Status: status, Time: pm.clock.Now()}:
+	default:
+		if pm.errorHandler != nil {
+			pm.errorHandler(fmt.Errorf("readiness webhook: queue full, dropped %q", status))
+		}
+	}
+}
+
+// runWebhook delivers queued payloads until shutdown has completed, then
+// sends what is left and exits.
+func (pm *PodManager) runWebhook() {
+	w := pm.webhook
+	for {
+		select {
+		case p := <-w.queue:
+			pm.deliverWebhook(p)
+		case <-pm.doneCh:
+			for {
+				select {
+				case p := <-w.queue:
+					pm.deliverWebhook(p)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (pm *PodManager) deliverWebhook(p webhookPayload) {
+	if err := pm.webhook.post(p); err != nil && pm.errorHandler != nil {
+		pm.errorHandler(fmt.Errorf("readiness webhook: %w", err))
+	}
+}
+
+func (w *webhook) post(p webhookPayload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("POST %s: %s", w.url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package podlifecycle_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	podlifecycle "github.com/kroderdev/pod-lifecycle-go"
+)
+
+type webhookBody struct {
+	Pod    string    `json:"pod"`
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+}
+
+func TestReadinessWebhookTransitions(t *testing.T) {
+	bodies := make(chan webhookBody, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var b webhookBody
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request: got %s with Content-Type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		bodies <- b
+	}))
+	defer srv.Close()
+
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithReadinessWebhook(srv.URL),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm.SetReady() // before Start: not published
+	pm.SetNotReady()
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	pm.SetReady()
+	pm.SetReady() // no transition
+	pm.SetNotReady()
+	pm.SetReady()
+	pm.Shutdown()
+
+	want := []string{"not-ready", "ready", "not-ready", "ready", "shutting-down"}
+	var got []string
+	host, _ := os.Hostname()
+	for range want {
+		select {
+		case b := <-bodies:
+			got = append(got, b.Status)
+			if b.Pod != host || b.Time.IsZero() {
+				t.Errorf("payload: got pod %q time %v, want pod %q and a time", b.Pod, b.Time, host)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("webhook statuses: got %v, want %v", got, want)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("webhook statuses: got %v, want %v", got, want)
+	}
+}
+
+func TestReadinessWebhookErrorReported(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	errs := make(chan error, 4)
+	pm, err := podlifecycle.NewPodManager(
+		podlifecycle.WithHTTPPort(freePort(t)),
+		podlifecycle.WithReadinessWebhook(srv.URL),
+		podlifecycle.WithErrorHandler(func(err error) { errs <- err }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.StartAsync(); err != nil {
+		t.Fatal(err)
+	}
+	defer pm.Shutdown()
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "502") {
+			t.Errorf("error: got %v, want the 502 status", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("webhook failure not reported to the error handler")
+	}
+}
+
+func TestReadinessWebhookInvalidURL(t *testing.T) {
+	for _, url := range []string{"not a url", "ftp://example.com/hook", "/relative"} {
+		if _, err := podlifecycle.NewPodManager(podlifecycle.WithReadinessWebhook(url)); !errors.Is(err, podlifecycle.ErrInvalidOption) {
+			t.Errorf("%q: got %v, want ErrInvalidOption", url, err)
+		}
+	}
+}